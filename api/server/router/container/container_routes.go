@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"syscall"
 
+	"github.com/containerd/containerd/platforms"
 	"github.com/docker/docker/api/server/httputils"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/backend"
@@ -19,6 +20,8 @@ import (
 	"github.com/docker/docker/errdefs"
 	"github.com/docker/docker/pkg/ioutils"
 	"github.com/docker/docker/pkg/signal"
+	"github.com/docker/docker/pkg/system"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/net/websocket"
@@ -130,6 +133,11 @@ func (s *containerRouter) getContainersLogs(ctx context.Context, w http.Response
 		return errdefs.InvalidParameter(errors.New("Bad parameters: you must choose at least one stream"))
 	}
 
+	logFilters, err := filters.FromJSON(r.Form.Get("filters"))
+	if err != nil {
+		return err
+	}
+
 	containerName := vars["name"]
 	logsConfig := &types.ContainerLogsOptions{
 		Follow:     httputils.BoolValue(r, "follow"),
@@ -140,6 +148,8 @@ func (s *containerRouter) getContainersLogs(ctx context.Context, w http.Response
 		ShowStdout: stdout,
 		ShowStderr: stderr,
 		Details:    httputils.BoolValue(r, "details"),
+		Grep:       r.Form.Get("grep"),
+		Filters:    logFilters,
 	}
 
 	msgs, tty, err := s.backend.ContainerLogs(ctx, containerName, logsConfig)
@@ -468,6 +478,14 @@ func (s *containerRouter) postContainersCreate(ctx context.Context, w http.Respo
 			if bo := m.BindOptions; bo != nil {
 				bo.NonRecursive = false
 			}
+			// Ignore VolumeOptions.Subpath and the populate options because
+			// they were added in API 1.40.
+			if vo := m.VolumeOptions; vo != nil {
+				vo.Subpath = ""
+				vo.Populate = ""
+				vo.PopulateOwner = ""
+				vo.PopulateMode = ""
+			}
 		}
 		// Ignore KernelMemoryTCP because it was added in API 1.40.
 		hostConfig.KernelMemoryTCP = 0
@@ -478,11 +496,26 @@ func (s *containerRouter) postContainersCreate(ctx context.Context, w http.Respo
 		hostConfig.Capabilities = nil
 	}
 
+	var platform *specs.Platform
+	if versions.GreaterThanOrEqualTo(version, "1.40") {
+		if v := r.Form.Get("platform"); v != "" {
+			p, err := platforms.Parse(v)
+			if err != nil {
+				return errdefs.InvalidParameter(err)
+			}
+			if err := system.ValidatePlatform(p); err != nil {
+				return errdefs.InvalidParameter(err)
+			}
+			platform = &p
+		}
+	}
+
 	ccr, err := s.backend.ContainerCreate(types.ContainerCreateConfig{
 		Name:             name,
 		Config:           config,
 		HostConfig:       hostConfig,
 		NetworkingConfig: networkingConfig,
+		Platform:         platform,
 		AdjustCPUShares:  adjustCPUShares,
 	})
 	if err != nil {
@@ -2,6 +2,7 @@ package volume // import "github.com/docker/docker/api/server/router/volume"
 
 import (
 	"context"
+	"io"
 
 	"github.com/docker/docker/volume/service/opts"
 	// TODO return types need to be refactored into pkg
@@ -17,4 +18,7 @@ type Backend interface {
 	Create(ctx context.Context, name, driverName string, opts ...opts.CreateOption) (*types.Volume, error)
 	Remove(ctx context.Context, name string, opts ...opts.RemoveOption) error
 	Prune(ctx context.Context, pruneFilters filters.Args) (*types.VolumesPruneReport, error)
+	Export(ctx context.Context, name string) (io.ReadCloser, error)
+	Import(ctx context.Context, content io.Reader, name, driverName string, opts ...opts.CreateOption) (*types.Volume, error)
+	Clone(ctx context.Context, name, cloneName string, opts ...opts.CreateOption) (*types.Volume, error)
 }
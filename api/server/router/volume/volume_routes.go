@@ -10,6 +10,7 @@ import (
 	"github.com/docker/docker/api/types/filters"
 	volumetypes "github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/ioutils"
 	"github.com/docker/docker/volume/service/opts"
 	"github.com/pkg/errors"
 )
@@ -94,3 +95,72 @@ func (v *volumeRouter) postVolumesPrune(ctx context.Context, w http.ResponseWrit
 	}
 	return httputils.WriteJSON(w, http.StatusOK, pruneReport)
 }
+
+func (v *volumeRouter) getVolumeExport(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+
+	rdr, err := v.backend.Export(ctx, vars["name"])
+	if err != nil {
+		return err
+	}
+	defer rdr.Close()
+
+	w.Header().Set("Content-Type", "application/x-tar")
+	output := ioutils.NewWriteFlusher(w)
+	defer output.Close()
+	_, err = io.Copy(output, rdr)
+	return err
+}
+
+func (v *volumeRouter) postVolumesImport(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+
+	var driverOpts, labels map[string]string
+	if driverOptsJSON := r.Form.Get("driveropts"); driverOptsJSON != "" {
+		if err := json.Unmarshal([]byte(driverOptsJSON), &driverOpts); err != nil {
+			return errdefs.InvalidParameter(errors.Wrap(err, "error reading driver options"))
+		}
+	}
+	if labelsJSON := r.Form.Get("labels"); labelsJSON != "" {
+		if err := json.Unmarshal([]byte(labelsJSON), &labels); err != nil {
+			return errdefs.InvalidParameter(errors.Wrap(err, "error reading labels"))
+		}
+	}
+
+	volume, err := v.backend.Import(ctx, r.Body, r.Form.Get("name"), r.Form.Get("driver"), opts.WithCreateOptions(driverOpts), opts.WithCreateLabels(labels))
+	if err != nil {
+		return err
+	}
+	return httputils.WriteJSON(w, http.StatusCreated, volume)
+}
+
+func (v *volumeRouter) postVolumeClone(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+
+	if err := httputils.CheckForJSON(r); err != nil {
+		return err
+	}
+
+	var req volumetypes.VolumeCreateBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		if err == io.EOF {
+			return errdefs.InvalidParameter(errors.New("got EOF while reading request body"))
+		}
+		return errdefs.InvalidParameter(err)
+	}
+	if req.Driver != "" {
+		return errdefs.InvalidParameter(errors.New("the driver of a clone cannot be set, it is the driver of the cloned volume"))
+	}
+
+	volume, err := v.backend.Clone(ctx, vars["name"], req.Name, opts.WithCreateOptions(req.DriverOpts), opts.WithCreateLabels(req.Labels))
+	if err != nil {
+		return err
+	}
+	return httputils.WriteJSON(w, http.StatusCreated, volume)
+}
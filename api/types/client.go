@@ -79,6 +79,11 @@ type ContainerLogsOptions struct {
 	Follow     bool
 	Tail       string
 	Details    bool
+	// Grep is a regular expression the returned lines must match.
+	Grep string
+	// Filters selects the returned lines by their attributes, using
+	// "attr=key" or "attr=key=value" filters.
+	Filters filters.Args
 }
 
 // ContainerRemoveOptions holds parameters to remove containers.
@@ -256,8 +261,26 @@ type ImagePullOptions struct {
 // if the privilege request fails.
 type RequestPrivilegeFunc func() (string, error)
 
-//ImagePushOptions holds information to push images.
-type ImagePushOptions ImagePullOptions
+// ImagePushOptions holds information to push images.
+type ImagePushOptions struct {
+	All           bool
+	RegistryAuth  string // RegistryAuth is the base64 encoded credentials for the registry
+	PrivilegeFunc RequestPrivilegeFunc
+	Platform      string
+	// MountFrom holds repositories, in the registry the image is pushed
+	// to, which layers are mounted from if they exist there.
+	MountFrom []string
+}
+
+// ManifestListPushOptions holds parameters to push a manifest list.
+type ManifestListPushOptions struct {
+	// MediaType is the media type of the manifest list, a Docker manifest
+	// list if it is not set, or an OCI image index.
+	MediaType     string
+	Manifests     []ManifestListEntry
+	RegistryAuth  string // RegistryAuth is the base64 encoded credentials for the registry
+	PrivilegeFunc RequestPrivilegeFunc
+}
 
 // ImageRemoveOptions holds parameters to remove images.
 type ImageRemoveOptions struct {
@@ -265,6 +288,21 @@ type ImageRemoveOptions struct {
 	PruneChildren bool
 }
 
+// Formats of the archives of saved images.
+const (
+	// ImageSaveFormatDocker is the default format, holding a manifest.json
+	// file and the layers as uncompressed tarballs.
+	ImageSaveFormatDocker = "docker"
+	// ImageSaveFormatOCI is the OCI image layout format, holding an
+	// index.json file and the layers as compressed blobs.
+	ImageSaveFormatOCI = "oci"
+)
+
+// ImageSaveOptions holds parameters to save images.
+type ImageSaveOptions struct {
+	Format string // Format is the format of the archive, ImageSaveFormatDocker (default) or ImageSaveFormatOCI
+}
+
 // ImageSearchOptions holds parameters to search images with.
 type ImageSearchOptions struct {
 	RegistryAuth  string
@@ -22,6 +22,8 @@ type HealthConfig struct {
 	// {"NONE"} : disable healthcheck
 	// {"CMD", args...} : exec arguments directly
 	// {"CMD-SHELL", command} : run command with system's default shell
+	// {"HTTP", url, [expectedStatus]} : send an HTTP GET request from the container's network namespace
+	// {"TCP", port} or {"TCP", host:port} : open a TCP connection from the container's network namespace
 	Test []string `json:",omitempty"`
 
 	// Zero means to inherit. Durations are expressed as integer nanoseconds.
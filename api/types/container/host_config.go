@@ -2,6 +2,7 @@ package container // import "github.com/docker/docker/api/types/container"
 
 import (
 	"strings"
+	"time"
 
 	"github.com/docker/docker/api/types/blkiodev"
 	"github.com/docker/docker/api/types/mount"
@@ -255,6 +256,21 @@ type DeviceMapping struct {
 type RestartPolicy struct {
 	Name              string
 	MaximumRetryCount int
+
+	// Backoff settings. Zero means use the daemon's default.
+	Delay      time.Duration `json:",omitempty"` // Delay is the delay before the first restart.
+	Multiplier float64       `json:",omitempty"` // Multiplier is the factor applied to the delay after each restart.
+	MaxDelay   time.Duration `json:",omitempty"` // MaxDelay is the maximum delay between two restarts.
+	ResetAfter time.Duration `json:",omitempty"` // ResetAfter is how long the container must run for the delay to be reset.
+
+	// MaxAttempts is the maximum number of restarts within Window, after
+	// which the container is no longer restarted. Zero means no limit.
+	MaxAttempts int           `json:",omitempty"`
+	Window      time.Duration `json:",omitempty"` // Window is the time window used to evaluate MaxAttempts.
+
+	// OnUnhealthy stops the container when its healthcheck reports it as
+	// unhealthy, so that it is restarted according to the policy.
+	OnUnhealthy bool `json:",omitempty"`
 }
 
 // IsNone indicates whether the container has the "no" restart policy.
@@ -284,7 +300,7 @@ func (rp *RestartPolicy) IsUnlessStopped() bool {
 
 // IsSame compares two RestartPolicy to see if they are the same
 func (rp *RestartPolicy) IsSame(tp *RestartPolicy) bool {
-	return rp.Name == tp.Name && rp.MaximumRetryCount == tp.MaximumRetryCount
+	return *rp == *tp
 }
 
 // LogMode is a type to define the available modes for logging
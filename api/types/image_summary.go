@@ -23,6 +23,10 @@ type ImageSummary struct {
 	// Required: true
 	Labels map[string]string `json:"Labels"`
 
+	// last used
+	// Required: true
+	LastUsed int64 `json:"LastUsed"`
+
 	// parent Id
 	// Required: true
 	ParentID string `json:"ParentId"`
@@ -77,6 +77,22 @@ const (
 	ConsistencyDefault Consistency = "default"
 )
 
+// Populate represents when a volume is populated with the content of the
+// image at the mount target.
+type Populate string
+
+const (
+	// PopulateIfEmpty populates the volume only if it is empty, when a
+	// container using it is created. This is the default.
+	PopulateIfEmpty Populate = "if-empty"
+	// PopulateAlways populates the volume with the files of the image it does
+	// not have yet, each time a container using it is created. Files already
+	// in the volume are left untouched.
+	PopulateAlways Populate = "always"
+	// PopulateNever never populates the volume.
+	PopulateNever Populate = "never"
+)
+
 // BindOptions defines options specific to mounts of type "bind".
 type BindOptions struct {
 	Propagation  Propagation `json:",omitempty"`
@@ -88,6 +104,19 @@ type VolumeOptions struct {
 	NoCopy       bool              `json:",omitempty"`
 	Labels       map[string]string `json:",omitempty"`
 	DriverConfig *Driver           `json:",omitempty"`
+	// Subpath is the path of a directory within the volume to mount instead
+	// of the root of the volume.
+	Subpath string `json:",omitempty"`
+	// Populate controls when the volume is populated with the content of the
+	// image at the mount target.
+	Populate Populate `json:",omitempty"`
+	// PopulateOwner is the "uid:gid" to set as the owner of the volume root
+	// and of the files copied when populating the volume. The ids are ids in
+	// the container, mapped to host ids if user namespaces are enabled.
+	PopulateOwner string `json:",omitempty"`
+	// PopulateMode is the octal permission bits, e.g. "0750", to set on the
+	// volume root when populating the volume.
+	PopulateMode string `json:",omitempty"`
 }
 
 // Driver represents a volume driver.
@@ -15,6 +15,7 @@ import (
 	"github.com/docker/docker/api/types/registry"
 	"github.com/docker/docker/api/types/swarm"
 	"github.com/docker/go-connections/nat"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
 // RootFS returns Image's RootFS description including the layer IDs.
@@ -50,7 +51,8 @@ type ImageInspect struct {
 
 // ImageMetadata contains engine-local data about the image
 type ImageMetadata struct {
-	LastTagTime time.Time `json:",omitempty"`
+	LastTagTime  time.Time `json:",omitempty"`
+	LastUsedTime time.Time `json:",omitempty"`
 }
 
 // Container contains response of Engine API:
@@ -315,6 +317,14 @@ type ContainerState struct {
 	Health     *Health `json:",omitempty"`
 }
 
+// RestartBackoff stores the current state of the restart backoff of a container
+// it's part of ContainerJSONBase and will return by "inspect" command
+type RestartBackoff struct {
+	Delay          time.Duration // Delay is the delay applied before the last (or pending) restart
+	RecentRestarts int           // RecentRestarts is the number of restarts within the restart policy's window
+	GaveUp         bool          // GaveUp indicates the restart policy's maximum number of restarts within its window was reached
+}
+
 // ContainerNode stores information about the node that a container
 // is running on.  It's only available in Docker Swarm
 type ContainerNode struct {
@@ -343,6 +353,7 @@ type ContainerJSONBase struct {
 	Node            *ContainerNode `json:",omitempty"`
 	Name            string
 	RestartCount    int
+	RestartBackoff  *RestartBackoff `json:",omitempty"`
 	Driver          string
 	Platform        string
 	MountLabel      string
@@ -588,6 +599,37 @@ type PushResult struct {
 	Size   int
 }
 
+// PushStats contains the number and size of the layers of an image which
+// were uploaded, mounted from another repository, or skipped because they
+// already existed in the registry, during a push. It is sent as an aux
+// message before the PushResult of each tag.
+type PushStats struct {
+	UploadedLayers int
+	UploadedBytes  int64
+	MountedLayers  int
+	MountedBytes   int64
+	SkippedLayers  int
+	SkippedBytes   int64
+}
+
+// ManifestListPushRequest contains the images of a manifest list pushed by
+// POST "/images/{name:.*}/manifest-list"
+type ManifestListPushRequest struct {
+	// MediaType is the media type of the manifest list, a Docker manifest
+	// list if it is not set, or an OCI image index.
+	MediaType string `json:",omitempty"`
+	Manifests []ManifestListEntry
+}
+
+// ManifestListEntry is a local image pushed as part of a manifest list.
+type ManifestListEntry struct {
+	// Image is the name or ID of the image.
+	Image string
+	// Platform overrides the platform of the image in the manifest list.
+	// Only its fields which are set override the ones of the image.
+	Platform *specs.Platform `json:",omitempty"`
+}
+
 // BuildResult contains the image id of a successful build
 type BuildResult struct {
 	ID string
@@ -18,6 +18,12 @@ type Volume struct {
 	// Required: true
 	Labels map[string]string `json:"Labels"`
 
+	// Date/Time the volume was last mounted or unmounted.
+	LastUsedAt string `json:"LastUsedAt,omitempty"`
+
+	// ID of the last container that used the volume.
+	LastUsedBy string `json:"LastUsedBy,omitempty"`
+
 	// Mount path of the volume on the host.
 	// Required: true
 	Mountpoint string `json:"Mountpoint"`
@@ -26,6 +32,10 @@ type Volume struct {
 	// Required: true
 	Name string `json:"Name"`
 
+	// Details about the last time the volume was populated with the
+	// content of an image.
+	Population *VolumePopulation `json:"Population,omitempty"`
+
 	// The driver specific options used when creating the volume.
 	// Required: true
 	Options map[string]string `json:"Options"`
@@ -66,4 +76,39 @@ type VolumeUsageData struct {
 	//
 	// Required: true
 	Size int64 `json:"Size"`
+
+	// The size limit of the volume (in bytes) if it was created with the
+	// `size` option of the `"local"` volume driver, in which case `Size`
+	// reports the disk space used against this limit.
+	Quota int64 `json:"Quota,omitempty"`
+}
+
+// VolumePopulation Details about the last time a volume was populated with the
+// content of an image, when a container using it was created.
+//
+// swagger:model VolumePopulation
+type VolumePopulation struct {
+
+	// Date/Time the volume was populated.
+	At string `json:"At"`
+
+	// ID of the container the volume was populated for.
+	Container string `json:"Container"`
+
+	// Whether the content of the image was copied to the volume. This is
+	// `false` if the image has no content at the mount target, or if the
+	// volume was not empty and `Populate` was `if-empty`.
+	Copied bool `json:"Copied"`
+
+	// ID of the image the volume was populated from.
+	Image string `json:"Image"`
+
+	// The octal permission bits set on the volume root.
+	Mode string `json:"Mode,omitempty"`
+
+	// The "uid:gid" set as the owner of the volume root and the copied files.
+	Owner string `json:"Owner,omitempty"`
+
+	// When the volume is populated, either `if-empty` or `always`.
+	Populate string `json:"Populate"`
 }
@@ -6,9 +6,11 @@ import (
 	"net/url"
 	"strings"
 
+	"github.com/containerd/containerd/platforms"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/api/types/versions"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
 type configWrapper struct {
@@ -20,8 +22,21 @@ type configWrapper struct {
 // ContainerCreate creates a new container based in the given configuration.
 // It can be associated with a name, but it's not mandatory.
 func (cli *Client) ContainerCreate(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, containerName string) (container.ContainerCreateCreatedBody, error) {
+	return cli.ContainerCreateWithPlatform(ctx, config, hostConfig, networkingConfig, nil, containerName)
+}
+
+// ContainerCreateWithPlatform creates a new container based in the given
+// configuration, from the image of the given platform. It can be associated
+// with a name, but it's not mandatory.
+func (cli *Client) ContainerCreateWithPlatform(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, platform *specs.Platform, containerName string) (container.ContainerCreateCreatedBody, error) {
 	var response container.ContainerCreateCreatedBody
 
+	if platform != nil {
+		if err := cli.NewVersionError("1.40", "platform"); err != nil {
+			return response, err
+		}
+	}
+
 	if err := cli.NewVersionError("1.25", "stop timeout"); config != nil && config.StopTimeout != nil && err != nil {
 		return response, err
 	}
@@ -35,6 +50,9 @@ func (cli *Client) ContainerCreate(ctx context.Context, config *container.Config
 	if containerName != "" {
 		query.Set("name", containerName)
 	}
+	if platform != nil {
+		query.Set("platform", platforms.Format(*platform))
+	}
 
 	body := configWrapper{
 		Config:           config,
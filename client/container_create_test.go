@@ -11,6 +11,7 @@ import (
 	"testing"
 
 	"github.com/docker/docker/api/types/container"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
 func TestContainerCreateError(t *testing.T) {
@@ -75,6 +76,41 @@ func TestContainerCreateWithName(t *testing.T) {
 	}
 }
 
+func TestContainerCreateWithPlatform(t *testing.T) {
+	client := &Client{
+		version: "1.40",
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if platform := req.URL.Query().Get("platform"); platform != "linux/arm64" {
+				return nil, fmt.Errorf("platform not set in URL query properly. Expected `linux/arm64`, got %s", platform)
+			}
+			b, err := json.Marshal(container.ContainerCreateCreatedBody{
+				ID: "container_id",
+			})
+			if err != nil {
+				return nil, err
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewReader(b)),
+			}, nil
+		}),
+	}
+
+	r, err := client.ContainerCreateWithPlatform(context.Background(), nil, nil, nil, &specs.Platform{OS: "linux", Architecture: "arm64"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.ID != "container_id" {
+		t.Fatalf("expected `container_id`, got %s", r.ID)
+	}
+
+	client.version = "1.39"
+	_, err = client.ContainerCreateWithPlatform(context.Background(), nil, nil, nil, &specs.Platform{OS: "linux", Architecture: "arm64"}, "")
+	if err == nil || !strings.Contains(err.Error(), "platform") {
+		t.Fatalf("expected a version error, got %v", err)
+	}
+}
+
 // TestContainerCreateAutoRemove validates that a client using API 1.24 always disables AutoRemove. When using API 1.25
 // or up, AutoRemove should not be disabled.
 func TestContainerCreateAutoRemove(t *testing.T) {
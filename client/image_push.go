@@ -34,6 +34,12 @@ func (cli *Client) ImagePush(ctx context.Context, image string, options types.Im
 
 	query := url.Values{}
 	query.Set("tag", tag)
+	if len(options.MountFrom) > 0 {
+		if err := cli.NewVersionError("1.40", "mount-from"); err != nil {
+			return nil, err
+		}
+		query["mount-from"] = options.MountFrom
+	}
 
 	resp, err := cli.tryImagePush(ctx, name, query, options.RegistryAuth)
 	if resp.statusCode == http.StatusUnauthorized && options.PrivilegeFunc != nil {
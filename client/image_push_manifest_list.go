@@ -0,0 +1,61 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/docker/distribution/reference"
+	"github.com/docker/docker/api/types"
+)
+
+// ImagePushManifestList requests the docker host to push the local images of
+// options to a remote registry, and then a manifest list of these images
+// tagged as ref.
+// It executes the privileged function if the operation is unauthorized
+// and it tries one more time.
+// It's up to the caller to handle the io.ReadCloser and close it properly.
+func (cli *Client) ImagePushManifestList(ctx context.Context, ref string, options types.ManifestListPushOptions) (io.ReadCloser, error) {
+	if err := cli.NewVersionError("1.40", "manifest list push"); err != nil {
+		return nil, err
+	}
+
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, isCanonical := named.(reference.Canonical); isCanonical {
+		return nil, errors.New("cannot push a manifest list by digest")
+	}
+
+	query := url.Values{}
+	if tagged, isTagged := named.(reference.NamedTagged); isTagged {
+		query.Set("tag", tagged.Tag())
+	}
+	name := reference.FamiliarName(named)
+	body := types.ManifestListPushRequest{
+		MediaType: options.MediaType,
+		Manifests: options.Manifests,
+	}
+
+	resp, err := cli.tryImagePushManifestList(ctx, name, query, body, options.RegistryAuth)
+	if resp.statusCode == http.StatusUnauthorized && options.PrivilegeFunc != nil {
+		newAuthHeader, privilegeErr := options.PrivilegeFunc()
+		if privilegeErr != nil {
+			return nil, privilegeErr
+		}
+		resp, err = cli.tryImagePushManifestList(ctx, name, query, body, newAuthHeader)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return resp.body, nil
+}
+
+func (cli *Client) tryImagePushManifestList(ctx context.Context, name string, query url.Values, body types.ManifestListPushRequest, registryAuth string) (serverResponse, error) {
+	headers := map[string][]string{"X-Registry-Auth": {registryAuth}}
+	return cli.post(ctx, "/images/"+name+"/manifest-list", query, body, headers)
+}
@@ -0,0 +1,90 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestImagePushManifestListReferenceError(t *testing.T) {
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			return nil, nil
+		}),
+	}
+	_, err := client.ImagePushManifestList(context.Background(), "", types.ManifestListPushOptions{})
+	assert.Check(t, is.ErrorContains(err, "invalid reference format"))
+	_, err = client.ImagePushManifestList(context.Background(), "repo@sha256:ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff", types.ManifestListPushOptions{})
+	assert.Check(t, is.Error(err, "cannot push a manifest list by digest"))
+}
+
+func TestImagePushManifestListVersionError(t *testing.T) {
+	client := &Client{
+		version: "1.39",
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			return nil, nil
+		}),
+	}
+	_, err := client.ImagePushManifestList(context.Background(), "myimage", types.ManifestListPushOptions{})
+	assert.Check(t, is.ErrorContains(err, `"manifest list push" requires API version 1.40`))
+}
+
+func TestImagePushManifestList(t *testing.T) {
+	manifests := []types.ManifestListEntry{
+		{Image: "myimage:amd64"},
+		{Image: "myimage:arm64", Platform: &specs.Platform{Variant: "v8"}},
+	}
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path != "/images/example.com/myimage/manifest-list" {
+				return nil, fmt.Errorf("unexpected URL '%s'", req.URL)
+			}
+			auth := req.Header.Get("X-Registry-Auth")
+			if auth == "NotValid" {
+				return &http.Response{
+					StatusCode: http.StatusUnauthorized,
+					Body:       ioutil.NopCloser(bytes.NewReader([]byte("Invalid credentials"))),
+				}, nil
+			}
+			if auth != "IAmValid" {
+				return nil, fmt.Errorf("invalid auth header: expected IAmValid, got %s", auth)
+			}
+			if tag := req.URL.Query().Get("tag"); tag != "multi" {
+				return nil, fmt.Errorf("expected tag multi, got %s", tag)
+			}
+			var body types.ManifestListPushRequest
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+				return nil, err
+			}
+			if body.MediaType != specs.MediaTypeImageIndex || len(body.Manifests) != 2 || body.Manifests[1].Platform.Variant != "v8" {
+				return nil, fmt.Errorf("unexpected body %+v", body)
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(strings.NewReader("hello world")),
+			}, nil
+		}),
+	}
+	resp, err := client.ImagePushManifestList(context.Background(), "example.com/myimage:multi", types.ManifestListPushOptions{
+		MediaType:    specs.MediaTypeImageIndex,
+		Manifests:    manifests,
+		RegistryAuth: "NotValid",
+		PrivilegeFunc: func() (string, error) {
+			return "IAmValid", nil
+		},
+	})
+	assert.NilError(t, err)
+	body, err := ioutil.ReadAll(resp)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(body), "hello world"))
+}
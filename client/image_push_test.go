@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -177,3 +178,32 @@ func TestImagePushWithoutErrors(t *testing.T) {
 		}
 	}
 }
+
+func TestImagePushMountFrom(t *testing.T) {
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			mountFrom := req.URL.Query()["mount-from"]
+			if !reflect.DeepEqual(mountFrom, []string{"example.com/base", "example.com/other"}) {
+				return nil, fmt.Errorf("mount-from not set in URL query properly, got %v", mountFrom)
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewReader([]byte("hello world"))),
+			}, nil
+		}),
+	}
+	_, err := client.ImagePush(context.Background(), "example.com/myimage", types.ImagePushOptions{
+		MountFrom: []string{"example.com/base", "example.com/other"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client.version = "1.39"
+	_, err = client.ImagePush(context.Background(), "example.com/myimage", types.ImagePushOptions{
+		MountFrom: []string{"example.com/base"},
+	})
+	if err == nil || !strings.Contains(err.Error(), `"mount-from" requires API version 1.40`) {
+		t.Fatalf("expected a version error, got %v", err)
+	}
+}
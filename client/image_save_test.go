@@ -9,6 +9,8 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/docker/docker/api/types"
 )
 
 func TestImageSaveError(t *testing.T) {
@@ -54,3 +56,22 @@ func TestImageSave(t *testing.T) {
 		t.Fatalf("expected response to contain 'response', got %s", string(response))
 	}
 }
+
+func TestImageSaveWithOptions(t *testing.T) {
+	client := &Client{
+		client: newMockClient(func(r *http.Request) (*http.Response, error) {
+			if format := r.URL.Query().Get("format"); format != types.ImageSaveFormatOCI {
+				return nil, fmt.Errorf("format not set in URL query properly. Expected %q, got %q", types.ImageSaveFormatOCI, format)
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewReader([]byte("response"))),
+			}, nil
+		}),
+	}
+	saveResponse, err := client.ImageSaveWithOptions(context.Background(), []string{"image_id1"}, types.ImageSaveOptions{Format: types.ImageSaveFormatOCI})
+	if err != nil {
+		t.Fatal(err)
+	}
+	saveResponse.Close()
+}
@@ -0,0 +1,83 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/docker/docker/api/types"
+	volumetypes "github.com/docker/docker/api/types/volume"
+)
+
+// VolumeExport retrieves the data of a volume from the docker host as a tar
+// archive. It's up to the caller to store the archive and close the stream.
+func (cli *Client) VolumeExport(ctx context.Context, volumeID string) (io.ReadCloser, error) {
+	if err := cli.NewVersionError("1.40", "volume export"); err != nil {
+		return nil, err
+	}
+	resp, err := cli.get(ctx, "/volumes/"+volumeID+"/export", nil, nil)
+	if err != nil {
+		return nil, wrapResponseError(err, resp, "volume", volumeID)
+	}
+	return resp.body, nil
+}
+
+// VolumeImport creates a volume in the docker host with the data of a tar
+// archive, which may be compressed. The Name, Driver, DriverOpts and Labels
+// of options are those of the volume created.
+func (cli *Client) VolumeImport(ctx context.Context, content io.Reader, options volumetypes.VolumeCreateBody) (types.Volume, error) {
+	var volume types.Volume
+	if err := cli.NewVersionError("1.40", "volume import"); err != nil {
+		return volume, err
+	}
+
+	query := url.Values{}
+	if options.Name != "" {
+		query.Set("name", options.Name)
+	}
+	if options.Driver != "" {
+		query.Set("driver", options.Driver)
+	}
+	if len(options.DriverOpts) != 0 {
+		driverOptsJSON, err := json.Marshal(options.DriverOpts)
+		if err != nil {
+			return volume, err
+		}
+		query.Set("driveropts", string(driverOptsJSON))
+	}
+	if len(options.Labels) != 0 {
+		labelsJSON, err := json.Marshal(options.Labels)
+		if err != nil {
+			return volume, err
+		}
+		query.Set("labels", string(labelsJSON))
+	}
+
+	headers := http.Header{"Content-Type": {"application/x-tar"}}
+	resp, err := cli.postRaw(ctx, "/volumes/import", query, content, headers)
+	if err != nil {
+		return volume, err
+	}
+	err = json.NewDecoder(resp.body).Decode(&volume)
+	ensureReaderClosed(resp)
+	return volume, err
+}
+
+// VolumeClone creates a volume in the docker host with a copy of the data of
+// an existing volume, using the same driver. The Name, DriverOpts and Labels
+// of options are those of the volume created.
+func (cli *Client) VolumeClone(ctx context.Context, volumeID string, options volumetypes.VolumeCreateBody) (types.Volume, error) {
+	var volume types.Volume
+	if err := cli.NewVersionError("1.40", "volume clone"); err != nil {
+		return volume, err
+	}
+	resp, err := cli.post(ctx, "/volumes/"+volumeID+"/clone", nil, options, nil)
+	if err != nil {
+		return volume, wrapResponseError(err, resp, "volume", volumeID)
+	}
+	err = json.NewDecoder(resp.body).Decode(&volume)
+	ensureReaderClosed(resp)
+	return volume, err
+}
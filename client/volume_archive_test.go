@@ -0,0 +1,121 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	volumetypes "github.com/docker/docker/api/types/volume"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestVolumeExportNotFound(t *testing.T) {
+	client := &Client{
+		client: newMockClient(errorMock(http.StatusNotFound, "no such volume")),
+	}
+
+	_, err := client.VolumeExport(context.Background(), "unknown")
+	assert.Check(t, IsErrNotFound(err))
+}
+
+func TestVolumeExport(t *testing.T) {
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path != "/volumes/myvolume/export" {
+				return nil, fmt.Errorf("unexpected URL '%s'", req.URL)
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(strings.NewReader("archive")),
+			}, nil
+		}),
+	}
+
+	rdr, err := client.VolumeExport(context.Background(), "myvolume")
+	assert.NilError(t, err)
+	defer rdr.Close()
+	content, err := ioutil.ReadAll(rdr)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(content), "archive"))
+}
+
+func TestVolumeImport(t *testing.T) {
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path != "/volumes/import" {
+				return nil, fmt.Errorf("unexpected URL '%s'", req.URL)
+			}
+			query := req.URL.Query()
+			if name := query.Get("name"); name != "myvolume" {
+				return nil, fmt.Errorf("name not set in URL query properly. Expected 'myvolume', got %s", name)
+			}
+			if labels := query.Get("labels"); labels != `{"label":"value"}` {
+				return nil, fmt.Errorf("labels not set in URL query properly, got %s", labels)
+			}
+			if contentType := req.Header.Get("Content-Type"); contentType != "application/x-tar" {
+				return nil, fmt.Errorf("unexpected content type %s", contentType)
+			}
+			body, err := ioutil.ReadAll(req.Body)
+			if err != nil {
+				return nil, err
+			}
+			if string(body) != "archive" {
+				return nil, fmt.Errorf("unexpected body %q", body)
+			}
+			content, err := json.Marshal(types.Volume{Name: "myvolume", Driver: "local"})
+			if err != nil {
+				return nil, err
+			}
+			return &http.Response{
+				StatusCode: http.StatusCreated,
+				Body:       ioutil.NopCloser(bytes.NewReader(content)),
+			}, nil
+		}),
+	}
+
+	volume, err := client.VolumeImport(context.Background(), strings.NewReader("archive"), volumetypes.VolumeCreateBody{
+		Name:   "myvolume",
+		Labels: map[string]string{"label": "value"},
+	})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(volume.Name, "myvolume"))
+}
+
+func TestVolumeClone(t *testing.T) {
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path != "/volumes/myvolume/clone" {
+				return nil, fmt.Errorf("unexpected URL '%s'", req.URL)
+			}
+			if req.Method != http.MethodPost {
+				return nil, fmt.Errorf("expected POST method, got %s", req.Method)
+			}
+			var options volumetypes.VolumeCreateBody
+			if err := json.NewDecoder(req.Body).Decode(&options); err != nil {
+				return nil, err
+			}
+			if options.Name != "myclone" {
+				return nil, fmt.Errorf("expected clone name 'myclone', got %s", options.Name)
+			}
+			content, err := json.Marshal(types.Volume{Name: "myclone", Driver: "local"})
+			if err != nil {
+				return nil, err
+			}
+			return &http.Response{
+				StatusCode: http.StatusCreated,
+				Body:       ioutil.NopCloser(bytes.NewReader(content)),
+			}, nil
+		}),
+	}
+
+	volume, err := client.VolumeClone(context.Background(), "myvolume", volumetypes.VolumeCreateBody{Name: "myclone"})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(volume.Name, "myclone"))
+}
@@ -18,7 +18,8 @@ const (
 
 // installCommonConfigFlags adds flags to the pflag.FlagSet to configure the daemon
 func installCommonConfigFlags(conf *config.Config, flags *pflag.FlagSet) error {
-	var maxConcurrentDownloads, maxConcurrentUploads int
+	var maxConcurrentDownloads, maxConcurrentUploads, maxDownloadAttempts int
+	var downloadRetryDelay, downloadRetryMaxDelay int
 	defaultPidFile, err := getDefaultPidFile()
 	if err != nil {
 		return err
@@ -71,6 +72,11 @@ func installCommonConfigFlags(conf *config.Config, flags *pflag.FlagSet) error {
 	flags.StringVar(&conf.CorsHeaders, "api-cors-header", "", "Set CORS headers in the Engine API")
 	flags.IntVar(&maxConcurrentDownloads, "max-concurrent-downloads", config.DefaultMaxConcurrentDownloads, "Set the max concurrent downloads for each pull")
 	flags.IntVar(&maxConcurrentUploads, "max-concurrent-uploads", config.DefaultMaxConcurrentUploads, "Set the max concurrent uploads for each push")
+	flags.IntVar(&maxDownloadAttempts, "max-download-attempts", config.DefaultMaxDownloadAttempts, "Set the max download attempts for each layer of a pull")
+	flags.IntVar(&downloadRetryDelay, "download-retry-delay", config.DefaultDownloadRetryDelay, "Set the delay in seconds before retrying a layer download, multiplied by the number of attempts")
+	flags.IntVar(&downloadRetryMaxDelay, "download-retry-max-delay", 0, "Set the max delay in seconds between layer download attempts (0 for no limit)")
+	flags.StringVar(&conf.PushCompression, "push-compression", "", "Set the compression of the layers pushed to registries (gzip)")
+	flags.StringVar(&conf.SignaturePolicy, "signature-policy", "", "Path to the policy file for image signature verification")
 	flags.IntVar(&conf.ShutdownTimeout, "shutdown-timeout", defaultShutdownTimeout, "Set the default shutdown timeout")
 	flags.IntVar(&conf.NetworkDiagnosticPort, "network-diagnostic-port", 0, "TCP port number of the network diagnostic server")
 	flags.MarkHidden("network-diagnostic-port")
@@ -92,6 +98,9 @@ func installCommonConfigFlags(conf *config.Config, flags *pflag.FlagSet) error {
 
 	conf.MaxConcurrentDownloads = &maxConcurrentDownloads
 	conf.MaxConcurrentUploads = &maxConcurrentUploads
+	conf.MaxDownloadAttempts = &maxDownloadAttempts
+	conf.DownloadRetryDelay = &downloadRetryDelay
+	conf.DownloadRetryMaxDelay = &downloadRetryMaxDelay
 	return nil
 }
 
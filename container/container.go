@@ -358,6 +358,30 @@ func (container *Container) CheckpointDir() string {
 // StartLogger starts a new logger driver for the container.
 func (container *Container) StartLogger() (logger.Logger, error) {
 	cfg := container.HostConfig.LogConfig
+	if cfg.Type != logger.FanoutDriverName {
+		return container.startLogDriver(cfg)
+	}
+
+	configs, err := logger.ParseFanoutConfig(cfg.Config)
+	if err != nil {
+		return nil, err
+	}
+	loggers := make([]logger.Logger, 0, len(configs))
+	for _, c := range configs {
+		l, err := container.startLogDriver(c)
+		if err != nil {
+			for _, l := range loggers {
+				l.Close()
+			}
+			return nil, errors.Wrapf(err, "failed to start %s logging driver", c.Type)
+		}
+		loggers = append(loggers, l)
+	}
+	return logger.NewFanout(loggers), nil
+}
+
+// startLogDriver starts a single logger driver for the container.
+func (container *Container) startLogDriver(cfg containertypes.LogConfig) (logger.Logger, error) {
 	initDriver, err := logger.GetLogDriver(cfg.Type)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get logging factory")
@@ -405,6 +429,20 @@ func (container *Container) StartLogger() (logger.Logger, error) {
 		return nil, err
 	}
 
+	sl, err := logger.NewStructuredLogger(l, cfg.Config)
+	if err != nil {
+		l.Close()
+		return nil, err
+	}
+	l = sl
+
+	rl, err := logger.NewRateLimiter(l, cfg.Config)
+	if err != nil {
+		l.Close()
+		return nil, err
+	}
+	l = rl
+
 	if containertypes.LogMode(cfg.Config["mode"]) == containertypes.LogModeNonBlock {
 		bufferSize := int64(-1)
 		if s, exists := cfg.Config["max-buffer-size"]; exists {
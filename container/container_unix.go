@@ -13,6 +13,9 @@ import (
 	containertypes "github.com/docker/docker/api/types/container"
 	mounttypes "github.com/docker/docker/api/types/mount"
 	swarmtypes "github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/chrootarchive"
+	"github.com/docker/docker/pkg/idtools"
 	"github.com/docker/docker/pkg/mount"
 	"github.com/docker/docker/pkg/stringid"
 	"github.com/docker/docker/volume"
@@ -122,24 +125,34 @@ func (container *Container) NetworkMounts() []Mount {
 	return mounts
 }
 
-// CopyImagePathContent copies files in destination to the volume.
-func (container *Container) CopyImagePathContent(v volume.Volume, destination string) error {
+// CopyImagePathContent copies files in destination to the volume, as
+// configured by cfg. The owner in cfg, if any, is applied as is and must
+// already be mapped to host ids. It returns whether any files were copied.
+func (container *Container) CopyImagePathContent(v volume.Volume, destination string, cfg volumemounts.PopulateConfig) (bool, error) {
+	if cfg.Populate == mounttypes.PopulateNever {
+		return false, nil
+	}
+
 	rootfs, err := container.GetResourcePath(destination)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	if _, err := os.Stat(rootfs); err != nil {
-		if os.IsNotExist(err) {
-			return nil
+		if !os.IsNotExist(err) {
+			return false, err
 		}
-		return err
+		if cfg.Owner == nil && cfg.Mode == nil {
+			return false, nil
+		}
+		// there is nothing to copy, but the volume root must still be set up
+		rootfs = ""
 	}
 
 	id := stringid.GenerateNonCryptoID()
 	path, err := v.Mount(id)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	defer func() {
@@ -148,9 +161,29 @@ func (container *Container) CopyImagePathContent(v volume.Volume, destination st
 		}
 	}()
 	if err := label.Relabel(path, container.MountLabel, true); err != nil && err != unix.ENOTSUP {
-		return err
+		return false, err
+	}
+
+	var copied []string
+	if rootfs != "" {
+		copied, err = copyExistingContents(rootfs, path, cfg.Populate == mounttypes.PopulateAlways, cfg.Owner)
+		if err != nil {
+			return false, err
+		}
+	}
+	if owner := cfg.Owner; owner != nil {
+		// the root of the volume is the mount path returned by the driver, the
+		// copied content was already chowned while it was extracted.
+		if err := os.Lchown(path, owner.UID, owner.GID); err != nil {
+			return false, errors.Wrap(err, "error setting the owner of the volume")
+		}
+	}
+	if cfg.Mode != nil {
+		if err := os.Chmod(path, *cfg.Mode); err != nil {
+			return false, errors.Wrap(err, "error setting the mode of the volume")
+		}
 	}
-	return copyExistingContents(rootfs, path)
+	return len(copied) > 0, nil
 }
 
 // ShmResourcePath returns path to shm
@@ -401,17 +434,83 @@ func ignoreUnsupportedXAttrs() fs.CopyDirOpt {
 }
 
 // copyExistingContents copies from the source to the destination and
-// ensures the ownership is appropriately set.
-func copyExistingContents(source, destination string) error {
+// ensures the ownership is appropriately set. Nothing is copied if the
+// destination is not empty, unless always is set, in which case only the files
+// and directories of the source which do not exist in the destination are
+// copied. If owner is set, it is applied to the copied files and directories.
+// It returns the paths, relative to the destination, of the copied files and
+// directories.
+func copyExistingContents(source, destination string, always bool, owner *idtools.Identity) ([]string, error) {
 	dstList, err := ioutil.ReadDir(destination)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if len(dstList) == 0 {
+		if owner != nil {
+			return []string{"."}, untarContents(source, destination, nil, owner)
+		}
+		if err := fs.CopyDir(destination, source, ignoreUnsupportedXAttrs()); err != nil {
+			return nil, err
+		}
+		return []string{"."}, nil
 	}
-	if len(dstList) != 0 {
+	if !always {
 		// destination is not empty, do not copy
+		return nil, nil
+	}
+	return copyMissingContents(source, destination, owner)
+}
+
+// copyMissingContents copies the files and directories of the source which do
+// not exist in the destination, leaving the content of the destination
+// untouched.
+func copyMissingContents(source, destination string, owner *idtools.Identity) ([]string, error) {
+	var missing []string
+	err := filepath.Walk(source, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(source, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		dstInfo, err := os.Lstat(filepath.Join(destination, rel))
+		if err != nil {
+			if !os.IsNotExist(err) {
+				return err
+			}
+			missing = append(missing, rel)
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() && !dstInfo.IsDir() {
+			// the directory was replaced in the destination
+			return filepath.SkipDir
+		}
 		return nil
+	})
+	if err != nil || len(missing) == 0 {
+		return nil, err
+	}
+	if err := untarContents(source, destination, missing, owner); err != nil {
+		return nil, err
+	}
+	return missing, nil
+}
+
+// untarContents copies the files of the source to the destination through a
+// tar archive. The destination is writable by containers using the volume, so
+// the archive is extracted chrooted in the destination, and the owner, if set,
+// is applied there as the files are created.
+func untarContents(source, destination string, files []string, owner *idtools.Identity) error {
+	rdr, err := archive.TarWithOptions(source, &archive.TarOptions{IncludeFiles: files})
+	if err != nil {
+		return err
 	}
-	return fs.CopyDir(destination, source, ignoreUnsupportedXAttrs())
+	defer rdr.Close()
+	return chrootarchive.UntarUncompressed(rdr, destination, &archive.TarOptions{ChownOpts: owner})
 }
 
 // TmpfsMounts returns the list of tmpfs mounts
@@ -0,0 +1,129 @@
+// +build !windows
+
+package container // import "github.com/docker/docker/container"
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/pkg/idtools"
+	"github.com/docker/docker/pkg/reexec"
+	"golang.org/x/sys/unix"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/skip"
+)
+
+func init() {
+	reexec.Init()
+}
+
+func TestCopyExistingContents(t *testing.T) {
+	src, err := ioutil.TempDir("", "test-copy-existing-contents-src")
+	assert.NilError(t, err)
+	defer os.RemoveAll(src)
+	dst, err := ioutil.TempDir("", "test-copy-existing-contents-dst")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dst)
+
+	assert.NilError(t, os.MkdirAll(filepath.Join(src, "dir", "sub"), 0755))
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(src, "file"), []byte("image"), 0644))
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(src, "dir", "file"), []byte("image"), 0644))
+
+	copied, err := copyExistingContents(src, dst, false, nil)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(copied, []string{"."}))
+
+	// the image adds files, and the volume content is modified
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(dst, "file"), []byte("volume"), 0644))
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(src, "dir", "new"), []byte("image"), 0644))
+	assert.NilError(t, os.MkdirAll(filepath.Join(src, "newdir"), 0755))
+
+	copied, err = copyExistingContents(src, dst, false, nil)
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(copied, 0))
+	_, err = os.Stat(filepath.Join(dst, "dir", "new"))
+	assert.Check(t, os.IsNotExist(err))
+
+	copied, err = copyExistingContents(src, dst, true, nil)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(copied, []string{filepath.Join("dir", "new"), "newdir"}))
+
+	content, err := ioutil.ReadFile(filepath.Join(dst, "file"))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(content), "volume"))
+	content, err = ioutil.ReadFile(filepath.Join(dst, "dir", "new"))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(content), "image"))
+	fi, err := os.Stat(filepath.Join(dst, "newdir"))
+	assert.NilError(t, err)
+	assert.Check(t, fi.IsDir())
+}
+
+func TestCopyExistingContentsOwner(t *testing.T) {
+	skip.If(t, os.Getuid() != 0, "requires root")
+
+	src, err := ioutil.TempDir("", "test-copy-existing-contents-owner-src")
+	assert.NilError(t, err)
+	defer os.RemoveAll(src)
+	dst, err := ioutil.TempDir("", "test-copy-existing-contents-owner-dst")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dst)
+
+	assert.NilError(t, os.MkdirAll(filepath.Join(src, "dir", "sub"), 0755))
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(src, "file"), nil, 0644))
+
+	owner := &idtools.Identity{UID: 1234, GID: 5678}
+	_, err = copyExistingContents(src, dst, false, owner)
+	assert.NilError(t, err)
+
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(dst, "existing"), nil, 0644))
+	assert.NilError(t, os.MkdirAll(filepath.Join(src, "newdir"), 0755))
+	copied, err := copyExistingContents(src, dst, true, owner)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(copied, []string{"newdir"}))
+
+	for _, p := range []string{"file", "dir", filepath.Join("dir", "sub"), "newdir"} {
+		uid, gid := fileOwner(t, filepath.Join(dst, p))
+		assert.Check(t, is.Equal(uid, owner.UID), p)
+		assert.Check(t, is.Equal(gid, owner.GID), p)
+	}
+	uid, _ := fileOwner(t, filepath.Join(dst, "existing"))
+	assert.Check(t, is.Equal(uid, 0))
+}
+
+func TestUntarContentsSymlinkInDestination(t *testing.T) {
+	skip.If(t, os.Getuid() != 0, "requires root")
+
+	src, err := ioutil.TempDir("", "test-untar-contents-src")
+	assert.NilError(t, err)
+	defer os.RemoveAll(src)
+	dst, err := ioutil.TempDir("", "test-untar-contents-dst")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dst)
+	outside, err := ioutil.TempDir("", "test-untar-contents-outside")
+	assert.NilError(t, err)
+	defer os.RemoveAll(outside)
+
+	assert.NilError(t, os.MkdirAll(filepath.Join(src, "dir"), 0755))
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(src, "dir", "file"), []byte("image"), 0644))
+
+	// a container swapped a directory of the volume for a symlink to the host
+	assert.NilError(t, os.Symlink(outside, filepath.Join(dst, "dir")))
+
+	_ = untarContents(src, dst, []string{filepath.Join("dir", "file")}, &idtools.Identity{UID: 1234, GID: 5678})
+
+	_, err = os.Lstat(filepath.Join(outside, "file"))
+	assert.Check(t, os.IsNotExist(err))
+	uid, _ := fileOwner(t, outside)
+	assert.Check(t, is.Equal(uid, 0))
+}
+
+func fileOwner(t *testing.T, path string) (int, int) {
+	t.Helper()
+	var st unix.Stat_t
+	assert.NilError(t, unix.Lstat(path, &st))
+	return int(st.Uid), int(st.Gid)
+}
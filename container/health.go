@@ -10,8 +10,9 @@ import (
 // Health holds the current container health-check state
 type Health struct {
 	types.Health
-	stop chan struct{} // Write struct{} to stop the monitor
-	mu   sync.Mutex
+	stop             chan struct{} // Write struct{} to stop the monitor
+	unhealthyRestart bool          // Set if the container is being stopped to be restarted because it is unhealthy
+	mu               sync.Mutex
 }
 
 // String returns a human-readable description of the health-check state
@@ -52,6 +53,24 @@ func (s *Health) SetStatus(new string) {
 	s.Health.Status = new
 }
 
+// SetUnhealthyRestart records whether the container is being stopped to be
+// restarted because it is unhealthy.
+func (s *Health) SetUnhealthyRestart(restart bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.unhealthyRestart = restart
+}
+
+// UnhealthyRestart returns whether the container is being stopped to be
+// restarted because it is unhealthy.
+func (s *Health) UnhealthyRestart() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.unhealthyRestart
+}
+
 // OpenMonitorChannel creates and returns a new monitor channel. If there
 // already is one, it returns nil.
 func (s *Health) OpenMonitorChannel() chan struct{} {
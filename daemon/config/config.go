@@ -32,6 +32,14 @@ const (
 	// maximum number of uploads that
 	// may take place at a time for each push.
 	DefaultMaxConcurrentUploads = 5
+	// DefaultMaxDownloadAttempts is the default value for
+	// maximum number of attempts that
+	// may take place for each layer download.
+	DefaultMaxDownloadAttempts = 5
+	// DefaultDownloadRetryDelay is the default delay, in seconds,
+	// before retrying a failed layer download. The delay grows
+	// with each attempt.
+	DefaultDownloadRetryDelay = 5
 	// StockRuntimeName is the reserved name/alias used to represent the
 	// OCI runtime being shipped with the docker daemon package.
 	StockRuntimeName = "runc"
@@ -50,20 +58,26 @@ const (
 // Use this to differentiate these options
 // with others like the ones in CommonTLSOptions.
 var flatOptions = map[string]bool{
-	"cluster-store-opts": true,
-	"log-opts":           true,
-	"runtimes":           true,
-	"default-ulimits":    true,
-	"features":           true,
-	"builder":            true,
+	"cluster-store-opts":    true,
+	"log-opts":              true,
+	"runtimes":              true,
+	"default-ulimits":       true,
+	"features":              true,
+	"builder":               true,
+	"events-journal":        true,
+	"event-sinks":           true,
+	"registry-host-mirrors": true,
 }
 
 // skipValidateOptions contains configuration keys
 // that will be skipped from findConfigurationConflicts
 // for unknown flag validation.
 var skipValidateOptions = map[string]bool{
-	"features": true,
-	"builder":  true,
+	"features":              true,
+	"builder":               true,
+	"events-journal":        true,
+	"event-sinks":           true,
+	"registry-host-mirrors": true,
 }
 
 // skipDuplicates contains configuration keys that
@@ -167,6 +181,28 @@ type CommonConfig struct {
 	// may take place at a time for each push.
 	MaxConcurrentUploads *int `json:"max-concurrent-uploads,omitempty"`
 
+	// MaxDownloadAttempts is the maximum number of attempts that
+	// may take place for each layer download.
+	MaxDownloadAttempts *int `json:"max-download-attempts,omitempty"`
+
+	// DownloadRetryDelay is the delay, in seconds, before retrying a
+	// failed layer download. It is multiplied by the number of failed
+	// attempts.
+	DownloadRetryDelay *int `json:"download-retry-delay,omitempty"`
+
+	// DownloadRetryMaxDelay is the maximum delay, in seconds, between
+	// two attempts of a layer download. Zero means no limit.
+	DownloadRetryMaxDelay *int `json:"download-retry-max-delay,omitempty"`
+
+	// PushCompression is the compression of the layers pushed to
+	// registries. Only "gzip" (default) is supported: zstd-compressed
+	// layers require OCI image manifests, which are not pushed yet.
+	PushCompression string `json:"push-compression,omitempty"`
+
+	// SignaturePolicy is the path to the policy file defining which
+	// images must be signed, and by which keys, to be pulled and run.
+	SignaturePolicy string `json:"signature-policy,omitempty"`
+
 	// ShutdownTimeout is the timeout value (in seconds) the daemon will wait for the container
 	// to stop when daemon is being shutdown
 	ShutdownTimeout int `json:"shutdown-timeout,omitempty"`
@@ -229,6 +265,18 @@ type CommonConfig struct {
 	Features map[string]bool `json:"features,omitempty"`
 
 	Builder BuilderConfig `json:"builder,omitempty"`
+
+	// ImageGC is the configuration of the garbage collection of unused
+	// images.
+	ImageGC ImageGCConfig `json:"image-gc,omitempty"`
+
+	// EventsJournal configures the on-disk journal of daemon events, which
+	// allows replaying events after the daemon is restarted.
+	EventsJournal EventsJournalConfig `json:"events-journal,omitempty"`
+
+	// EventSinks are destinations, such as webhooks or plugins, daemon
+	// events are forwarded to.
+	EventSinks []EventSinkConfig `json:"event-sinks,omitempty"`
 }
 
 // IsValueSet returns true if a configuration value
@@ -559,6 +607,29 @@ func Validate(config *Config) error {
 	if config.MaxConcurrentUploads != nil && *config.MaxConcurrentUploads < 0 {
 		return fmt.Errorf("invalid max concurrent uploads: %d", *config.MaxConcurrentUploads)
 	}
+	// validate MaxDownloadAttempts
+	if config.MaxDownloadAttempts != nil && *config.MaxDownloadAttempts <= 0 {
+		return fmt.Errorf("invalid max download attempts: %d", *config.MaxDownloadAttempts)
+	}
+	// validate DownloadRetryDelay
+	if config.DownloadRetryDelay != nil && *config.DownloadRetryDelay < 0 {
+		return fmt.Errorf("invalid download retry delay: %d", *config.DownloadRetryDelay)
+	}
+	// validate DownloadRetryMaxDelay
+	if config.DownloadRetryMaxDelay != nil && *config.DownloadRetryMaxDelay < 0 {
+		return fmt.Errorf("invalid download retry max delay: %d", *config.DownloadRetryMaxDelay)
+	}
+	if err := config.ImageGC.Validate(); err != nil {
+		return err
+	}
+	// validate PushCompression
+	switch config.PushCompression {
+	case "", "gzip":
+	case "zstd":
+		return fmt.Errorf("push compression zstd is not supported: zstd-compressed layers require OCI image manifests, which are not pushed yet")
+	default:
+		return fmt.Errorf("invalid push compression: %s", config.PushCompression)
+	}
 
 	// validate that "default" runtime is not reset
 	if runtimes := config.GetAllRuntimes(); len(runtimes) > 0 {
@@ -571,6 +642,16 @@ func Validate(config *Config) error {
 		return err
 	}
 
+	if _, _, err := config.EventsJournal.Retention(); err != nil {
+		return err
+	}
+
+	for _, sink := range config.EventSinks {
+		if err := sink.Validate(); err != nil {
+			return err
+		}
+	}
+
 	if defaultRuntime := config.GetDefaultRuntimeName(); defaultRuntime != "" && defaultRuntime != StockRuntimeName {
 		runtimes := config.GetAllRuntimes()
 		if _, ok := runtimes[defaultRuntime]; !ok {
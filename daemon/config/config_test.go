@@ -224,6 +224,7 @@ func TestValidateReservedNamespaceLabels(t *testing.T) {
 
 func TestValidateConfigurationErrors(t *testing.T) {
 	minusNumber := -10
+	zeroNumber := 0
 	testCases := []struct {
 		config *Config
 	}{
@@ -262,6 +263,13 @@ func TestValidateConfigurationErrors(t *testing.T) {
 				},
 			},
 		},
+		{
+			config: &Config{
+				CommonConfig: CommonConfig{
+					PushCompression: "xz",
+				},
+			},
+		},
 		{
 			config: &Config{
 				CommonConfig: CommonConfig{
@@ -291,6 +299,48 @@ func TestValidateConfigurationErrors(t *testing.T) {
 				},
 			},
 		},
+		{
+			config: &Config{
+				CommonConfig: CommonConfig{
+					MaxDownloadAttempts: &zeroNumber,
+				},
+			},
+		},
+		{
+			config: &Config{
+				CommonConfig: CommonConfig{
+					DownloadRetryDelay: &minusNumber,
+				},
+			},
+		},
+		{
+			config: &Config{
+				CommonConfig: CommonConfig{
+					DownloadRetryMaxDelay: &minusNumber,
+				},
+			},
+		},
+		{
+			config: &Config{
+				CommonConfig: CommonConfig{
+					ImageGC: ImageGCConfig{Enabled: true, HighWaterMark: 60},
+				},
+			},
+		},
+		{
+			config: &Config{
+				CommonConfig: CommonConfig{
+					ImageGC: ImageGCConfig{Enabled: true, MinAge: "1 day"},
+				},
+			},
+		},
+		{
+			config: &Config{
+				CommonConfig: CommonConfig{
+					ImageGC: ImageGCConfig{Enabled: true, KeepReferences: []string{"busybox:["}},
+				},
+			},
+		},
 		{
 			config: &Config{
 				CommonConfig: CommonConfig{
@@ -305,6 +355,41 @@ func TestValidateConfigurationErrors(t *testing.T) {
 				},
 			},
 		},
+		{
+			config: &Config{
+				CommonConfig: CommonConfig{
+					EventsJournal: EventsJournalConfig{Enabled: true, MaxSize: "lots"},
+				},
+			},
+		},
+		{
+			config: &Config{
+				CommonConfig: CommonConfig{
+					EventsJournal: EventsJournalConfig{Enabled: true, MaxAge: "-1h"},
+				},
+			},
+		},
+		{
+			config: &Config{
+				CommonConfig: CommonConfig{
+					EventSinks: []EventSinkConfig{{}},
+				},
+			},
+		},
+		{
+			config: &Config{
+				CommonConfig: CommonConfig{
+					EventSinks: []EventSinkConfig{{Plugin: "foo", URL: "http://localhost"}},
+				},
+			},
+		},
+		{
+			config: &Config{
+				CommonConfig: CommonConfig{
+					EventSinks: []EventSinkConfig{{URL: "tcp://localhost:1234"}},
+				},
+			},
+		},
 	}
 	for _, tc := range testCases {
 		err := Validate(tc.config)
@@ -362,6 +447,20 @@ func TestValidateConfiguration(t *testing.T) {
 				},
 			},
 		},
+		{
+			config: &Config{
+				CommonConfig: CommonConfig{
+					ImageGC: ImageGCConfig{
+						Enabled:        true,
+						HighWaterMark:  90,
+						LowWaterMark:   80,
+						Schedule:       "24h",
+						KeepReferences: []string{"busybox:*"},
+						MinAge:         "72h",
+					},
+				},
+			},
+		},
 		{
 			config: &Config{
 				CommonConfig: CommonConfig{
@@ -385,6 +484,11 @@ func TestValidateConfiguration(t *testing.T) {
 	}
 }
 
+func TestValidatePushCompressionZstd(t *testing.T) {
+	config := &Config{CommonConfig: CommonConfig{PushCompression: "zstd"}}
+	assert.Check(t, is.ErrorContains(Validate(config), "require OCI image manifests"))
+}
+
 func TestModifiedDiscoverySettings(t *testing.T) {
 	cases := []struct {
 		current  *Config
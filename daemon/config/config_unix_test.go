@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/docker/docker/opts"
+	"github.com/docker/docker/registry"
 	"github.com/docker/go-units"
 	"github.com/spf13/pflag"
 	"gotest.tools/assert"
@@ -132,3 +133,20 @@ func TestDaemonConfigurationMergeShmSize(t *testing.T) {
 	expectedValue := 1 * 1024 * 1024 * 1024
 	assert.Check(t, is.Equal(int64(expectedValue), cc.ShmSize.Value()))
 }
+
+func TestDaemonConfigurationMergeRegistryMirrors(t *testing.T) {
+	data := `{"registry-host-mirrors": {"quay.io": [{"url": "https://quay-cache.example.com", "insecure": true}]}}`
+
+	file := fs.NewFile(t, "docker-config", fs.WithContent(data))
+	defer file.Remove()
+
+	c := &Config{}
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+
+	cc, err := MergeDaemonConfigurations(c, flags, file.Path())
+	assert.NilError(t, err)
+
+	assert.Check(t, is.DeepEqual(cc.RegistryMirrors, map[string][]registry.RegistryMirror{
+		"quay.io": {{URL: "https://quay-cache.example.com", Insecure: true}},
+	}))
+}
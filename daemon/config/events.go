@@ -0,0 +1,72 @@
+package config // import "github.com/docker/docker/daemon/config"
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/go-units"
+	"github.com/pkg/errors"
+)
+
+// EventsJournalConfig contains config for the on-disk events journal
+type EventsJournalConfig struct {
+	Enabled bool   `json:",omitempty"`
+	MaxSize string `json:",omitempty"` // MaxSize is the maximum size of the journal, e.g. "100MB"
+	MaxAge  string `json:",omitempty"` // MaxAge is the maximum age of the journal's events, e.g. "720h"
+}
+
+// Retention returns the maximum size and age of the events journal. Zero
+// values mean no limit.
+func (c EventsJournalConfig) Retention() (maxSize int64, maxAge time.Duration, err error) {
+	if c.MaxSize != "" {
+		if maxSize, err = units.RAMInBytes(c.MaxSize); err != nil {
+			return 0, 0, errors.Wrap(err, "invalid events journal max size")
+		}
+		if maxSize < 0 {
+			return 0, 0, errors.Errorf("invalid events journal max size: %s", c.MaxSize)
+		}
+	}
+	if c.MaxAge != "" {
+		if maxAge, err = time.ParseDuration(c.MaxAge); err != nil {
+			return 0, 0, errors.Wrap(err, "invalid events journal max age")
+		}
+		if maxAge < 0 {
+			return 0, 0, errors.Errorf("invalid events journal max age: %s", c.MaxAge)
+		}
+	}
+	return maxSize, maxAge, nil
+}
+
+// EventSinkConfig configures a destination daemon events are forwarded to.
+// Exactly one of Plugin or URL must be set.
+type EventSinkConfig struct {
+	Plugin     string       `json:",omitempty"` // Plugin is the name of an "eventsink" plugin
+	URL        string       `json:",omitempty"` // URL is an HTTP(S) endpoint events are POSTed to
+	Filters    filters.Args `json:",omitempty"` // Filters selects the events to forward, like the /events API filters
+	QueueSize  int          `json:",omitempty"` // QueueSize is the maximum number of events waiting to be delivered
+	MaxRetries int          `json:",omitempty"` // MaxRetries is the number of delivery attempts before events are dropped
+}
+
+// Validate validates the event sink configuration.
+func (c EventSinkConfig) Validate() error {
+	if (c.Plugin == "") == (c.URL == "") {
+		return errors.New("event sinks must have exactly one of Plugin or URL")
+	}
+	if c.URL != "" {
+		u, err := url.Parse(c.URL)
+		if err != nil {
+			return errors.Wrap(err, "invalid event sink URL")
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return errors.Errorf("invalid event sink URL %q: scheme must be http or https", c.URL)
+		}
+	}
+	if c.QueueSize < 0 {
+		return errors.Errorf("invalid event sink queue size: %d", c.QueueSize)
+	}
+	if c.MaxRetries < 0 {
+		return errors.Errorf("invalid event sink max retries: %d", c.MaxRetries)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package config // import "github.com/docker/docker/daemon/config"
+
+import (
+	"fmt"
+	"path"
+	"time"
+)
+
+const (
+	// DefaultImageGCHighWaterMark is the default disk usage, in percent,
+	// above which unused images are collected.
+	DefaultImageGCHighWaterMark = 85
+	// DefaultImageGCLowWaterMark is the default disk usage, in percent,
+	// down to which unused images are collected.
+	DefaultImageGCLowWaterMark = 70
+	// DefaultImageGCCheckInterval is the default interval between two
+	// checks of the disk usage.
+	DefaultImageGCCheckInterval = 5 * time.Minute
+)
+
+// ImageGCConfig contains the configuration of the garbage collection of
+// unused images
+type ImageGCConfig struct {
+	Enabled bool `json:",omitempty"`
+	// HighWaterMark is the disk usage of the filesystem holding the
+	// images, in percent, above which unused images are collected.
+	HighWaterMark int `json:",omitempty"`
+	// LowWaterMark is the disk usage, in percent, down to which unused
+	// images are collected.
+	LowWaterMark int `json:",omitempty"`
+	// CheckInterval is the interval between two checks of the disk usage,
+	// as a duration such as "5m".
+	CheckInterval string `json:",omitempty"`
+	// Schedule is the interval between two collections run even if the
+	// disk usage is below the high water mark, as a duration such as
+	// "24h". Scheduled collections also stop at the low water mark.
+	Schedule string `json:",omitempty"`
+	// KeepLabels holds the labels, "key" or "key=value", of images that
+	// are never collected.
+	KeepLabels []string `json:",omitempty"`
+	// KeepReferences holds the reference patterns, such as "busybox:*"
+	// or "example.com/team/*", of images that are never collected.
+	KeepReferences []string `json:",omitempty"`
+	// MinAge is the minimum time since an image was last used, tagged or
+	// created before it is collected, as a duration such as "72h".
+	MinAge string `json:",omitempty"`
+}
+
+// Validate validates the image garbage collection configuration.
+func (c ImageGCConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	high, low := c.WaterMarks()
+	if high <= 0 || high > 100 {
+		return fmt.Errorf("invalid image GC high water mark: %d", high)
+	}
+	if low <= 0 || low >= high {
+		return fmt.Errorf("invalid image GC low water mark: %d, must be lower than the high water mark %d", low, high)
+	}
+	for name, d := range map[string]string{"check interval": c.CheckInterval, "schedule": c.Schedule, "min age": c.MinAge} {
+		if d == "" {
+			continue
+		}
+		if v, err := time.ParseDuration(d); err != nil || v < 0 {
+			return fmt.Errorf("invalid image GC %s: %s", name, d)
+		}
+	}
+	for _, pattern := range c.KeepReferences {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid image GC reference pattern: %s", pattern)
+		}
+	}
+	if c.CheckInterval != "" {
+		if v, _ := time.ParseDuration(c.CheckInterval); v == 0 {
+			return fmt.Errorf("invalid image GC check interval: %s", c.CheckInterval)
+		}
+	}
+	return nil
+}
+
+// WaterMarks returns the high and low water marks, defaulting unset ones.
+func (c ImageGCConfig) WaterMarks() (high, low int) {
+	high, low = c.HighWaterMark, c.LowWaterMark
+	if high == 0 {
+		high = DefaultImageGCHighWaterMark
+	}
+	if low == 0 {
+		low = DefaultImageGCLowWaterMark
+	}
+	return high, low
+}
@@ -342,6 +342,23 @@ func validateHealthCheck(healthConfig *containertypes.HealthConfig) error {
 	if healthConfig.StartPeriod != 0 && healthConfig.StartPeriod < containertypes.MinimumDuration {
 		return errors.Errorf("StartPeriod in Healthcheck cannot be less than %s", containertypes.MinimumDuration)
 	}
+	if len(healthConfig.Test) > 0 {
+		// HTTP and TCP probes are run from the network namespace of the
+		// container, which is only supported on Linux.
+		if t := healthConfig.Test[0]; (t == "HTTP" || t == "TCP") && runtime.GOOS != "linux" {
+			return errors.Errorf("%s healthchecks are not supported on this platform", t)
+		}
+		switch healthConfig.Test[0] {
+		case "HTTP":
+			if _, _, err := parseHTTPProbe(healthConfig.Test); err != nil {
+				return err
+			}
+		case "TCP":
+			if _, err := parseTCPProbe(healthConfig.Test); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
@@ -362,6 +379,9 @@ func validatePortBindings(ports nat.PortMap) error {
 }
 
 func validateRestartPolicy(policy containertypes.RestartPolicy) error {
+	if policy.OnUnhealthy && policy.IsNone() {
+		return errors.Errorf("restarting unhealthy containers requires a restart policy")
+	}
 	switch policy.Name {
 	case "always", "unless-stopped", "no":
 		if policy.MaximumRetryCount != 0 {
@@ -377,6 +397,27 @@ func validateRestartPolicy(policy containertypes.RestartPolicy) error {
 	default:
 		return errors.Errorf("invalid restart policy '%s'", policy.Name)
 	}
+	if policy.Name == "no" {
+		if policy.Delay != 0 || policy.Multiplier != 0 || policy.MaxDelay != 0 || policy.ResetAfter != 0 || policy.MaxAttempts != 0 || policy.Window != 0 {
+			return errors.Errorf("restart backoff options cannot be used with restart policy '%s'", policy.Name)
+		}
+		return nil
+	}
+	if policy.Delay < 0 || policy.MaxDelay < 0 || policy.ResetAfter < 0 || policy.Window < 0 {
+		return errors.Errorf("restart delays and windows cannot be negative")
+	}
+	if policy.Multiplier != 0 && policy.Multiplier < 1 {
+		return errors.Errorf("restart delay multiplier cannot be less than 1")
+	}
+	if policy.Delay != 0 && policy.MaxDelay != 0 && policy.Delay > policy.MaxDelay {
+		return errors.Errorf("restart delay cannot be greater than the maximum restart delay")
+	}
+	if policy.MaxAttempts < 0 {
+		return errors.Errorf("maximum restart attempts cannot be negative")
+	}
+	if (policy.MaxAttempts == 0) != (policy.Window == 0) {
+		return errors.Errorf("maximum restart attempts and restart window must be used together")
+	}
 	return nil
 }
 
@@ -37,9 +37,11 @@ func (daemon *Daemon) containerCreate(params types.ContainerCreateConfig, manage
 		return containertypes.ContainerCreateCreatedBody{}, errdefs.InvalidParameter(errors.New("Config cannot be empty in order to create a container"))
 	}
 
+	var img *image.Image
 	os := runtime.GOOS
 	if params.Config.Image != "" {
-		img, err := daemon.imageService.GetImage(params.Config.Image)
+		var err error
+		img, err = daemon.imageService.GetImageForPlatform(params.Config.Image, params.Platform)
 		if err == nil {
 			os = img.OS
 		}
@@ -55,6 +57,11 @@ func (daemon *Daemon) containerCreate(params types.ContainerCreateConfig, manage
 	if err != nil {
 		return containertypes.ContainerCreateCreatedBody{Warnings: warnings}, errdefs.InvalidParameter(err)
 	}
+	if img != nil {
+		if err := checkEmulation(img.Platform()); err != nil {
+			warnings = append(warnings, err.Error())
+		}
+	}
 
 	err = verifyNetworkingConfig(params.NetworkingConfig)
 	if err != nil {
@@ -89,10 +96,13 @@ func (daemon *Daemon) create(params types.ContainerCreateConfig, managed bool) (
 
 	os := runtime.GOOS
 	if params.Config.Image != "" {
-		img, err = daemon.imageService.GetImage(params.Config.Image)
+		img, err = daemon.imageService.GetImageForPlatform(params.Config.Image, params.Platform)
 		if err != nil {
 			return nil, err
 		}
+		if err := daemon.imageService.VerifyImageSignature(params.Config.Image, img); err != nil {
+			return nil, err
+		}
 		if img.OS != "" {
 			os = img.OS
 		} else {
@@ -191,6 +201,11 @@ func (daemon *Daemon) create(params types.ContainerCreateConfig, managed bool) (
 		return nil, err
 	}
 	stateCtr.set(container.ID, "stopped")
+	if imgID != "" {
+		if err := daemon.imageService.SetImageLastUsed(imgID); err != nil {
+			logrus.Warnf("failed to record last use of image %s: %v", imgID, err)
+		}
+	}
 	daemon.LogContainerEvent(container, "create")
 	return container, nil
 }
@@ -7,14 +7,19 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
+	"github.com/docker/docker/api/types"
 	containertypes "github.com/docker/docker/api/types/container"
 	mounttypes "github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/container"
+	"github.com/docker/docker/errdefs"
 	"github.com/docker/docker/oci"
 	"github.com/docker/docker/pkg/stringid"
+	volumemounts "github.com/docker/docker/volume/mounts"
 	volumeopts "github.com/docker/docker/volume/service/opts"
 	"github.com/opencontainers/selinux/go-selinux/label"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
 
@@ -68,7 +73,7 @@ func (daemon *Daemon) createContainerOSSpecificSettings(container *container.Con
 			return err
 		}
 
-		container.AddMountPointWithVolume(destination, &volumeWrapper{v: v, s: daemon.volumes}, true)
+		container.AddMountPointWithVolume(destination, &volumeWrapper{v: v, s: daemon.volumes, container: container.ID}, true)
 	}
 	return daemon.populateVolumes(container)
 }
@@ -85,10 +90,39 @@ func (daemon *Daemon) populateVolumes(c *container.Container) error {
 			continue
 		}
 
+		cfg, err := volumemounts.ParsePopulateOptions(mnt.Spec.VolumeOptions)
+		if err != nil {
+			return err
+		}
+		if cfg.Owner != nil {
+			// the populate owner is given as container ids
+			owner, err := daemon.idMapping.ToHost(*cfg.Owner)
+			if err != nil {
+				return errdefs.InvalidParameter(errors.Wrapf(err, "populate owner %d:%d is not mapped in the user namespace", cfg.Owner.UID, cfg.Owner.GID))
+			}
+			cfg.Owner = &owner
+		}
+
 		logrus.Debugf("copying image data from %s:%s, to %s", c.ID, mnt.Destination, mnt.Name)
-		if err := c.CopyImagePathContent(mnt.Volume, mnt.Destination); err != nil {
+		copied, err := c.CopyImagePathContent(mnt.Volume, mnt.Destination, cfg)
+		if err != nil {
 			return err
 		}
+
+		population := types.VolumePopulation{
+			At:        time.Now().UTC().Format(time.RFC3339),
+			Container: c.ID,
+			Copied:    copied,
+			Image:     c.ImageID.String(),
+			Populate:  string(cfg.Populate),
+		}
+		if vo := mnt.Spec.VolumeOptions; vo != nil {
+			population.Owner = vo.PopulateOwner
+			population.Mode = vo.PopulateMode
+		}
+		if err := daemon.volumes.SetPopulation(context.TODO(), mnt.Volume.Name(), population); err != nil {
+			logrus.WithError(err).WithField("volume", mnt.Volume.Name()).Warn("Failed to record volume population")
+		}
 	}
 	return nil
 }
@@ -34,6 +34,7 @@ import (
 	"github.com/docker/docker/daemon/config"
 	"github.com/docker/docker/daemon/discovery"
 	"github.com/docker/docker/daemon/events"
+	eventsink "github.com/docker/docker/daemon/events/sink"
 	"github.com/docker/docker/daemon/exec"
 	"github.com/docker/docker/daemon/images"
 	"github.com/docker/docker/daemon/logger"
@@ -46,8 +47,10 @@ import (
 	_ "github.com/docker/docker/daemon/graphdriver/register"
 	"github.com/docker/docker/daemon/stats"
 	dmetadata "github.com/docker/docker/distribution/metadata"
+	"github.com/docker/docker/distribution/xfer"
 	"github.com/docker/docker/dockerversion"
 	"github.com/docker/docker/image"
+	"github.com/docker/docker/image/signature"
 	"github.com/docker/docker/layer"
 	"github.com/docker/docker/libcontainerd"
 	"github.com/docker/docker/pkg/idtools"
@@ -61,6 +64,7 @@ import (
 	refstore "github.com/docker/docker/reference"
 	"github.com/docker/docker/registry"
 	"github.com/docker/docker/runconfig"
+	volumemounts "github.com/docker/docker/volume/mounts"
 	volumesservice "github.com/docker/docker/volume/service"
 	"github.com/docker/libnetwork"
 	"github.com/docker/libnetwork/cluster"
@@ -111,6 +115,7 @@ type Daemon struct {
 	cluster               Cluster
 	genericResources      []swarm.GenericResource
 	metricsPluginListener net.Listener
+	eventForwarders       []*eventsink.Forwarder
 
 	machineMemory uint64
 
@@ -740,6 +745,12 @@ func NewDaemon(ctx context.Context, config *config.Config, pluginStore *plugin.S
 		return nil, err
 	}
 
+	// staging mounts of volume subpaths left behind by a previous daemon
+	// must be unmounted before anything removes their directory.
+	if err := volumemounts.CleanupSubpathStaging(getSubpathStagingDir(config)); err != nil {
+		logrus.WithError(err).Warn("failed to clean up volume subpath staging directories")
+	}
+
 	// set up the tmpDir to use a canonical path
 	tmp, err := prepareTempDir(config.Root, rootIDs)
 	if err != nil {
@@ -987,6 +998,17 @@ func NewDaemon(ctx context.Context, config *config.Config, pluginStore *plugin.S
 		return nil, err
 	}
 
+	var signaturePolicy *signature.Policy
+	if config.SignaturePolicy != "" {
+		if signaturePolicy, err = signature.LoadPolicy(config.SignaturePolicy); err != nil {
+			return nil, err
+		}
+	}
+	signatureStore, err := signature.NewStore(filepath.Join(config.Root, "signatures"))
+	if err != nil {
+		return nil, err
+	}
+
 	// Discovery is only enabled when the daemon is launched with an address to advertise.  When
 	// initialized, the daemon is registered and we can store the discovery backend as it's read-only
 	if err := d.initDiscovery(config); err != nil {
@@ -1011,6 +1033,20 @@ func NewDaemon(ctx context.Context, config *config.Config, pluginStore *plugin.S
 	d.statsCollector = d.newStatsCollector(1 * time.Second)
 
 	d.EventsService = events.New()
+	if config.EventsJournal.Enabled {
+		maxSize, maxAge, err := config.EventsJournal.Retention()
+		if err != nil {
+			return nil, err
+		}
+		journal, err := events.NewJournal(filepath.Join(config.Root, "events"), maxSize, maxAge)
+		if err != nil {
+			return nil, err
+		}
+		d.EventsService.SetJournal(journal)
+	}
+	if err := d.startEventSinks(config.EventSinks); err != nil {
+		return nil, err
+	}
 	d.root = config.Root
 	d.idMapping = idMapping
 	d.seccompEnabled = sysInfo.Seccomp
@@ -1029,11 +1065,16 @@ func NewDaemon(ctx context.Context, config *config.Config, pluginStore *plugin.S
 		LayerStores:               layerStores,
 		MaxConcurrentDownloads:    *config.MaxConcurrentDownloads,
 		MaxConcurrentUploads:      *config.MaxConcurrentUploads,
+		DownloadRetryPolicy:       downloadRetryPolicy(config),
 		ReferenceStore:            rs,
 		RegistryService:           registryService,
+		SignaturePolicy:           signaturePolicy,
+		SignatureStore:            signatureStore,
 		TrustKey:                  trustKey,
 	})
 
+	d.imageService.SetGCPolicy(d.imageGCPolicy(config))
+
 	go d.execCommandGC()
 
 	d.containerd, err = libcontainerd.NewClient(ctx, d.containerdCli, filepath.Join(config.ExecRoot, "containerd"), ContainersNamespace, d)
@@ -1174,9 +1215,18 @@ func (daemon *Daemon) Shutdown() error {
 	}
 
 	if daemon.imageService != nil {
+		daemon.imageService.SetGCPolicy(nil)
 		daemon.imageService.Cleanup()
 	}
 
+	daemon.stopEventSinks()
+
+	if daemon.EventsService != nil {
+		if err := daemon.EventsService.Close(); err != nil {
+			logrus.Errorf("Error closing events journal: %v", err)
+		}
+	}
+
 	// If we are part of a cluster, clean up cluster's stuff
 	if daemon.clusterProvider != nil {
 		logrus.Debugf("start clean shutdown of cluster resources...")
@@ -1263,6 +1313,12 @@ func (daemon *Daemon) Subnets() ([]net.IPNet, []net.IPNet) {
 	return v4Subnets, v6Subnets
 }
 
+// getSubpathStagingDir returns the directory in which the subpaths of volumes
+// are bind mounted before being mounted in containers.
+func getSubpathStagingDir(config *config.Config) string {
+	return filepath.Join(config.GetExecRoot(), "volume-subpaths")
+}
+
 // prepareTempDir prepares and returns the default directory to use
 // for temporary files.
 // If it doesn't exist, it is created. If it exists, its content is removed.
@@ -1333,6 +1389,51 @@ func (daemon *Daemon) initDiscovery(conf *config.Config) error {
 	return nil
 }
 
+// imageGCPolicy returns the policy of the garbage collection of unused images
+// configured in conf, or nil if it is disabled.
+func (daemon *Daemon) imageGCPolicy(conf *config.Config) *images.GCPolicy {
+	if !conf.ImageGC.Enabled {
+		return nil
+	}
+	policy := &images.GCPolicy{
+		Root:           filepath.Join(conf.Root, daemon.imageService.GraphDriverForOS(runtime.GOOS)),
+		CheckInterval:  config.DefaultImageGCCheckInterval,
+		KeepLabels:     conf.ImageGC.KeepLabels,
+		KeepReferences: conf.ImageGC.KeepReferences,
+	}
+	policy.HighWaterMark, policy.LowWaterMark = conf.ImageGC.WaterMarks()
+	// durations are checked by config.Validate
+	if conf.ImageGC.CheckInterval != "" {
+		policy.CheckInterval, _ = time.ParseDuration(conf.ImageGC.CheckInterval)
+	}
+	if conf.ImageGC.Schedule != "" {
+		policy.Schedule, _ = time.ParseDuration(conf.ImageGC.Schedule)
+	}
+	if conf.ImageGC.MinAge != "" {
+		policy.MinAge, _ = time.ParseDuration(conf.ImageGC.MinAge)
+	}
+	return policy
+}
+
+// downloadRetryPolicy returns the retry policy of layer downloads configured
+// in conf. Unset values fall back to their default.
+func downloadRetryPolicy(conf *config.Config) xfer.RetryPolicy {
+	policy := xfer.RetryPolicy{
+		MaxAttempts: config.DefaultMaxDownloadAttempts,
+		Delay:       config.DefaultDownloadRetryDelay * time.Second,
+	}
+	if conf.MaxDownloadAttempts != nil {
+		policy.MaxAttempts = *conf.MaxDownloadAttempts
+	}
+	if conf.DownloadRetryDelay != nil {
+		policy.Delay = time.Duration(*conf.DownloadRetryDelay) * time.Second
+	}
+	if conf.DownloadRetryMaxDelay != nil {
+		policy.MaxDelay = time.Duration(*conf.DownloadRetryMaxDelay) * time.Second
+	}
+	return policy
+}
+
 func isBridgeNetworkDisabled(conf *config.Config) bool {
 	return conf.BridgeConfig.Iface == config.DisableNetworkBridge
 }
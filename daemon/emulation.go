@@ -0,0 +1,60 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"runtime"
+
+	"github.com/containerd/containerd/platforms"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/errdefs"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+// compatibleArchitectures lists, for each host architecture, the other
+// architectures the host runs natively.
+var compatibleArchitectures = map[string][]string{
+	"amd64": {"386"},
+	"arm64": {"arm"},
+}
+
+// checkEmulation returns an error if images of platform are of a foreign
+// architecture which the host runs neither natively nor through a
+// registered emulator.
+func checkEmulation(platform specs.Platform) error {
+	if platform.OS != runtime.GOOS || platform.Architecture == "" {
+		return nil
+	}
+	arch := platforms.Normalize(platform).Architecture
+	host := platforms.Normalize(platforms.DefaultSpec()).Architecture
+	if arch == host {
+		return nil
+	}
+	for _, compatible := range compatibleArchitectures[host] {
+		if arch == compatible {
+			return nil
+		}
+	}
+
+	registered, err := emulatorRegistered(arch)
+	if err != nil {
+		return errdefs.System(errors.Wrapf(err, "unable to check for an emulator of architecture %s", arch))
+	}
+	if !registered {
+		return errdefs.InvalidParameter(errors.Errorf("image architecture %s cannot run on this %s host: no binfmt_misc handler is registered for it", arch, host))
+	}
+	return nil
+}
+
+// checkContainerEmulation returns an error if the image of container is of a
+// foreign architecture which cannot run on the host.
+func (daemon *Daemon) checkContainerEmulation(container *container.Container) error {
+	if container.ImageID == "" {
+		return nil
+	}
+	img, err := daemon.imageService.GetImage(container.ImageID.String())
+	if err != nil {
+		// the image may have been removed while the container was kept
+		return nil
+	}
+	return checkEmulation(img.Platform())
+}
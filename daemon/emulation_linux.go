@@ -0,0 +1,123 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// binfmtMiscDir is where the binfmt_misc file system, which registers the
+// interpreters of binary formats the kernel does not run natively, is
+// mounted.
+var binfmtMiscDir = "/proc/sys/fs/binfmt_misc"
+
+// elfMachines maps the ELF machine types to architectures, by class
+// (32-bit or 64-bit) and then by byte order (little or big endian).
+var elfMachines = map[uint16]map[byte]map[byte]string{
+	0x03: {1: {1: "386"}},
+	0x08: {1: {1: "mipsle", 2: "mips"}, 2: {1: "mips64le", 2: "mips64"}},
+	0x15: {2: {1: "ppc64le", 2: "ppc64"}},
+	0x16: {2: {2: "s390x"}},
+	0x28: {1: {1: "arm"}},
+	0x3e: {2: {1: "amd64"}},
+	0xb7: {2: {1: "arm64"}},
+	0xf3: {2: {1: "riscv64"}},
+}
+
+// emulatorRegistered returns whether an enabled binfmt_misc handler is
+// registered to run ELF binaries of architecture arch.
+func emulatorRegistered(arch string) (bool, error) {
+	status, err := ioutil.ReadFile(filepath.Join(binfmtMiscDir, "status"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			// binfmt_misc is not mounted
+			return false, nil
+		}
+		return false, err
+	}
+	if strings.TrimSpace(string(status)) != "enabled" {
+		return false, nil
+	}
+
+	entries, err := ioutil.ReadDir(binfmtMiscDir)
+	if err != nil {
+		return false, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || entry.Name() == "status" || entry.Name() == "register" {
+			continue
+		}
+		handlerArch, err := binfmtHandlerArch(filepath.Join(binfmtMiscDir, entry.Name()))
+		if err != nil {
+			return false, err
+		}
+		if handlerArch == arch {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// binfmtHandlerArch returns the architecture of the ELF binaries the
+// binfmt_misc handler at path runs, or an empty string if the handler is
+// disabled or does not match on an ELF header.
+func binfmtHandlerArch(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// the handler was unregistered meanwhile
+			return "", nil
+		}
+		return "", err
+	}
+	defer f.Close()
+
+	var (
+		enabled bool
+		magic   []byte
+	)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		switch {
+		case len(fields) == 1 && fields[0] == "enabled":
+			enabled = true
+		case len(fields) == 2 && fields[0] == "offset" && fields[1] != "0":
+			return "", nil
+		case len(fields) == 2 && fields[0] == "magic":
+			if magic, err = hex.DecodeString(fields[1]); err != nil {
+				return "", nil
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	if !enabled {
+		return "", nil
+	}
+	return elfArch(magic), nil
+}
+
+// elfArch returns the architecture of the ELF binaries starting with the
+// header, or an empty string if header is not a known ELF header.
+func elfArch(header []byte) string {
+	if len(header) < 20 || string(header[:4]) != "\x7fELF" {
+		return ""
+	}
+	class, order := header[4], header[5]
+	var machine uint16
+	switch order {
+	case 1:
+		machine = binary.LittleEndian.Uint16(header[18:20])
+	case 2:
+		machine = binary.BigEndian.Uint16(header[18:20])
+	default:
+		return ""
+	}
+	return elfMachines[machine][class][order]
+}
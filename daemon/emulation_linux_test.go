@@ -0,0 +1,76 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestEmulatorRegistered(t *testing.T) {
+	dir, err := ioutil.TempDir("", "binfmt_misc")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+	defer func(orig string) { binfmtMiscDir = orig }(binfmtMiscDir)
+	binfmtMiscDir = dir
+
+	// binfmt_misc not mounted
+	registered, err := emulatorRegistered("arm64")
+	assert.NilError(t, err)
+	assert.Check(t, !registered)
+
+	handlers := map[string]string{
+		"status":   "enabled\n",
+		"register": "",
+		"qemu-aarch64": `enabled
+interpreter /usr/bin/qemu-aarch64-static
+flags: OCF
+offset 0
+magic 7f454c460201010000000000000000000200b700
+mask ffffffffffffff00fffffffffffffffffeffffff
+`,
+		"qemu-s390x": `disabled
+interpreter /usr/bin/qemu-s390x-static
+flags: OCF
+offset 0
+magic 7f454c4602020100000000000000000000020016
+mask ffffffffffffff00fffffffffffffffffffeffff
+`,
+		"qemu-ppc64le": `enabled
+interpreter /usr/bin/qemu-ppc64le-static
+flags: OCF
+offset 0
+magic 7f454c4602010100000000000000000002001500
+mask ffffffffffffff00fffffffffffffffffeffff00
+`,
+		"jar": `enabled
+interpreter /usr/bin/jexec
+flags:
+offset 0
+magic 504b0304
+`,
+	}
+	for name, content := range handlers {
+		assert.NilError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+
+	for arch, expected := range map[string]bool{
+		"arm64":   true,
+		"ppc64le": true,
+		"s390x":   false, // disabled
+		"riscv64": false,
+	} {
+		registered, err := emulatorRegistered(arch)
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(registered, expected), arch)
+	}
+
+	// binfmt_misc disabled
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(dir, "status"), []byte("disabled\n"), 0644))
+	registered, err = emulatorRegistered("arm64")
+	assert.NilError(t, err)
+	assert.Check(t, !registered)
+}
@@ -0,0 +1,9 @@
+// +build !linux
+
+package daemon // import "github.com/docker/docker/daemon"
+
+// emulatorRegistered returns whether an emulator is registered to run
+// binaries of architecture arch. Emulators are only supported on Linux.
+func emulatorRegistered(arch string) (bool, error) {
+	return false, nil
+}
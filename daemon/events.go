@@ -9,7 +9,9 @@ import (
 	"github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/config"
 	daemonevents "github.com/docker/docker/daemon/events"
+	eventsink "github.com/docker/docker/daemon/events/sink"
 	"github.com/docker/libnetwork"
 	swarmapi "github.com/docker/swarmkit/api"
 	gogotypes "github.com/gogo/protobuf/types"
@@ -306,3 +308,33 @@ func eventTimestamp(meta swarmapi.Meta, action swarmapi.WatchActionKind) time.Ti
 	}
 	return eventTime
 }
+
+// startEventSinks starts forwarding events to the configured event sinks.
+func (daemon *Daemon) startEventSinks(sinks []config.EventSinkConfig) error {
+	for _, c := range sinks {
+		var (
+			s   eventsink.Sink
+			err error
+		)
+		if c.Plugin != "" {
+			s = eventsink.NewPlugin(c.Plugin, daemon.PluginStore)
+		} else if s, err = eventsink.NewWebhook(c.URL); err != nil {
+			daemon.stopEventSinks()
+			return err
+		}
+		daemon.eventForwarders = append(daemon.eventForwarders, eventsink.NewForwarder(daemon.EventsService, s, eventsink.Config{
+			Filter:     daemonevents.NewFilter(c.Filters),
+			QueueSize:  c.QueueSize,
+			MaxRetries: c.MaxRetries,
+		}))
+	}
+	return nil
+}
+
+// stopEventSinks stops forwarding events to the configured event sinks.
+func (daemon *Daemon) stopEventSinks() {
+	for _, f := range daemon.eventForwarders {
+		f.Close()
+	}
+	daemon.eventForwarders = nil
+}
@@ -1,23 +1,29 @@
 package events // import "github.com/docker/docker/daemon/events"
 
 import (
+	"sort"
 	"sync"
 	"time"
 
 	eventtypes "github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/pkg/pubsub"
+	"github.com/sirupsen/logrus"
 )
 
 const (
 	eventsLimit = 256
 	bufferSize  = 1024
+
+	// maximum number of events replayed from the journal to a subscriber.
+	journalReplayLimit = 10000
 )
 
 // Events is pubsub channel for events generated by the engine.
 type Events struct {
-	mu     sync.Mutex
-	events []eventtypes.Message
-	pub    *pubsub.Publisher
+	mu      sync.Mutex
+	events  []eventtypes.Message
+	pub     *pubsub.Publisher
+	journal *Journal
 }
 
 // New returns new *Events instance
@@ -28,6 +34,28 @@ func New() *Events {
 	}
 }
 
+// SetJournal sets the on-disk journal that events are persisted to. When set,
+// past events are replayed from the journal instead of the in-memory buffer.
+func (e *Events) SetJournal(j *Journal) {
+	e.mu.Lock()
+	e.journal = j
+	e.mu.Unlock()
+}
+
+// Close closes the journal, if any. Events published afterwards are no longer
+// persisted.
+func (e *Events) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.journal == nil {
+		return nil
+	}
+	err := e.journal.Close()
+	e.journal = nil
+	return err
+}
+
 // Subscribe adds new listener to events, returns slice of 256 stored
 // last events, a channel in which you can expect new events (in form
 // of interface{}, so you need type assertion), and a function to call
@@ -48,7 +76,9 @@ func (e *Events) Subscribe() ([]eventtypes.Message, chan interface{}, func()) {
 
 // SubscribeTopic adds new listener to events, returns slice of 256 stored
 // last events, a channel in which you can expect new events (in form
-// of interface{}, so you need type assertion).
+// of interface{}, so you need type assertion). If the events are persisted to
+// a journal, past events are read from the journal instead, up to the
+// journalReplayLimit newest events.
 func (e *Events) SubscribeTopic(since, until time.Time, ef *Filter) ([]eventtypes.Message, chan interface{}) {
 	eventSubscribers.Inc()
 	e.mu.Lock()
@@ -58,8 +88,6 @@ func (e *Events) SubscribeTopic(since, until time.Time, ef *Filter) ([]eventtype
 		topic = func(m interface{}) bool { return ef.Include(m.(eventtypes.Message)) }
 	}
 
-	buffered := e.loadBufferedEvents(since, until, topic)
-
 	var ch chan interface{}
 	if topic != nil {
 		ch = e.pub.SubscribeTopic(topic)
@@ -68,8 +96,60 @@ func (e *Events) SubscribeTopic(since, until time.Time, ef *Filter) ([]eventtype
 		ch = e.pub.Subscribe()
 	}
 
+	buffered := e.loadBufferedEvents(since, until, topic)
+	journal := e.journal
+
+	// events published from now on are sent to the channel, so they must
+	// not be replayed from the journal.
+	last := time.Now().UnixNano()
+	if n := len(e.events); n > 0 {
+		last = e.events[n-1].TimeNano
+	}
 	e.mu.Unlock()
-	return buffered, ch
+
+	if journal == nil || (since.IsZero() && until.IsZero()) {
+		return buffered, ch
+	}
+
+	// the journal is read without holding the lock, so that publishing
+	// events is not blocked while reading it.
+	if until.IsZero() || until.UnixNano() > last {
+		until = time.Unix(0, last)
+	}
+	journaled, truncated, err := journal.Read(since, until, topic, journalReplayLimit)
+	if err != nil {
+		logrus.WithError(err).Warn("failed to read events journal, only returning buffered events")
+		return buffered, ch
+	}
+	merged := mergeEvents(journaled, buffered)
+	if truncated {
+		logrus.Warnf("more than %d events to replay from the events journal, only returning the newest ones", journalReplayLimit)
+		if len(merged) > journalReplayLimit {
+			merged = merged[len(merged)-journalReplayLimit:]
+		}
+	}
+	return merged, ch
+}
+
+// mergeEvents adds the buffered events to the events read from the journal,
+// as they may not be written to the journal yet.
+func mergeEvents(journaled, buffered []eventtypes.Message) []eventtypes.Message {
+	type eventKey struct {
+		timeNano           int64
+		typ, action, actor string
+	}
+	seen := make(map[eventKey]bool, len(journaled))
+	for _, ev := range journaled {
+		seen[eventKey{ev.TimeNano, ev.Type, ev.Action, ev.Actor.ID}] = true
+	}
+	merged := journaled
+	for _, ev := range buffered {
+		if !seen[eventKey{ev.TimeNano, ev.Type, ev.Action, ev.Actor.ID}] {
+			merged = append(merged, ev)
+		}
+	}
+	sort.SliceStable(merged, func(i, j int) bool { return merged[i].TimeNano < merged[j].TimeNano })
+	return merged
 }
 
 // Evict evicts listener from pubsub
@@ -117,7 +197,13 @@ func (e *Events) PublishMessage(jm eventtypes.Message) {
 	} else {
 		e.events = append(e.events, jm)
 	}
+	journal := e.journal
 	e.mu.Unlock()
+
+	if journal != nil {
+		// the event is written by the journal in the background
+		journal.Append(jm)
+	}
 	e.pub.Publish(jm)
 }
 
@@ -0,0 +1,337 @@
+package events // import "github.com/docker/docker/daemon/events"
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	eventtypes "github.com/docker/docker/api/types/events"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	journalSegmentPrefix = "events-"
+	journalSegmentSuffix = ".log"
+
+	// the journal is split in this many segments, so that retention can
+	// discard old events without rewriting the whole journal.
+	journalSegments = 8
+	// size of the segments if the journal has no maximum size.
+	defaultJournalSegmentSize = 16 * 1024 * 1024
+	// minimum size of the segments, so that a small maximum size does not
+	// rotate segments on every event. The journal may then grow past its
+	// maximum size by up to one segment.
+	minJournalSegmentSize = 64 * 1024
+	// number of events waiting to be written to the journal.
+	journalQueueSize = 1024
+)
+
+// Journal is an append-only, on-disk log of events. Events are stored as JSON
+// lines in segment files, which are removed when the journal grows past its
+// maximum size, or when all of their events are older than its maximum age.
+type Journal struct {
+	mu          sync.Mutex
+	dir         string
+	maxSize     int64
+	maxAge      time.Duration
+	segmentSize int64
+
+	segments []string // segment file names, oldest first
+	sizes    map[string]int64
+	current  *os.File
+
+	queue     chan eventtypes.Message
+	closing   chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewJournal opens the events journal in dir, creating it if needed. A zero
+// maxSize or maxAge disables the corresponding retention rule.
+func NewJournal(dir string, maxSize int64, maxAge time.Duration) (*Journal, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, errors.Wrap(err, "failed to create events journal directory")
+	}
+	j := &Journal{
+		dir:         dir,
+		maxSize:     maxSize,
+		maxAge:      maxAge,
+		sizes:       make(map[string]int64),
+		segmentSize: defaultJournalSegmentSize,
+		queue:       make(chan eventtypes.Message, journalQueueSize),
+		closing:     make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	if maxSize > 0 {
+		j.segmentSize = maxSize / journalSegments
+		if j.segmentSize < minJournalSegmentSize {
+			j.segmentSize = minJournalSegmentSize
+		}
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read events journal directory")
+	}
+	for _, fi := range files {
+		if !isJournalSegment(fi.Name()) {
+			continue
+		}
+		j.segments = append(j.segments, fi.Name())
+		j.sizes[fi.Name()] = fi.Size()
+	}
+	sort.Strings(j.segments)
+
+	j.mu.Lock()
+	j.applyRetention(time.Now())
+	j.mu.Unlock()
+
+	go j.run()
+	return j, nil
+}
+
+// Append queues an event to be written to the journal. It only blocks if the
+// queue is full, and drops the event if the journal is closed.
+func (j *Journal) Append(ev eventtypes.Message) {
+	select {
+	case j.queue <- ev:
+	case <-j.closing:
+	}
+}
+
+// run writes the queued events to the journal until it's closed.
+func (j *Journal) run() {
+	defer close(j.done)
+	for {
+		select {
+		case ev := <-j.queue:
+			j.writeQueued(ev)
+		case <-j.closing:
+			// write the events queued before the journal was closed
+			for {
+				select {
+				case ev := <-j.queue:
+					j.writeQueued(ev)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (j *Journal) writeQueued(ev eventtypes.Message) {
+	if err := j.Write(ev); err != nil {
+		logrus.WithError(err).Warn("failed to write event to events journal")
+	}
+}
+
+// Write appends an event to the journal.
+func (j *Journal) Write(ev eventtypes.Message) error {
+	b, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	name := j.currentSegment()
+	if j.current == nil || j.sizes[name] >= j.segmentSize {
+		if err := j.rotate(ev.TimeNano); err != nil {
+			return err
+		}
+		name = j.currentSegment()
+	}
+	n, err := j.current.Write(b)
+	j.sizes[name] += int64(n)
+	return err
+}
+
+// Read returns the events of the journal emitted between since and until,
+// oldest first. A zero until means no upper bound. It filters the events with
+// a topic function if it's not nil, otherwise it returns all events. At most
+// limit events, the newest ones, are returned if limit is positive, in which
+// case it also returns whether older events were left out.
+//
+// The segments are read without holding the lock of the journal, so that
+// writes are not blocked while reading.
+func (j *Journal) Read(since, until time.Time, topic func(interface{}) bool, limit int) ([]eventtypes.Message, bool, error) {
+	j.mu.Lock()
+	segments := make([]string, len(j.segments))
+	copy(segments, j.segments)
+	j.mu.Unlock()
+
+	var sinceNano, untilNano int64
+	if !since.IsZero() {
+		sinceNano = since.UnixNano()
+	}
+	if !until.IsZero() {
+		untilNano = until.UnixNano()
+	}
+
+	var (
+		events    []eventtypes.Message
+		oldest    int // index of the oldest event once events is full
+		truncated bool
+	)
+	for i, name := range segments {
+		// segments are named after their first event, so the next segment
+		// tells whether this one may contain events after since.
+		if i+1 < len(segments) && segmentStart(segments[i+1]) < sinceNano {
+			continue
+		}
+		if untilNano > 0 && segmentStart(name) > untilNano {
+			break
+		}
+		if err := j.readSegment(name, func(ev eventtypes.Message) bool {
+			if ev.TimeNano < sinceNano || (untilNano > 0 && ev.TimeNano > untilNano) {
+				return true
+			}
+			if topic != nil && !topic(ev) {
+				return true
+			}
+			if limit > 0 && len(events) == limit {
+				// events is used as a ring buffer of the newest events
+				events[oldest] = ev
+				oldest = (oldest + 1) % limit
+				truncated = true
+				return true
+			}
+			events = append(events, ev)
+			return true
+		}); err != nil {
+			return nil, false, err
+		}
+	}
+	if oldest > 0 {
+		events = append(append(make([]eventtypes.Message, 0, len(events)), events[oldest:]...), events[:oldest]...)
+	}
+	return events, truncated, nil
+}
+
+// Close closes the journal, after writing the queued events.
+func (j *Journal) Close() error {
+	j.closeOnce.Do(func() { close(j.closing) })
+	<-j.done
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.current == nil {
+		return nil
+	}
+	err := j.current.Close()
+	j.current = nil
+	return err
+}
+
+// readSegment calls fn for each event of the segment, until fn returns false.
+func (j *Journal) readSegment(name string, fn func(eventtypes.Message) bool) error {
+	f, err := os.Open(filepath.Join(j.dir, name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	s.Buffer(make([]byte, 64*1024), 1024*1024)
+	for s.Scan() {
+		var ev eventtypes.Message
+		if err := json.Unmarshal(s.Bytes(), &ev); err != nil {
+			// a partial line may be left behind if the daemon crashed while
+			// writing an event.
+			logrus.WithError(err).WithField("segment", name).Debug("skipping invalid entry in events journal")
+			continue
+		}
+		if !fn(ev) {
+			return nil
+		}
+	}
+	return s.Err()
+}
+
+func (j *Journal) currentSegment() string {
+	if len(j.segments) == 0 {
+		return ""
+	}
+	return j.segments[len(j.segments)-1]
+}
+
+// rotate closes the current segment and starts a new one, named after the
+// first event it will contain.
+func (j *Journal) rotate(timeNano int64) error {
+	if j.current != nil {
+		if err := j.current.Close(); err != nil {
+			logrus.WithError(err).Warn("failed to close events journal segment")
+		}
+		j.current = nil
+	}
+
+	name := segmentName(timeNano)
+	if last := j.currentSegment(); last != "" && name <= last {
+		// keep segments ordered, even if the clock went backwards
+		name = segmentName(segmentStart(last) + 1)
+	}
+	f, err := os.OpenFile(filepath.Join(j.dir, name), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return errors.Wrap(err, "failed to create events journal segment")
+	}
+	j.current = f
+	j.segments = append(j.segments, name)
+	j.sizes[name] = 0
+
+	j.applyRetention(time.Now())
+	return nil
+}
+
+// applyRetention removes the oldest segments until the journal fits within
+// its maximum size, and segments that only hold events older than its
+// maximum age. The current segment is never removed.
+func (j *Journal) applyRetention(now time.Time) {
+	var total int64
+	for _, name := range j.segments {
+		total += j.sizes[name]
+	}
+	for len(j.segments) > 1 {
+		oldest, next := j.segments[0], j.segments[1]
+		tooBig := j.maxSize > 0 && total > j.maxSize
+		tooOld := j.maxAge > 0 && now.Sub(time.Unix(0, segmentStart(next))) > j.maxAge
+		if !tooBig && !tooOld {
+			break
+		}
+		if err := os.Remove(filepath.Join(j.dir, oldest)); err != nil && !os.IsNotExist(err) {
+			logrus.WithError(err).WithField("segment", oldest).Warn("failed to remove events journal segment")
+			break
+		}
+		total -= j.sizes[oldest]
+		delete(j.sizes, oldest)
+		j.segments = j.segments[1:]
+	}
+}
+
+func segmentName(timeNano int64) string {
+	return fmt.Sprintf("%s%020d%s", journalSegmentPrefix, timeNano, journalSegmentSuffix)
+}
+
+func segmentStart(name string) int64 {
+	var timeNano int64
+	fmt.Sscanf(strings.TrimSuffix(strings.TrimPrefix(name, journalSegmentPrefix), journalSegmentSuffix), "%d", &timeNano)
+	return timeNano
+}
+
+func isJournalSegment(name string) bool {
+	return strings.HasPrefix(name, journalSegmentPrefix) && strings.HasSuffix(name, journalSegmentSuffix)
+}
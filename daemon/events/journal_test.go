@@ -0,0 +1,250 @@
+package events // import "github.com/docker/docker/daemon/events"
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	eventtypes "github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+)
+
+func newJournalEvent(id string, ts time.Time) eventtypes.Message {
+	return eventtypes.Message{
+		Type:     eventtypes.ContainerEventType,
+		Action:   "destroy",
+		Actor:    eventtypes.Actor{ID: id},
+		Time:     ts.Unix(),
+		TimeNano: ts.UnixNano(),
+	}
+}
+
+func TestJournalReadAfterReopen(t *testing.T) {
+	dir, err := ioutil.TempDir("", "events-journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	j, err := NewJournal(dir, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now().Add(-time.Hour)
+	for i := 0; i < 10; i++ {
+		if err := j.Write(newJournalEvent(fmt.Sprintf("c%d", i), start.Add(time.Duration(i)*time.Minute))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := j.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	j, err = NewJournal(dir, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+
+	events, _, err := j.Read(start.Add(5*time.Minute), start.Add(7*time.Minute), nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(events))
+	}
+	if events[0].Actor.ID != "c5" || events[2].Actor.ID != "c7" {
+		t.Fatalf("unexpected events: %v", events)
+	}
+}
+
+func TestJournalRetention(t *testing.T) {
+	dir, err := ioutil.TempDir("", "events-journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	j, err := NewJournal(dir, 8*1024, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+
+	start := time.Now()
+	for i := 0; i < 1000; i++ {
+		if err := j.Write(newJournalEvent(fmt.Sprintf("c%d", i), start.Add(time.Duration(i)*time.Millisecond))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var total int64
+	for _, size := range j.sizes {
+		total += size
+	}
+	if total > 8*1024+j.segmentSize {
+		t.Fatalf("expected the journal to be at most %d bytes, got %d", 8*1024+j.segmentSize, total)
+	}
+
+	events, _, err := j.Read(start, time.Time{}, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) == 0 || len(events) == 1000 {
+		t.Fatalf("expected old events to be discarded, got %d events", len(events))
+	}
+	if last := events[len(events)-1]; last.Actor.ID != "c999" {
+		t.Fatalf("expected the last event to be c999, got %s", last.Actor.ID)
+	}
+
+	// segments older than the maximum age are discarded when rotating
+	j.maxAge = time.Minute
+	j.applyRetention(start.Add(time.Hour))
+	if len(j.segments) != 1 {
+		t.Fatalf("expected 1 segment, got %d", len(j.segments))
+	}
+
+	// small journals do not rotate segments on every event
+	small, err := NewJournal(filepath.Join(dir, "small"), 1024, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer small.Close()
+	if small.segmentSize != minJournalSegmentSize {
+		t.Fatalf("expected segments of %d bytes, got %d", minJournalSegmentSize, small.segmentSize)
+	}
+}
+
+func TestSubscribeTopicFromJournal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "events-journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	j, err := NewJournal(dir, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now().Add(-time.Hour)
+	for i := 0; i < eventsLimit+10; i++ {
+		if err := j.Write(newJournalEvent(fmt.Sprintf("c%03d", i), start.Add(time.Duration(i)*time.Second))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// events written before a restart are replayed from the journal
+	e := New()
+	e.SetJournal(j)
+	defer e.Close()
+
+	f := NewFilter(filters.NewArgs(filters.Arg("container", "c001")))
+	buffered, l := e.SubscribeTopic(start, time.Now(), f)
+	defer e.Evict(l)
+	if len(buffered) != 1 || buffered[0].Actor.ID != "c001" {
+		t.Fatalf("expected event for c001, got %v", buffered)
+	}
+
+	buffered, l2 := e.SubscribeTopic(start, time.Now(), nil)
+	defer e.Evict(l2)
+	if len(buffered) != eventsLimit+10 {
+		t.Fatalf("expected %d events, got %d", eventsLimit+10, len(buffered))
+	}
+}
+
+func TestJournalReadLimit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "events-journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	j, err := NewJournal(dir, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+
+	start := time.Now().Add(-time.Hour)
+	for i := 0; i < 10; i++ {
+		if err := j.Write(newJournalEvent(fmt.Sprintf("c%d", i), start.Add(time.Duration(i)*time.Second))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	events, truncated, err := j.Read(start, time.Time{}, nil, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !truncated || len(events) != 4 || events[0].Actor.ID != "c6" || events[3].Actor.ID != "c9" {
+		t.Fatalf("expected the 4 newest events, got %v (truncated: %v)", events, truncated)
+	}
+
+	events, truncated, err = j.Read(start, start.Add(4*time.Second), nil, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !truncated || len(events) != 4 || events[0].Actor.ID != "c1" || events[3].Actor.ID != "c4" {
+		t.Fatalf("expected the 4 newest events until c4, got %v (truncated: %v)", events, truncated)
+	}
+
+	events, truncated, err = j.Read(start, time.Time{}, nil, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if truncated || len(events) != 10 {
+		t.Fatalf("expected 10 events, got %d (truncated: %v)", len(events), truncated)
+	}
+}
+
+func TestPublishToJournal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "events-journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	j, err := NewJournal(dir, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := New()
+	e.SetJournal(j)
+
+	start := time.Now().Add(-time.Minute)
+	for i := 0; i < 10; i++ {
+		e.PublishMessage(newJournalEvent(fmt.Sprintf("c%d", i), start.Add(time.Duration(i)*time.Second)))
+	}
+
+	// events are replayed whether they were written to the journal yet or not
+	buffered, l := e.SubscribeTopic(start, time.Time{}, nil)
+	e.Evict(l)
+	if len(buffered) != 10 {
+		t.Fatalf("expected 10 events, got %d", len(buffered))
+	}
+	for i, ev := range buffered {
+		if ev.Actor.ID != fmt.Sprintf("c%d", i) {
+			t.Fatalf("unexpected events: %v", buffered)
+		}
+	}
+
+	// queued events are written when the journal is closed
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+	j, err = NewJournal(dir, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+	events, _, err := j.Read(start, time.Time{}, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 10 {
+		t.Fatalf("expected 10 events in the journal, got %d", len(events))
+	}
+}
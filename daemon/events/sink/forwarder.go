@@ -0,0 +1,193 @@
+// Package sink forwards daemon events to external systems, such as webhooks
+// and "eventsink" plugins.
+package sink // import "github.com/docker/docker/daemon/events/sink"
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	eventtypes "github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/daemon/events"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultQueueSize  = 1024
+	defaultMaxRetries = 5
+	maxBatchSize      = 100
+	initialRetryDelay = 100 * time.Millisecond
+	maxRetryDelay     = 30 * time.Second
+)
+
+// Sink delivers events to an external system.
+type Sink interface {
+	// Name identifies the sink in logs.
+	Name() string
+	// Send delivers a batch of events. If it returns an error, the batch is
+	// sent again later.
+	Send(ctx context.Context, events []eventtypes.Message) error
+}
+
+// Config configures how events are forwarded to a sink.
+type Config struct {
+	// Filter selects the events to forward. All events are forwarded if nil.
+	Filter *events.Filter
+	// QueueSize is the maximum number of events waiting to be delivered.
+	// The oldest events are dropped when the queue is full.
+	QueueSize int
+	// MaxRetries is the number of times delivery of a batch of events is
+	// retried before the batch is dropped.
+	MaxRetries int
+}
+
+// Forwarder subscribes to daemon events and delivers them to a sink.
+type Forwarder struct {
+	sink       Sink
+	events     *events.Events
+	listener   chan interface{}
+	queue      chan eventtypes.Message
+	maxRetries int
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// NewForwarder creates a forwarder which starts delivering the events
+// published to e to the sink.
+func NewForwarder(e *events.Events, s Sink, config Config) *Forwarder {
+	if config.QueueSize <= 0 {
+		config.QueueSize = defaultQueueSize
+	}
+	if config.MaxRetries <= 0 {
+		config.MaxRetries = defaultMaxRetries
+	}
+
+	_, l := e.SubscribeTopic(time.Time{}, time.Time{}, config.Filter)
+	f := &Forwarder{
+		sink:       s,
+		events:     e,
+		listener:   l,
+		queue:      make(chan eventtypes.Message, config.QueueSize),
+		maxRetries: config.MaxRetries,
+		stop:       make(chan struct{}),
+	}
+	f.wg.Add(2)
+	go f.enqueue()
+	go f.deliver()
+	return f
+}
+
+// Close stops forwarding events. Events which were not delivered yet are
+// discarded.
+func (f *Forwarder) Close() {
+	f.stopOnce.Do(func() {
+		f.events.Evict(f.listener)
+		close(f.stop)
+	})
+	f.wg.Wait()
+}
+
+// enqueue moves events from the subscription to the queue, so that a slow
+// sink never blocks the publisher.
+func (f *Forwarder) enqueue() {
+	defer f.wg.Done()
+	defer close(f.queue)
+
+	for ev := range f.listener {
+		msg, ok := ev.(eventtypes.Message)
+		if !ok {
+			continue
+		}
+		select {
+		case f.queue <- msg:
+			continue
+		default:
+		}
+
+		// the queue is full; drop the oldest event to make room
+		select {
+		case <-f.queue:
+			droppedEventsCounter.Inc()
+			logrus.WithField("sink", f.sink.Name()).Warn("event sink queue is full, dropping oldest event")
+		default:
+		}
+		select {
+		case f.queue <- msg:
+		default:
+			droppedEventsCounter.Inc()
+		}
+	}
+}
+
+func (f *Forwarder) deliver() {
+	defer f.wg.Done()
+
+	for {
+		var batch []eventtypes.Message
+		select {
+		case <-f.stop:
+			return
+		case msg, ok := <-f.queue:
+			if !ok {
+				return
+			}
+			batch = append(batch, msg)
+		}
+	fill:
+		for len(batch) < maxBatchSize {
+			select {
+			case msg, ok := <-f.queue:
+				if !ok {
+					break fill
+				}
+				batch = append(batch, msg)
+			default:
+				break fill
+			}
+		}
+
+		if !f.send(batch) {
+			droppedEventsCounter.Inc(float64(len(batch)))
+		}
+	}
+}
+
+// send delivers a batch of events, retrying with an exponential backoff.
+// It returns false if the batch could not be delivered.
+func (f *Forwarder) send(batch []eventtypes.Message) bool {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-f.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	delay := initialRetryDelay
+	for attempt := 0; ; attempt++ {
+		err := f.sink.Send(ctx, batch)
+		if err == nil {
+			sentEventsCounter.Inc(float64(len(batch)))
+			return true
+		}
+		if attempt >= f.maxRetries {
+			logrus.WithError(err).WithField("sink", f.sink.Name()).Errorf("failed to deliver %d events, dropping them", len(batch))
+			return false
+		}
+		logrus.WithError(err).WithField("sink", f.sink.Name()).Debugf("failed to deliver events, retrying in %s", delay)
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(delay):
+		}
+		delay *= 2
+		if delay > maxRetryDelay {
+			delay = maxRetryDelay
+		}
+	}
+}
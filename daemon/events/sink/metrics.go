@@ -0,0 +1,15 @@
+package sink // import "github.com/docker/docker/daemon/events/sink"
+
+import "github.com/docker/go-metrics"
+
+var (
+	sentEventsCounter    metrics.Counter
+	droppedEventsCounter metrics.Counter
+)
+
+func init() {
+	ns := metrics.NewNamespace("engine", "daemon", nil)
+	sentEventsCounter = ns.NewCounter("events_sink_sent", "The number of events delivered to event sinks")
+	droppedEventsCounter = ns.NewCounter("events_sink_dropped", "The number of events dropped before they could be delivered to event sinks")
+	metrics.Register(ns)
+}
@@ -0,0 +1,93 @@
+package sink // import "github.com/docker/docker/daemon/events/sink"
+
+import (
+	"context"
+
+	eventtypes "github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/plugingetter"
+	"github.com/docker/docker/pkg/plugins"
+	"github.com/pkg/errors"
+)
+
+// PluginType is the capability implemented by event sink plugins.
+const PluginType = "EventSink"
+
+// pluginRequest is the payload sent to EventSink.Send
+type pluginRequest struct {
+	Events []eventtypes.Message
+}
+
+// pluginResponse is the response of EventSink.Send
+type pluginResponse struct {
+	Err string
+}
+
+type pluginSink struct {
+	name   string
+	getter plugingetter.PluginGetter
+}
+
+// NewPlugin returns a sink which delivers events to the named plugin. The
+// plugin is looked up for every batch, so that it does not need to be
+// enabled when the sink is created.
+func NewPlugin(name string, getter plugingetter.PluginGetter) Sink {
+	return &pluginSink{name: name, getter: getter}
+}
+
+func (p *pluginSink) Name() string {
+	return p.name
+}
+
+func (p *pluginSink) Send(ctx context.Context, events []eventtypes.Message) error {
+	pl, err := p.getter.Get(p.name, PluginType, plugingetter.Lookup)
+	if err != nil {
+		return errors.Wrapf(err, "error looking up event sink plugin %s", p.name)
+	}
+	client, err := makePluginClient(pl)
+	if err != nil {
+		return err
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		var res pluginResponse
+		if err := client.Call(PluginType+".Send", pluginRequest{Events: events}, &res); err != nil {
+			errCh <- err
+			return
+		}
+		if res.Err != "" {
+			errCh <- errors.New(res.Err)
+			return
+		}
+		errCh <- nil
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func makePluginClient(p plugingetter.CompatPlugin) (*plugins.Client, error) {
+	if pc, ok := p.(plugingetter.PluginWithV1Client); ok {
+		return pc.Client(), nil
+	}
+	pa, ok := p.(plugingetter.PluginAddr)
+	if !ok {
+		return nil, errdefs.System(errors.Errorf("got unknown plugin type %T", p))
+	}
+
+	if pa.Protocol() != plugins.ProtocolSchemeHTTPV1 {
+		return nil, errors.Errorf("plugin protocol not supported: %s", pa.Protocol())
+	}
+
+	addr := pa.Addr()
+	client, err := plugins.NewClientWithTimeout(addr.Network()+"://"+addr.String(), nil, pa.Timeout())
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating event sink plugin client")
+	}
+	return client, nil
+}
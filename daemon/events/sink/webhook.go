@@ -0,0 +1,82 @@
+package sink // import "github.com/docker/docker/daemon/events/sink"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	eventtypes "github.com/docker/docker/api/types/events"
+	"github.com/pkg/errors"
+)
+
+const webhookTimeout = 30 * time.Second
+
+type webhookSink struct {
+	url    string
+	name   string
+	client *http.Client
+}
+
+// NewWebhook returns a sink which POSTs batches of events to an HTTP(S)
+// endpoint, as a JSON array. Any 2xx response means the events were
+// delivered.
+func NewWebhook(rawURL string) (Sink, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid event sink URL")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, errors.Errorf("invalid event sink URL %q: scheme must be http or https", rawURL)
+	}
+	return &webhookSink{
+		url:    u.String(),
+		name:   redactURL(u),
+		client: &http.Client{Timeout: webhookTimeout},
+	}, nil
+}
+
+// redactURL returns u without its user info, query and fragment, which may
+// hold credentials, so that it can be logged and used as a metric label.
+func redactURL(u *url.URL) string {
+	redacted := url.URL{Scheme: u.Scheme, Host: u.Host, Path: u.Path}
+	return redacted.String()
+}
+
+func (w *webhookSink) Name() string {
+	return w.name
+}
+
+func (w *webhookSink) Send(ctx context.Context, events []eventtypes.Message) error {
+	body, err := json.Marshal(events)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req.WithContext(ctx))
+	if err != nil {
+		if urlErr, ok := err.(*url.Error); ok {
+			// the error is logged, do not leak the credentials of the URL
+			urlErr.URL = w.name
+		}
+		return err
+	}
+	defer resp.Body.Close()
+	// drain the body so that the connection can be reused
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("event sink returned %s", resp.Status)
+	}
+	return nil
+}
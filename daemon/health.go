@@ -3,10 +3,17 @@ package daemon // import "github.com/docker/docker/daemon"
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
 	"runtime"
+	"strconv"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/docker/docker/api/types"
@@ -44,7 +51,8 @@ const (
 const (
 	// Exit status codes that can be returned by the probe command.
 
-	exitStatusHealthy = 0 // Container is healthy
+	exitStatusHealthy   = 0 // Container is healthy
+	exitStatusUnhealthy = 1 // Container is unhealthy
 )
 
 // probe implementations know how to run a particular type of probe.
@@ -114,6 +122,210 @@ func (p *cmdProbe) run(ctx context.Context, d *Daemon, cntr *container.Container
 	}, nil
 }
 
+// httpProbe implements the "HTTP" probe type.
+type httpProbe struct{}
+
+// Send an HTTP GET request to the healthcheck URL from inside the container's
+// network namespace. The container is considered healthy if the response status
+// matches the expected status, or is a 2xx or 3xx status if none was given.
+func (p *httpProbe) run(ctx context.Context, d *Daemon, cntr *container.Container) (*types.HealthcheckResult, error) {
+	target, expectedStatus, err := parseHTTPProbe(cntr.Config.Healthcheck.Test)
+	if err != nil {
+		return nil, err
+	}
+	dial, err := d.containerDialer(cntr)
+	if err != nil {
+		return nil, err
+	}
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext:       dial,
+			DisableKeepAlives: true,
+			// Like other orchestrators, do not verify certificates; the endpoint
+			// is usually served with a self-signed certificate.
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+		// Report redirects as-is instead of following them.
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	req, err := http.NewRequest(http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return &types.HealthcheckResult{
+			End:      time.Now(),
+			ExitCode: exitStatusUnhealthy,
+			Output:   err.Error(),
+		}, nil
+	}
+	defer resp.Body.Close()
+
+	output := &limitedBuffer{}
+	fmt.Fprintf(output, "HTTP GET %s: %s\n", target, resp.Status)
+	io.Copy(output, io.LimitReader(resp.Body, maxOutputLen))
+
+	exitCode := exitStatusUnhealthy
+	if expectedStatus != 0 && resp.StatusCode == expectedStatus ||
+		expectedStatus == 0 && resp.StatusCode >= 200 && resp.StatusCode < 400 {
+		exitCode = exitStatusHealthy
+	}
+	return &types.HealthcheckResult{
+		End:      time.Now(),
+		ExitCode: exitCode,
+		Output:   output.String(),
+	}, nil
+}
+
+// tcpProbe implements the "TCP" probe type.
+type tcpProbe struct{}
+
+// Open a TCP connection to the healthcheck address from inside the container's
+// network namespace. The container is considered healthy if the connection
+// is accepted.
+func (p *tcpProbe) run(ctx context.Context, d *Daemon, cntr *container.Container) (*types.HealthcheckResult, error) {
+	addr, err := parseTCPProbe(cntr.Config.Healthcheck.Test)
+	if err != nil {
+		return nil, err
+	}
+	dial, err := d.containerDialer(cntr)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := dial(ctx, "tcp", addr)
+	if err != nil {
+		return &types.HealthcheckResult{
+			End:      time.Now(),
+			ExitCode: exitStatusUnhealthy,
+			Output:   err.Error(),
+		}, nil
+	}
+	conn.Close()
+	return &types.HealthcheckResult{
+		End:      time.Now(),
+		ExitCode: exitStatusHealthy,
+		Output:   fmt.Sprintf("TCP connection to %s succeeded", addr),
+	}, nil
+}
+
+// parseHTTPProbe returns the URL and the expected status code (zero if not
+// set) of an {"HTTP", url, [expectedStatus]} healthcheck test.
+func parseHTTPProbe(test []string) (string, int, error) {
+	if len(test) < 2 || len(test) > 3 {
+		return "", 0, fmt.Errorf("HTTP healthcheck requires a URL and an optional expected status code")
+	}
+	target := test[1]
+	u, err := url.Parse(target)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return "", 0, fmt.Errorf("invalid URL for HTTP healthcheck: %q", target)
+	}
+	if err := validateProbeHost(u.Hostname()); err != nil {
+		return "", 0, err
+	}
+	if len(test) == 2 {
+		return target, 0, nil
+	}
+	status, err := strconv.Atoi(test[2])
+	if err != nil || status < 100 || status > 599 {
+		return "", 0, fmt.Errorf("invalid expected status code for HTTP healthcheck: %q", test[2])
+	}
+	return target, status, nil
+}
+
+// parseTCPProbe returns the address to connect to for a {"TCP", port} or
+// {"TCP", host:port} healthcheck test. A bare port refers to the container's
+// loopback interface.
+func parseTCPProbe(test []string) (string, error) {
+	if len(test) != 2 {
+		return "", fmt.Errorf("TCP healthcheck requires a port or host:port")
+	}
+	addr := test[1]
+	if _, err := strconv.ParseUint(addr, 10, 16); err == nil {
+		addr = net.JoinHostPort("127.0.0.1", addr)
+	}
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", fmt.Errorf("invalid address for TCP healthcheck: %q", test[1])
+	}
+	if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+		return "", fmt.Errorf("invalid port for TCP healthcheck: %q", port)
+	}
+	if err := validateProbeHost(host); err != nil {
+		return "", err
+	}
+	return addr, nil
+}
+
+// validateProbeHost checks that the host of an HTTP or TCP healthcheck is an
+// IP address or "localhost". Host names are not supported, as the daemon would
+// resolve them with its own configuration instead of the container's.
+func validateProbeHost(host string) error {
+	if host == "localhost" || net.ParseIP(host) != nil {
+		return nil
+	}
+	return fmt.Errorf("invalid host for healthcheck: %q: only IP addresses and localhost are supported", host)
+}
+
+// probeDialAddress returns the address to dial for addr, with "localhost"
+// replaced by the loopback address.
+func probeDialAddress(addr string) (string, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", err
+	}
+	if err := validateProbeHost(host); err != nil {
+		return "", err
+	}
+	if host == "localhost" {
+		host = "127.0.0.1"
+	}
+	return net.JoinHostPort(host, port), nil
+}
+
+// containerDialer returns a function that dials connections from inside the
+// network namespace of the container, so that the HTTP and TCP probes see the
+// same network as a process running in the container.
+func (d *Daemon) containerDialer(c *container.Container) (func(context.Context, string, string) (net.Conn, error), error) {
+	if d.netController == nil {
+		return nil, fmt.Errorf("networking is not available for container %s", c.ID)
+	}
+	nc := c
+	if c.HostConfig != nil && c.HostConfig.NetworkMode.IsContainer() {
+		var err error
+		nc, err = d.getNetworkedContainer(c.ID, c.HostConfig.NetworkMode.ConnectedContainer())
+		if err != nil {
+			return nil, err
+		}
+	}
+	sb := d.getNetworkSandbox(nc)
+	if sb == nil || sb.Key() == "" {
+		return nil, fmt.Errorf("no network sandbox found for container %s", c.ID)
+	}
+	key := sb.Key()
+	// Disable dual-stack fast fallback so that connections are always created
+	// on the goroutine running inside the namespace.
+	dialer := &net.Dialer{FallbackDelay: -1}
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		// the address is not resolved, as the resolver would not run in the
+		// network namespace of the container. HTTP redirects may lead to
+		// other addresses, so they are checked here as well.
+		addr, err := probeDialAddress(addr)
+		if err != nil {
+			return nil, err
+		}
+		var conn net.Conn
+		if nsErr := runInNetworkNamespace(key, func() {
+			conn, err = dialer.DialContext(ctx, network, addr)
+		}); nsErr != nil {
+			return nil, nsErr
+		}
+		return conn, err
+	}, nil
+}
+
 // Update the container's Status.Health struct based on the latest probe's result.
 func handleProbeResult(d *Daemon, c *container.Container, result *types.HealthcheckResult, done chan struct{}) {
 	c.Lock()
@@ -179,6 +391,44 @@ func handleProbeResult(d *Daemon, c *container.Container, result *types.Healthch
 	current := h.Status()
 	if oldStatus != current {
 		d.LogContainerEvent(c, "health_status: "+current)
+
+		if current == types.Unhealthy && c.HostConfig != nil && c.HostConfig.RestartPolicy.OnUnhealthy && !h.UnhealthyRestart() {
+			h.SetUnhealthyRestart(true)
+			d.LogContainerEvent(c, "health_restart")
+			go d.stopUnhealthyContainer(c)
+		}
+	}
+}
+
+// stopUnhealthyContainer stops a container that turned unhealthy. Unlike
+// containerStop, the container is not marked as manually stopped, so that
+// it is restarted by the restart manager, with the usual backoff.
+func (d *Daemon) stopUnhealthyContainer(c *container.Container) {
+	stopSignal := c.StopSignal()
+	c.Lock()
+	if !c.Running || c.Paused {
+		c.Unlock()
+		return
+	}
+	if err := d.kill(c, stopSignal); err != nil {
+		logrus.WithError(err).WithField("container", c.ID).Warn("failed to stop unhealthy container")
+	}
+	c.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(c.StopTimeout())*time.Second)
+	defer cancel()
+	if status := <-c.Wait(ctx, container.WaitConditionNotRunning); status.Err() == nil {
+		return
+	}
+
+	logrus.Infof("Unhealthy container %s failed to exit within %d seconds of signal %d - using the force", c.ID, c.StopTimeout(), stopSignal)
+	c.Lock()
+	defer c.Unlock()
+	if !c.Running {
+		return
+	}
+	if err := d.kill(c, int(syscall.SIGKILL)); err != nil {
+		logrus.WithError(err).WithField("container", c.ID).Warn("failed to kill unhealthy container")
 	}
 }
 
@@ -257,10 +507,14 @@ func getProbe(c *container.Container) probe {
 		return &cmdProbe{shell: false}
 	case "CMD-SHELL":
 		return &cmdProbe{shell: true}
+	case "HTTP":
+		return &httpProbe{}
+	case "TCP":
+		return &tcpProbe{}
 	case "NONE":
 		return nil
 	default:
-		logrus.Warnf("Unknown healthcheck type '%s' (expected 'CMD') in container %s", config.Test[0], c.ID)
+		logrus.Warnf("Unknown healthcheck type '%s' (expected 'CMD', 'CMD-SHELL', 'HTTP' or 'TCP') in container %s", config.Test[0], c.ID)
 		return nil
 	}
 }
@@ -300,6 +554,7 @@ func (d *Daemon) initHealthMonitor(c *container.Container) {
 
 	if h := c.State.Health; h != nil {
 		h.SetStatus(types.Starting)
+		h.SetUnhealthyRestart(false)
 		h.FailingStreak = 0
 	} else {
 		h := &container.Health{}
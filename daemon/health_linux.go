@@ -0,0 +1,44 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"runtime"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netns"
+)
+
+// runInNetworkNamespace runs f on an OS thread that has joined the network
+// namespace at path. Sockets created by f belong to that namespace, and can
+// still be used after f returns.
+func runInNetworkNamespace(path string, f func()) error {
+	runtime.LockOSThread()
+
+	origin, err := netns.Get()
+	if err != nil {
+		runtime.UnlockOSThread()
+		return errors.Wrap(err, "failed to get current network namespace")
+	}
+	defer origin.Close()
+
+	target, err := netns.GetFromPath(path)
+	if err != nil {
+		runtime.UnlockOSThread()
+		return errors.Wrapf(err, "failed to get network namespace %q", path)
+	}
+	defer target.Close()
+
+	if err := netns.Set(target); err != nil {
+		runtime.UnlockOSThread()
+		return errors.Wrapf(err, "failed to join network namespace %q", path)
+	}
+
+	f()
+
+	if err := netns.Set(origin); err != nil {
+		// Leave the thread locked, so that it is terminated together with the
+		// goroutine instead of being reused in the wrong namespace.
+		return errors.Wrap(err, "failed to restore network namespace")
+	}
+	runtime.UnlockOSThread()
+	return nil
+}
@@ -152,3 +152,130 @@ func TestHealthStates(t *testing.T) {
 		t.Errorf("Expecting FailingStreak=0, but got %d\n", c.State.Health.FailingStreak)
 	}
 }
+
+func TestParseHTTPProbe(t *testing.T) {
+	url, status, err := parseHTTPProbe([]string{"HTTP", "http://localhost:8080/healthz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if url != "http://localhost:8080/healthz" || status != 0 {
+		t.Errorf("Expecting http://localhost:8080/healthz with no status, but got %s %d", url, status)
+	}
+
+	_, status, err = parseHTTPProbe([]string{"HTTP", "https://localhost/", "204"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status != 204 {
+		t.Errorf("Expecting status 204, but got %d", status)
+	}
+
+	for _, test := range [][]string{
+		{"HTTP"},
+		{"HTTP", "localhost:8080"},
+		{"HTTP", "http://localhost", "ok"},
+		{"HTTP", "http://localhost", "999"},
+		{"HTTP", "http://localhost", "200", "extra"},
+		{"HTTP", "http://example.com/healthz"},
+		{"HTTP", "ftp://localhost/"},
+	} {
+		if _, _, err := parseHTTPProbe(test); err == nil {
+			t.Errorf("Expecting error for %q, but got none", test)
+		}
+	}
+}
+
+func TestParseTCPProbe(t *testing.T) {
+	for test, expected := range map[string]string{
+		"5432":           "127.0.0.1:5432",
+		"localhost:5432": "localhost:5432",
+		"[::1]:5432":     "[::1]:5432",
+	} {
+		addr, err := parseTCPProbe([]string{"TCP", test})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if addr != expected {
+			t.Errorf("Expecting %s, but got %s", expected, addr)
+		}
+	}
+
+	for _, test := range [][]string{
+		{"TCP"},
+		{"TCP", "65536"},
+		{"TCP", "localhost"},
+		{"TCP", "localhost:http-alt"},
+		{"TCP", "5432", "extra"},
+		{"TCP", "db:5432"},
+	} {
+		if _, err := parseTCPProbe(test); err == nil {
+			t.Errorf("Expecting error for %q, but got none", test)
+		}
+	}
+}
+
+func TestProbeDialAddress(t *testing.T) {
+	for addr, expected := range map[string]string{
+		"localhost:8080": "127.0.0.1:8080",
+		"10.0.0.1:80":    "10.0.0.1:80",
+		"[::1]:443":      "[::1]:443",
+	} {
+		dialAddr, err := probeDialAddress(addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if dialAddr != expected {
+			t.Errorf("Expecting %s, but got %s", expected, dialAddr)
+		}
+	}
+
+	// host names are not resolved, for example after a redirect
+	if _, err := probeDialAddress("example.com:80"); err == nil {
+		t.Error("Expecting error for a host name, but got none")
+	}
+}
+
+func TestHealthRestartOnUnhealthy(t *testing.T) {
+	e := events.New()
+	_, l, _ := e.Subscribe()
+	defer e.Evict(l)
+
+	c := &container.Container{
+		ID:   "container_id",
+		Name: "container_name",
+		Config: &containertypes.Config{
+			Image:       "image_name",
+			Healthcheck: &containertypes.HealthConfig{Retries: 1},
+		},
+		HostConfig: &containertypes.HostConfig{
+			RestartPolicy: containertypes.RestartPolicy{Name: "always", OnUnhealthy: true},
+		},
+	}
+	reset(c)
+
+	store, err := container.NewViewDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	daemon := &Daemon{
+		EventsService:     e,
+		containersReplica: store,
+	}
+
+	handleProbeResult(daemon, c, &types.HealthcheckResult{ExitCode: 1}, nil)
+
+	for _, expected := range []string{"health_status: unhealthy", "health_restart"} {
+		select {
+		case event := <-l:
+			ev := event.(eventtypes.Message)
+			if ev.Status != expected {
+				t.Errorf("Expecting event %#v, but got %#v\n", expected, ev.Status)
+			}
+		case <-time.After(1 * time.Second):
+			t.Errorf("Expecting event %#v, but got nothing\n", expected)
+		}
+	}
+	if !c.State.Health.UnhealthyRestart() {
+		t.Error("Expecting the container to be restarted")
+	}
+}
@@ -0,0 +1,9 @@
+// +build !linux
+
+package daemon // import "github.com/docker/docker/daemon"
+
+import "errors"
+
+func runInNetworkNamespace(path string, f func()) error {
+	return errors.New("HTTP and TCP healthchecks are not supported on this platform")
+}
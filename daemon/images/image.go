@@ -3,9 +3,12 @@ package images // import "github.com/docker/docker/daemon/images"
 import (
 	"fmt"
 
+	"github.com/containerd/containerd/platforms"
 	"github.com/docker/distribution/reference"
 	"github.com/docker/docker/errdefs"
 	"github.com/docker/docker/image"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
 )
 
 // ErrImageDoesNotExist is error returned when no image can be found for a reference.
@@ -62,3 +65,53 @@ func (i *ImageService) GetImage(refOrID string) (*image.Image, error) {
 
 	return nil, ErrImageDoesNotExist{ref}
 }
+
+// GetImageForPlatform returns the image of platform corresponding to the
+// image referred to by refOrID. A reference refers to the images pulled for
+// each platform side by side, and to its default image for the platforms
+// the default image runs on. If platform is nil, the image is looked up as
+// with GetImage.
+func (i *ImageService) GetImageForPlatform(refOrID string, platform *specs.Platform) (*image.Image, error) {
+	if platform == nil {
+		return i.GetImage(refOrID)
+	}
+	if ref, err := reference.ParseNormalizedNamed(refOrID); err == nil {
+		if digest, err := i.referenceStore.GetPlatform(ref, *platform); err == nil {
+			if img, err := i.imageStore.Get(image.IDFromDigest(digest)); err == nil {
+				return img, nil
+			}
+		}
+	}
+
+	img, err := i.GetImage(refOrID)
+	if err != nil {
+		return nil, err
+	}
+	if !imageMatchesPlatform(img, *platform) {
+		return nil, errdefs.NotFound(errors.Errorf("image %s is not available for platform %s", refOrID, platforms.Format(*platform)))
+	}
+	return img, nil
+}
+
+// imageMatchesPlatform returns whether img runs on platform. The variant is
+// only compared if both the image and platform specify one.
+func imageMatchesPlatform(img *image.Image, platform specs.Platform) bool {
+	imgPlatform := img.Platform()
+	if platform.OS == "" {
+		platform.OS = imgPlatform.OS
+	}
+	if platform.Architecture == "" {
+		platform.Architecture = imgPlatform.Architecture
+	}
+	normalized, imgNormalized := platforms.Normalize(platform), platforms.Normalize(imgPlatform)
+	if normalized.OS != imgNormalized.OS || normalized.Architecture != imgNormalized.Architecture {
+		return false
+	}
+	return platform.Variant == "" || imgPlatform.Variant == "" || normalized.Variant == imgNormalized.Variant
+}
+
+// SetImageLastUsed records that the image with ID id was used by a container
+// now.
+func (i *ImageService) SetImageLastUsed(id image.ID) error {
+	return i.imageStore.SetLastUsed(id)
+}
@@ -101,7 +101,7 @@ func (i *ImageService) ImageDelete(imageRef string, force, prune bool) ([]types.
 			return nil, err
 		}
 
-		parsedRef, err = i.removeImageRef(parsedRef)
+		parsedRef, err = i.removeImageRef(parsedRef, imgID)
 		if err != nil {
 			return nil, err
 		}
@@ -129,7 +129,7 @@ func (i *ImageService) ImageDelete(imageRef string, force, prune bool) ([]types.
 				var remainingRefs []reference.Named
 				for _, repoRef := range repoRefs {
 					if _, repoRefIsCanonical := repoRef.(reference.Canonical); repoRefIsCanonical && parsedRef.Name() == repoRef.Name() {
-						if _, err := i.removeImageRef(repoRef); err != nil {
+						if _, err := i.removeImageRef(repoRef, imgID); err != nil {
 							return records, err
 						}
 
@@ -164,7 +164,7 @@ func (i *ImageService) ImageDelete(imageRef string, force, prune bool) ([]types.
 			}
 
 			for _, repoRef := range repoRefs {
-				parsedRef, err := i.removeImageRef(repoRef)
+				parsedRef, err := i.removeImageRef(repoRef, imgID)
 				if err != nil {
 					return nil, err
 				}
@@ -229,14 +229,16 @@ func isImageIDPrefix(imageID, possiblePrefix string) bool {
 // this daemon's store of repository tag/digest references. The given
 // repositoryRef must not be an image ID but a repository name followed by an
 // optional tag or digest reference. If tag or digest is omitted, the default
-// tag is used. Returns the resolved image reference and an error.
-func (i *ImageService) removeImageRef(ref reference.Named) (reference.Named, error) {
+// tag is used. Only the association of the reference with imgID is removed,
+// the reference keeps referring to the images of other platforms, if any.
+// Returns the resolved image reference and an error.
+func (i *ImageService) removeImageRef(ref reference.Named, imgID image.ID) (reference.Named, error) {
 	ref = reference.TagNameOnly(ref)
 
 	// Ignore the boolean value returned, as far as we're concerned, this
 	// is an idempotent operation and it's okay if the reference didn't
 	// exist in the first place.
-	_, err := i.referenceStore.Delete(ref)
+	_, err := i.referenceStore.DeleteImageReference(ref, imgID.Digest())
 
 	return ref, err
 }
@@ -250,7 +252,7 @@ func (i *ImageService) removeAllReferencesToImageID(imgID image.ID, records *[]t
 	imageRefs := i.referenceStore.References(imgID.Digest())
 
 	for _, imageRef := range imageRefs {
-		parsedRef, err := i.removeImageRef(imageRef)
+		parsedRef, err := i.removeImageRef(imageRef, imgID)
 		if err != nil {
 			return err
 		}
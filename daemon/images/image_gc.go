@@ -0,0 +1,241 @@
+package images // import "github.com/docker/docker/daemon/images"
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/docker/distribution/reference"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/image"
+	"github.com/sirupsen/logrus"
+)
+
+// diskUsage returns the usage, in percent, of the filesystem holding path.
+var diskUsage = getDiskUsage
+
+// GCPolicy is the policy of the garbage collection of unused images.
+type GCPolicy struct {
+	// Root is a path on the filesystem the usage of which is checked.
+	Root string
+	// HighWaterMark is the disk usage, in percent, above which unused
+	// images are collected.
+	HighWaterMark int
+	// LowWaterMark is the disk usage, in percent, down to which unused
+	// images are collected.
+	LowWaterMark int
+	// CheckInterval is the interval between two checks of the disk usage.
+	CheckInterval time.Duration
+	// Schedule is the interval between two collections run even if the
+	// disk usage is below the high water mark. Zero disables scheduled
+	// collections.
+	Schedule time.Duration
+	// KeepLabels holds the labels, "key" or "key=value", of images that
+	// are never collected.
+	KeepLabels []string
+	// KeepReferences holds the reference patterns of images that are never
+	// collected.
+	KeepReferences []string
+	// MinAge is the minimum time since an image was last used, tagged or
+	// created before it is collected.
+	MinAge time.Duration
+}
+
+type imageGC struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// SetGCPolicy starts the garbage collection of unused images with policy,
+// replacing the running one. The garbage collection is stopped if policy is
+// nil.
+func (i *ImageService) SetGCPolicy(policy *GCPolicy) {
+	i.gcMu.Lock()
+	defer i.gcMu.Unlock()
+
+	if i.gc != nil {
+		i.gc.cancel()
+		<-i.gc.done
+		i.gc = nil
+	}
+	if policy == nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	i.gc = &imageGC{cancel: cancel, done: make(chan struct{})}
+	go i.runGC(ctx, *policy, i.gc.done)
+}
+
+func (i *ImageService) runGC(ctx context.Context, policy GCPolicy, done chan struct{}) {
+	defer close(done)
+
+	check := time.NewTicker(policy.CheckInterval)
+	defer check.Stop()
+	var scheduled <-chan time.Time
+	if policy.Schedule > 0 {
+		schedule := time.NewTicker(policy.Schedule)
+		defer schedule.Stop()
+		scheduled = schedule.C
+	}
+
+	for {
+		var err error
+		select {
+		case <-ctx.Done():
+			return
+		case <-check.C:
+			_, err = i.collectImages(ctx, policy, false)
+		case <-scheduled:
+			_, err = i.collectImages(ctx, policy, true)
+		}
+		if err != nil && err != context.Canceled {
+			logrus.Warnf("Image garbage collection failed: %v", err)
+		}
+	}
+}
+
+// collectImages removes unused images, least recently used first, until the
+// disk usage is down to the low water mark. Nothing is removed unless the
+// disk usage is above the high water mark, or, for scheduled collections,
+// above the low water mark.
+func (i *ImageService) collectImages(ctx context.Context, policy GCPolicy, scheduled bool) ([]types.ImageDeleteResponseItem, error) {
+	usage, err := diskUsage(policy.Root)
+	if err != nil {
+		return nil, err
+	}
+	threshold := policy.HighWaterMark
+	if scheduled {
+		threshold = policy.LowWaterMark
+	}
+	if usage <= float64(threshold) {
+		return nil, nil
+	}
+
+	if !atomic.CompareAndSwapInt32(&i.pruneRunning, 0, 1) {
+		return nil, errPruneRunning
+	}
+	defer atomic.StoreInt32(&i.pruneRunning, 0)
+
+	logrus.Infof("Collecting unused images: disk usage of %s is %.1f%%", policy.Root, usage)
+
+	var deleted []types.ImageDeleteResponseItem
+	for _, id := range i.gcCandidates(policy, time.Now()) {
+		select {
+		case <-ctx.Done():
+			return deleted, ctx.Err()
+		default:
+		}
+
+		deleted = append(deleted, i.deleteUnusedImage(id)...)
+
+		if usage, err = diskUsage(policy.Root); err != nil {
+			return deleted, err
+		}
+		if usage <= float64(policy.LowWaterMark) {
+			break
+		}
+	}
+	logrus.Infof("Image garbage collection done: disk usage of %s is %.1f%%", policy.Root, usage)
+	return deleted, nil
+}
+
+// gcCandidates returns the images that may be collected, least recently
+// used first. Images used by containers, parents of other images and images
+// matching a keep-rule of the policy are not collected.
+func (i *ImageService) gcCandidates(policy GCPolicy, now time.Time) []image.ID {
+	used := make(map[image.ID]bool)
+	for _, c := range i.containers.List() {
+		used[c.ImageID] = true
+	}
+
+	lastUsed := make(map[image.ID]time.Time)
+	var candidates []image.ID
+	for id, img := range i.imageStore.Map() {
+		if used[id] || len(i.imageStore.Children(id)) != 0 {
+			continue
+		}
+		if img.Config != nil && matchAnyLabel(policy.KeepLabels, img.Config.Labels) {
+			continue
+		}
+		if matchAnyReference(policy.KeepReferences, i.referenceStore.References(id.Digest())) {
+			continue
+		}
+		t := i.imageLastUsed(img)
+		if now.Sub(t) < policy.MinAge {
+			continue
+		}
+		lastUsed[id] = t
+		candidates = append(candidates, id)
+	}
+
+	sort.Slice(candidates, func(a, b int) bool {
+		return lastUsed[candidates[a]].Before(lastUsed[candidates[b]])
+	})
+	return candidates
+}
+
+// imageLastUsed returns the last time img was pulled, tagged or used by a
+// container, or the time it was created if it never was.
+func (i *ImageService) imageLastUsed(img *image.Image) time.Time {
+	t := img.Created
+	if lastUsed, err := i.imageStore.GetLastUsed(img.ID()); err == nil && lastUsed.After(t) {
+		t = lastUsed
+	}
+	if lastUpdated, err := i.imageStore.GetLastUpdated(img.ID()); err == nil && lastUpdated.After(t) {
+		t = lastUpdated
+	}
+	return t
+}
+
+// deleteUnusedImage removes the image with ID id and all its references.
+// The image is deleted by ID rather than through its references, as a
+// reference may refer by default to another image than the one of the
+// platform id was pulled for.
+func (i *ImageService) deleteUnusedImage(id image.ID) []types.ImageDeleteResponseItem {
+	if conflict := i.checkImageDeleteConflict(id, conflictHard|conflictStoppedContainer); conflict != nil {
+		return nil
+	}
+
+	var records []types.ImageDeleteResponseItem
+	if err := i.removeAllReferencesToImageID(id, &records); err != nil {
+		logrus.Warnf("failed to prune image %s: %v", id, err)
+		return records
+	}
+	if err := i.imageDeleteHelper(id, &records, false, true, false); imageDeleteFailed(id.String(), err) {
+		return records
+	}
+	return records
+}
+
+// matchAnyLabel returns whether labels match one of the "key" or
+// "key=value" keep-rules.
+func matchAnyLabel(rules []string, labels map[string]string) bool {
+	for _, rule := range rules {
+		kv := strings.SplitN(rule, "=", 2)
+		v, ok := labels[kv[0]]
+		if ok && (len(kv) == 1 || v == kv[1]) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchAnyReference returns whether one of refs matches one of the patterns,
+// in its familiar or fully qualified form.
+func matchAnyReference(patterns []string, refs []reference.Named) bool {
+	for _, pattern := range patterns {
+		for _, ref := range refs {
+			if ok, _ := reference.FamiliarMatch(pattern, ref); ok {
+				return true
+			}
+			if ok, _ := path.Match(pattern, ref.String()); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
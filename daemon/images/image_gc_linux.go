@@ -0,0 +1,16 @@
+package images // import "github.com/docker/docker/daemon/images"
+
+import "golang.org/x/sys/unix"
+
+func getDiskUsage(path string) (float64, error) {
+	var st unix.Statfs_t
+	if err := unix.Statfs(path, &st); err != nil {
+		return 0, err
+	}
+	// As df does, blocks reserved to the superuser are not accounted for.
+	used := st.Blocks - st.Bfree
+	if used+st.Bavail == 0 {
+		return 0, nil
+	}
+	return float64(used) * 100 / float64(used+st.Bavail), nil
+}
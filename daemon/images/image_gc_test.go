@@ -0,0 +1,170 @@
+package images // import "github.com/docker/docker/daemon/images"
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/docker/distribution/reference"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/container"
+	daemonevents "github.com/docker/docker/daemon/events"
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+	refstore "github.com/docker/docker/reference"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+type noLayers struct{}
+
+func (noLayers) Get(layer.ChainID) (layer.Layer, error) {
+	return nil, layer.ErrLayerDoesNotExist
+}
+
+func (noLayers) Release(layer.Layer) ([]layer.Metadata, error) {
+	return nil, nil
+}
+
+func newTestImageService(t *testing.T) (*ImageService, func()) {
+	t.Helper()
+	root, err := ioutil.TempDir("", "images-gc")
+	assert.NilError(t, err)
+
+	fs, err := image.NewFSStoreBackend(filepath.Join(root, "imagedb"))
+	assert.NilError(t, err)
+	imageStore, err := image.NewImageStore(fs, map[string]image.LayerGetReleaser{runtime.GOOS: noLayers{}})
+	assert.NilError(t, err)
+	rs, err := refstore.NewReferenceStore(filepath.Join(root, "repositories.json"))
+	assert.NilError(t, err)
+
+	i := &ImageService{
+		containers:     container.NewMemoryStore(),
+		eventsService:  daemonevents.New(),
+		imageStore:     imageStore,
+		referenceStore: rs,
+	}
+	return i, func() { os.RemoveAll(root) }
+}
+
+// createTestImage creates an image without layers, tagged as ref unless it
+// is empty.
+func createTestImage(t *testing.T, i *ImageService, ref string, created time.Time, labels map[string]string) image.ID {
+	t.Helper()
+	config, err := json.Marshal(map[string]interface{}{
+		"created": created,
+		"config":  map[string]interface{}{"Labels": labels},
+		"rootfs":  map[string]string{"type": "layers"},
+		"comment": ref,
+	})
+	assert.NilError(t, err)
+	id, err := i.imageStore.Create(config)
+	assert.NilError(t, err)
+	if ref != "" {
+		named, err := reference.ParseNormalizedNamed(ref)
+		assert.NilError(t, err)
+		assert.NilError(t, i.referenceStore.AddTag(reference.TagNameOnly(named).(reference.NamedTagged), id.Digest(), true))
+	}
+	return id
+}
+
+func TestImageGCCandidates(t *testing.T) {
+	i, cleanup := newTestImageService(t)
+	defer cleanup()
+
+	now := time.Now()
+	old := now.Add(-48 * time.Hour)
+	oldest := createTestImage(t, i, "example.com/oldest", old.Add(-time.Hour), nil)
+	recentlyUsed := createTestImage(t, i, "example.com/recently-used", old.Add(-2*time.Hour), nil)
+	assert.NilError(t, i.SetImageLastUsed(recentlyUsed))
+	dangling := createTestImage(t, i, "", old, nil)
+	createTestImage(t, i, "example.com/young", now.Add(-time.Hour), nil)
+	createTestImage(t, i, "example.com/kept-by-label", old, map[string]string{"keep": "yes"})
+	createTestImage(t, i, "busybox", old, nil)
+	inUse := createTestImage(t, i, "example.com/in-use", old, nil)
+	i.containers.(container.Store).Add("c1", &container.Container{ID: "c1", ImageID: inUse})
+
+	policy := GCPolicy{
+		KeepLabels:     []string{"keep"},
+		KeepReferences: []string{"busybox:*"},
+		MinAge:         24 * time.Hour,
+	}
+	assert.Check(t, is.DeepEqual(i.gcCandidates(policy, now), []image.ID{oldest, dangling}))
+
+	policy.MinAge = 0
+	assert.Check(t, is.DeepEqual(i.gcCandidates(policy, now.Add(time.Hour))[3], recentlyUsed))
+}
+
+func TestCollectImages(t *testing.T) {
+	i, cleanup := newTestImageService(t)
+	defer cleanup()
+
+	old := time.Now().Add(-48 * time.Hour)
+	first := createTestImage(t, i, "example.com/first", old.Add(-2*time.Hour), nil)
+	second := createTestImage(t, i, "example.com/second", old.Add(-time.Hour), nil)
+	third := createTestImage(t, i, "example.com/third", old, nil)
+
+	// each image removed frees 10% of the disk
+	defer func(f func(string) (float64, error)) { diskUsage = f }(diskUsage)
+	base := 50.0
+	diskUsage = func(string) (float64, error) {
+		return base + float64(i.imageStore.Len())*10, nil
+	}
+	policy := GCPolicy{HighWaterMark: 75, LowWaterMark: 62}
+
+	deleted, err := i.collectImages(context.Background(), policy, false)
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(deleted, 4)) // an untag and a delete per image
+	_, err = i.imageStore.Get(first)
+	assert.Check(t, err != nil)
+	_, err = i.imageStore.Get(second)
+	assert.Check(t, err != nil)
+	_, err = i.imageStore.Get(third)
+	assert.Check(t, err)
+
+	// below the high water mark, only scheduled collections remove images
+	base = 60.0
+	deleted, err = i.collectImages(context.Background(), policy, false)
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(deleted, 0))
+	deleted, err = i.collectImages(context.Background(), policy, true)
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(deleted, 2))
+	assert.Check(t, is.Equal(i.imageStore.Len(), 0))
+}
+
+func TestCollectPlatformImage(t *testing.T) {
+	i, cleanup := newTestImageService(t)
+	defer cleanup()
+
+	old := time.Now().Add(-48 * time.Hour)
+	native := createTestImage(t, i, "example.com/multi", old, nil)
+	foreign := createTestImage(t, i, "", old.Add(-time.Hour), nil)
+	ref, err := reference.ParseNormalizedNamed("example.com/multi")
+	assert.NilError(t, err)
+	arm64 := specs.Platform{OS: "linux", Architecture: "arm64"}
+	assert.NilError(t, i.referenceStore.AddPlatformReference(ref, arm64, foreign.Digest()))
+
+	// the image of the foreign platform is collected by ID, the default
+	// image of the reference is kept
+	deleted := i.deleteUnusedImage(foreign)
+	assert.Check(t, is.DeepEqual(deleted, []types.ImageDeleteResponseItem{
+		{Untagged: "example.com/multi:latest"},
+		{Deleted: foreign.String()},
+	}))
+	_, err = i.imageStore.Get(foreign)
+	assert.Check(t, err != nil)
+	_, err = i.imageStore.Get(native)
+	assert.Check(t, err)
+	id, err := i.referenceStore.Get(ref)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(id, native.Digest()))
+	_, err = i.referenceStore.GetPlatform(ref, arm64)
+	assert.Check(t, is.Equal(err, refstore.ErrDoesNotExist))
+}
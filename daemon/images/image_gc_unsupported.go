@@ -0,0 +1,9 @@
+// +build !linux
+
+package images // import "github.com/docker/docker/daemon/images"
+
+import "errors"
+
+func getDiskUsage(path string) (float64, error) {
+	return 0, errors.New("disk usage is not supported on this platform")
+}
@@ -62,6 +62,10 @@ func (i *ImageService) LookupImage(name string) (*types.ImageInspect, error) {
 	if err != nil {
 		return nil, err
 	}
+	lastUsed, err := i.imageStore.GetLastUsed(img.ID())
+	if err != nil {
+		return nil, err
+	}
 
 	imageInspect := &types.ImageInspect{
 		ID:              img.ID().String(),
@@ -82,7 +86,8 @@ func (i *ImageService) LookupImage(name string) (*types.ImageInspect, error) {
 		VirtualSize:     size, // TODO: field unused, deprecate
 		RootFS:          rootFSToAPIType(img.RootFS),
 		Metadata: types.ImageMetadata{
-			LastTagTime: lastUpdated,
+			LastTagTime:  lastUpdated,
+			LastUsedTime: lastUsed,
 		},
 	}
 
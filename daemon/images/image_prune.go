@@ -19,10 +19,11 @@ import (
 )
 
 var imagesAcceptedFilters = map[string]bool{
-	"dangling": true,
-	"label":    true,
-	"label!":   true,
-	"until":    true,
+	"dangling":     true,
+	"label":        true,
+	"label!":       true,
+	"until":        true,
+	"unused-since": true,
 }
 
 // errPruneRunning is returned when a prune request is received while
@@ -57,6 +58,10 @@ func (i *ImageService) ImagesPrune(ctx context.Context, pruneFilters filters.Arg
 	if err != nil {
 		return nil, err
 	}
+	unusedSince, err := getTimeFromPruneFilters(pruneFilters, "unused-since")
+	if err != nil {
+		return nil, err
+	}
 
 	var allImages map[image.ID]*image.Image
 	if danglingOnly {
@@ -85,6 +90,9 @@ func (i *ImageService) ImagesPrune(ctx context.Context, pruneFilters filters.Arg
 			if !until.IsZero() && img.Created.After(until) {
 				continue
 			}
+			if !unusedSince.IsZero() && i.imageLastUsed(img).After(unusedSince) {
+				continue
+			}
 			if img.Config != nil && !matchLabels(pruneFilters, img.Config.Labels) {
 				continue
 			}
@@ -190,22 +198,27 @@ func matchLabels(pruneFilters filters.Args, labels map[string]string) bool {
 }
 
 func getUntilFromPruneFilters(pruneFilters filters.Args) (time.Time, error) {
-	until := time.Time{}
-	if !pruneFilters.Contains("until") {
-		return until, nil
+	return getTimeFromPruneFilters(pruneFilters, "until")
+}
+
+// getTimeFromPruneFilters returns the time of the filter named name, which
+// is either a timestamp or a duration relative to now.
+func getTimeFromPruneFilters(pruneFilters filters.Args, name string) (time.Time, error) {
+	t := time.Time{}
+	if !pruneFilters.Contains(name) {
+		return t, nil
 	}
-	untilFilters := pruneFilters.Get("until")
-	if len(untilFilters) > 1 {
-		return until, fmt.Errorf("more than one until filter specified")
+	timeFilters := pruneFilters.Get(name)
+	if len(timeFilters) > 1 {
+		return t, fmt.Errorf("more than one %s filter specified", name)
 	}
-	ts, err := timetypes.GetTimestamp(untilFilters[0], time.Now())
+	ts, err := timetypes.GetTimestamp(timeFilters[0], time.Now())
 	if err != nil {
-		return until, err
+		return t, err
 	}
 	seconds, nanoseconds, err := timetypes.ParseTimestamps(ts, 0)
 	if err != nil {
-		return until, err
+		return t, err
 	}
-	until = time.Unix(seconds, nanoseconds)
-	return until, nil
+	return time.Unix(seconds, nanoseconds), nil
 }
@@ -16,6 +16,7 @@ import (
 	"github.com/docker/docker/registry"
 	"github.com/opencontainers/go-digest"
 	specs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
 )
 
 // PullImage initiates a pull operation. image is the repository name to pull, and
@@ -48,9 +49,28 @@ func (i *ImageService) PullImage(ctx context.Context, image, tag string, platfor
 
 	err = i.pullImageWithReference(ctx, ref, platform, metaHeaders, authConfig, outStream)
 	imageActions.WithValues("pull").UpdateSince(start)
+	if err == nil {
+		i.setLastUsedByReference(ref, platform)
+	}
 	return err
 }
 
+// setLastUsedByReference records that the image of platform ref points to
+// was just pulled. Nothing is recorded when pulling all the tags of a
+// repository.
+func (i *ImageService) setLastUsedByReference(ref reference.Named, platform *specs.Platform) {
+	if reference.IsNameOnly(ref) {
+		return
+	}
+	img, err := i.GetImageForPlatform(ref.String(), platform)
+	if err != nil {
+		return
+	}
+	if err := i.imageStore.SetLastUsed(img.ID()); err != nil {
+		logrus.Warnf("failed to record last use of image %s: %v", reference.FamiliarString(ref), err)
+	}
+}
+
 func (i *ImageService) pullImageWithReference(ctx context.Context, ref reference.Named, platform *specs.Platform, metaHeaders map[string][]string, authConfig *types.AuthConfig, outStream io.Writer) error {
 	// Include a buffer so that slow client connections don't affect
 	// transfer performance.
@@ -79,6 +99,8 @@ func (i *ImageService) pullImageWithReference(ctx context.Context, ref reference
 		DownloadManager: i.downloadManager,
 		Schema2Types:    distribution.ImageTypes,
 		Platform:        platform,
+		SignaturePolicy: i.signaturePolicy,
+		SignatureStore:  i.signatureStore,
 	}
 
 	err := distribution.Pull(ctx, ref, imagePullConfig)
@@ -5,16 +5,21 @@ import (
 	"io"
 	"time"
 
+	"github.com/docker/distribution/manifest/manifestlist"
 	"github.com/docker/distribution/manifest/schema2"
 	"github.com/docker/distribution/reference"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/distribution"
 	progressutils "github.com/docker/docker/distribution/utils"
+	"github.com/docker/docker/errdefs"
 	"github.com/docker/docker/pkg/progress"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
 )
 
 // PushImage initiates a push operation on the repository named localName.
-func (i *ImageService) PushImage(ctx context.Context, image, tag string, metaHeaders map[string][]string, authConfig *types.AuthConfig, outStream io.Writer) error {
+// Layers are mounted from the repositories of mountFrom if they exist there.
+func (i *ImageService) PushImage(ctx context.Context, image, tag string, mountFrom []string, metaHeaders map[string][]string, authConfig *types.AuthConfig, outStream io.Writer) error {
 	start := time.Now()
 	ref, err := reference.ParseNormalizedNamed(image)
 	if err != nil {
@@ -27,6 +32,82 @@ func (i *ImageService) PushImage(ctx context.Context, image, tag string, metaHea
 			return err
 		}
 	}
+	var mountCandidates []reference.Named
+	for _, repo := range mountFrom {
+		named, err := reference.ParseNormalizedNamed(repo)
+		if err != nil {
+			return errdefs.InvalidParameter(err)
+		}
+		if !reference.IsNameOnly(named) {
+			return errdefs.InvalidParameter(errors.Errorf("invalid mount source %s: must be a repository name", repo))
+		}
+		if reference.Domain(named) != reference.Domain(ref) {
+			return errdefs.InvalidParameter(errors.Errorf("invalid mount source %s: must be in registry %s", repo, reference.Domain(ref)))
+		}
+		mountCandidates = append(mountCandidates, named)
+	}
+
+	// Include a buffer so that slow client connections don't affect
+	// transfer performance.
+	progressChan := make(chan progress.Progress, 100)
+
+	writesDone := make(chan struct{})
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+
+	go func() {
+		progressutils.WriteDistributionProgress(cancelFunc, outStream, progressChan)
+		close(writesDone)
+	}()
+
+	imagePushConfig := i.newImagePushConfig(metaHeaders, authConfig, progress.ChanOutput(progressChan))
+	imagePushConfig.MountCandidates = mountCandidates
+
+	err = distribution.Push(ctx, ref, imagePushConfig)
+	close(progressChan)
+	<-writesDone
+	imageActions.WithValues("push").UpdateSince(start)
+	return err
+}
+
+// PushManifestList pushes the images of request to the repository named
+// image, and then a manifest list of these images tagged as tag.
+func (i *ImageService) PushManifestList(ctx context.Context, image, tag string, request types.ManifestListPushRequest, metaHeaders map[string][]string, authConfig *types.AuthConfig, outStream io.Writer) error {
+	start := time.Now()
+	ref, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return errdefs.InvalidParameter(err)
+	}
+	if tag != "" {
+		ref, err = reference.WithTag(ref, tag)
+		if err != nil {
+			return errdefs.InvalidParameter(err)
+		}
+	}
+	if _, isCanonical := ref.(reference.Canonical); isCanonical {
+		return errdefs.InvalidParameter(errors.New("cannot push a manifest list by digest"))
+	}
+	tagged := reference.TagNameOnly(ref).(reference.NamedTagged)
+
+	mediaType := request.MediaType
+	switch mediaType {
+	case "":
+		mediaType = manifestlist.MediaTypeManifestList
+	case manifestlist.MediaTypeManifestList, ocispec.MediaTypeImageIndex:
+	default:
+		return errdefs.InvalidParameter(errors.Errorf("unsupported manifest list media type: %s", mediaType))
+	}
+	if len(request.Manifests) == 0 {
+		return errdefs.InvalidParameter(errors.New("no image to push in the manifest list"))
+	}
+	var entries []distribution.ManifestListEntry
+	for _, m := range request.Manifests {
+		img, err := i.GetImage(m.Image)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, distribution.ManifestListEntry{ID: img.ID().Digest(), Platform: m.Platform})
+	}
 
 	// Include a buffer so that slow client connections don't affect
 	// transfer performance.
@@ -41,26 +122,32 @@ func (i *ImageService) PushImage(ctx context.Context, image, tag string, metaHea
 		close(writesDone)
 	}()
 
+	imagePushConfig := i.newImagePushConfig(metaHeaders, authConfig, progress.ChanOutput(progressChan))
+
+	err = distribution.PushManifestList(ctx, tagged, entries, mediaType, imagePushConfig)
+	close(progressChan)
+	<-writesDone
+	imageActions.WithValues("push_manifest_list").UpdateSince(start)
+	return err
+}
+
+func (i *ImageService) newImagePushConfig(metaHeaders map[string][]string, authConfig *types.AuthConfig, progressOutput progress.Output) *distribution.ImagePushConfig {
 	imagePushConfig := &distribution.ImagePushConfig{
 		Config: distribution.Config{
 			MetaHeaders:      metaHeaders,
 			AuthConfig:       authConfig,
-			ProgressOutput:   progress.ChanOutput(progressChan),
+			ProgressOutput:   progressOutput,
 			RegistryService:  i.registryService,
 			ImageEventLogger: i.LogImageEvent,
 			MetadataStore:    i.distributionMetadataStore,
 			ImageStore:       distribution.NewImageConfigStoreFromStore(i.imageStore),
 			ReferenceStore:   i.referenceStore,
 		},
-		ConfigMediaType: schema2.MediaTypeImageConfig,
-		LayerStores:     distribution.NewLayerProvidersFromStores(i.layerStores),
-		TrustKey:        i.trustKey,
-		UploadManager:   i.uploadManager,
+		ConfigMediaType:   schema2.MediaTypeImageConfig,
+		LayerStores:       distribution.NewLayerProvidersFromStores(i.layerStores),
+		TrustKey:          i.trustKey,
+		UploadManager:     i.uploadManager,
+		PushStatsRecorder: recordPushStats,
 	}
-
-	err = distribution.Push(ctx, ref, imagePushConfig)
-	close(progressChan)
-	<-writesDone
-	imageActions.WithValues("push").UpdateSince(start)
-	return err
+	return imagePushConfig
 }
@@ -0,0 +1,80 @@
+package images // import "github.com/docker/docker/daemon/images"
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/docker/distribution/reference"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/image/signature"
+)
+
+// VerifyImageSignature checks that img, found by refOrID, is trusted by the
+// signature policy, using the signatures of the local signature store. An
+// image found by ID must be trusted in one of the repositories it is tagged
+// in. Rejected images are logged as "reject" image events.
+func (i *ImageService) VerifyImageSignature(refOrID string, img *image.Image) error {
+	if i.signaturePolicy == nil {
+		return nil
+	}
+
+	var names []reference.Named
+	if named, err := reference.ParseNormalizedNamed(refOrID); err == nil {
+		if id, err := i.referenceStore.Get(named); err == nil && image.IDFromDigest(id) == img.ID() {
+			names = append(names, named)
+		}
+	}
+	if len(names) == 0 {
+		for _, ref := range i.referenceStore.References(img.ID().Digest()) {
+			names = append(names, ref)
+		}
+	}
+
+	var err error
+	if len(names) == 0 {
+		err = i.verifyImageSignature(nil, img)
+	}
+	for _, name := range names {
+		if err = i.verifyImageSignature(name, img); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		i.LogImageEventWithAttributes(img.ID().String(), refOrID, "reject", map[string]string{"reason": err.Error()})
+		return errdefs.Forbidden(fmt.Errorf("image %s rejected by signature policy: %v", refOrID, err))
+	}
+	return nil
+}
+
+// verifyImageSignature checks img against the requirement of the policy for
+// the repository named name, using the digests of the manifests img was
+// pulled from that repository with.
+func (i *ImageService) verifyImageSignature(name reference.Named, img *image.Image) error {
+	req := i.signaturePolicy.Requirement(name)
+	if req == nil {
+		return nil
+	}
+	if req.Type == signature.Reject {
+		return req.Verify("", nil)
+	}
+
+	var lastErr error = errors.New("image has no repository digest to verify")
+	for _, ref := range i.referenceStore.References(img.ID().Digest()) {
+		canonical, ok := ref.(reference.Canonical)
+		if !ok || name == nil || canonical.Name() != name.Name() {
+			continue
+		}
+		var signatures []signature.Signature
+		if i.signatureStore != nil {
+			var err error
+			if signatures, err = i.signatureStore.Get(canonical.Digest()); err != nil {
+				return err
+			}
+		}
+		if lastErr = req.Verify(canonical.Digest(), signatures); lastErr == nil {
+			return nil
+		}
+	}
+	return lastErr
+}
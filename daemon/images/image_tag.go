@@ -36,6 +36,9 @@ func (i *ImageService) TagImageWithReference(imageID image.ID, newTag reference.
 	if err := i.imageStore.SetLastUpdated(imageID); err != nil {
 		return err
 	}
+	if err := i.imageStore.SetLastUsed(imageID); err != nil {
+		return err
+	}
 	i.LogImageEvent(imageID.String(), reference.FamiliarString(newTag), "tag")
 	return nil
 }
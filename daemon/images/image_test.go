@@ -0,0 +1,60 @@
+package images // import "github.com/docker/docker/daemon/images"
+
+import (
+	"encoding/json"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/docker/distribution/reference"
+	"github.com/docker/docker/errdefs"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestGetImageForPlatform(t *testing.T) {
+	i, cleanup := newTestImageService(t)
+	defer cleanup()
+
+	foreignArch := "arm64"
+	if runtime.GOARCH == foreignArch {
+		foreignArch = "amd64"
+	}
+	native := specs.Platform{OS: runtime.GOOS, Architecture: runtime.GOARCH}
+	foreign := specs.Platform{OS: runtime.GOOS, Architecture: foreignArch}
+
+	nativeID := createTestImage(t, i, "example.com/multi", time.Now(), nil)
+	config, err := json.Marshal(map[string]interface{}{
+		"architecture": foreignArch,
+		"os":           runtime.GOOS,
+		"rootfs":       map[string]string{"type": "layers"},
+	})
+	assert.NilError(t, err)
+	foreignID, err := i.imageStore.Create(config)
+	assert.NilError(t, err)
+	ref, err := reference.ParseNormalizedNamed("example.com/multi")
+	assert.NilError(t, err)
+	assert.NilError(t, i.referenceStore.AddPlatformReference(ref, foreign, foreignID.Digest()))
+
+	img, err := i.GetImageForPlatform("example.com/multi", nil)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(img.ID(), nativeID))
+	img, err = i.GetImageForPlatform("example.com/multi", &native)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(img.ID(), nativeID))
+	img, err = i.GetImageForPlatform("example.com/multi", &foreign)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(img.ID(), foreignID))
+	_, err = i.GetImageForPlatform("example.com/multi", &specs.Platform{OS: runtime.GOOS, Architecture: "s390x"})
+	assert.Check(t, errdefs.IsNotFound(err))
+
+	// deleting the foreign image keeps the reference to the native one
+	_, err = i.ImageDelete(foreignID.String(), false, false)
+	assert.NilError(t, err)
+	img, err = i.GetImage("example.com/multi")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(img.ID(), nativeID))
+	_, err = i.GetImageForPlatform("example.com/multi", &foreign)
+	assert.Check(t, errdefs.IsNotFound(err))
+}
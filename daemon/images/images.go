@@ -11,7 +11,6 @@ import (
 	"github.com/docker/distribution/reference"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
-	"github.com/docker/docker/container"
 	"github.com/docker/docker/image"
 	"github.com/docker/docker/layer"
 	"github.com/docker/docker/pkg/system"
@@ -88,7 +87,7 @@ func (i *ImageService) Images(imageFilters filters.Args, all bool, withExtraAttr
 	var imagesMap map[*image.Image]*types.ImageSummary
 	var layerRefs map[layer.ChainID]int
 	var allLayers map[layer.ChainID]layer.Layer
-	var allContainers []*container.Container
+	var containerCounts map[image.ID]int64
 
 	for id, img := range allImages {
 		if beforeFilter != nil {
@@ -142,6 +141,9 @@ func (i *ImageService) Images(imageFilters filters.Args, all bool, withExtraAttr
 		}
 
 		newImage := newImage(img, size)
+		if lastUsed, err := i.imageStore.GetLastUsed(id); err == nil && !lastUsed.IsZero() {
+			newImage.LastUsed = lastUsed.Unix()
+		}
 
 		for _, ref := range i.referenceStore.References(id.Digest()) {
 			if imageFilters.Contains("reference") {
@@ -186,11 +188,18 @@ func (i *ImageService) Images(imageFilters filters.Args, all bool, withExtraAttr
 			continue
 		}
 
+		// Get container count
+		if containerCounts == nil {
+			containerCounts = make(map[image.ID]int64)
+			for _, c := range i.containers.List() {
+				containerCounts[c.ImageID]++
+			}
+		}
+		newImage.Containers = containerCounts[id]
+
 		if withExtraAttrs {
 			// lazily init variables
 			if imagesMap == nil {
-				allContainers = i.containers.List()
-
 				// allLayers is built from all layerstores combined
 				allLayers = make(map[layer.ChainID]layer.Layer)
 				for _, ls := range i.layerStores {
@@ -203,14 +212,6 @@ func (i *ImageService) Images(imageFilters filters.Args, all bool, withExtraAttr
 				layerRefs = make(map[layer.ChainID]int)
 			}
 
-			// Get container count
-			newImage.Containers = 0
-			for _, c := range allContainers {
-				if c.ImageID == id {
-					newImage.Containers++
-				}
-			}
-
 			// count layer references
 			rootFS := *img.RootFS
 			rootFS.DiffIDs = nil
@@ -351,7 +352,6 @@ func newImage(image *image.Image, size int64) *types.ImageSummary {
 	newImage.Size = size
 	newImage.VirtualSize = size
 	newImage.SharedSize = -1
-	newImage.Containers = -1
 	if image.Config != nil {
 		newImage.Labels = image.Config.Labels
 	}
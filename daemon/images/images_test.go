@@ -0,0 +1,64 @@
+package images // import "github.com/docker/docker/daemon/images"
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/container"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestImagesLastUsedAndContainers(t *testing.T) {
+	i, cleanup := newTestImageService(t)
+	defer cleanup()
+
+	used := createTestImage(t, i, "example.com/used", time.Now(), nil)
+	unused := createTestImage(t, i, "example.com/unused", time.Now(), nil)
+	assert.NilError(t, i.SetImageLastUsed(used))
+	i.containers.(container.Store).Add("c1", &container.Container{ID: "c1", ImageID: used})
+	i.containers.(container.Store).Add("c2", &container.Container{ID: "c2", ImageID: used})
+
+	images, err := i.Images(filters.NewArgs(), false, false)
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(images, 2))
+	for _, img := range images {
+		switch img.ID {
+		case used.String():
+			assert.Check(t, is.Equal(img.Containers, int64(2)))
+			assert.Check(t, img.LastUsed != 0)
+		case unused.String():
+			assert.Check(t, is.Equal(img.Containers, int64(0)))
+			assert.Check(t, is.Equal(img.LastUsed, int64(0)))
+		}
+	}
+
+}
+
+func TestImagesPruneUnusedSince(t *testing.T) {
+	i, cleanup := newTestImageService(t)
+	defer cleanup()
+
+	old := time.Now().Add(-48 * time.Hour)
+	unused := createTestImage(t, i, "example.com/unused", old, nil)
+	recentlyUsed := createTestImage(t, i, "example.com/recently-used", old, nil)
+	assert.NilError(t, i.SetImageLastUsed(recentlyUsed))
+
+	report, err := i.ImagesPrune(context.Background(), filters.NewArgs(
+		filters.Arg("dangling", "false"),
+		filters.Arg("unused-since", "24h"),
+	))
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(report.ImagesDeleted, []types.ImageDeleteResponseItem{
+		{Untagged: "example.com/unused:latest"},
+		{Deleted: unused.String()},
+	}))
+	_, err = i.imageStore.Get(recentlyUsed)
+	assert.Check(t, err)
+
+	_, err = i.ImagesPrune(context.Background(), filters.NewArgs(filters.Arg("unused-since", "yesterday")))
+	assert.Check(t, is.ErrorContains(err, ""))
+}
@@ -3,6 +3,7 @@ package images // import "github.com/docker/docker/daemon/images"
 import (
 	"fmt"
 
+	"github.com/docker/docker/api/types"
 	"github.com/docker/go-metrics"
 )
 
@@ -21,12 +22,28 @@ func (e invalidFilter) Error() string {
 
 func (e invalidFilter) InvalidParameter() {}
 
-var imageActions metrics.LabeledTimer
+var (
+	imageActions    metrics.LabeledTimer
+	pushLayers      metrics.LabeledCounter
+	pushLayersBytes metrics.LabeledCounter
+)
 
 func init() {
 	ns := metrics.NewNamespace("engine", "daemon", nil)
 	imageActions = ns.NewLabeledTimer("image_actions", "The number of seconds it takes to process each image action", "action")
+	pushLayers = ns.NewLabeledCounter("image_push_layers", "The number of layers of pushed images by result: uploaded, mounted from another repository or skipped as already existing", "result")
+	pushLayersBytes = ns.NewLabeledCounter("image_push_layers_bytes", "The size in bytes of the layers of pushed images by result: uploaded, mounted from another repository or skipped as already existing", "result")
 	// TODO: is it OK to register a namespace with the same name? Or does this
 	// need to be exported from somewhere?
 	metrics.Register(ns)
 }
+
+// recordPushStats adds stats to the push metrics.
+func recordPushStats(stats types.PushStats) {
+	pushLayers.WithValues("uploaded").Inc(float64(stats.UploadedLayers))
+	pushLayers.WithValues("mounted").Inc(float64(stats.MountedLayers))
+	pushLayers.WithValues("skipped").Inc(float64(stats.SkippedLayers))
+	pushLayersBytes.WithValues("uploaded").Inc(float64(stats.UploadedBytes))
+	pushLayersBytes.WithValues("mounted").Inc(float64(stats.MountedBytes))
+	pushLayersBytes.WithValues("skipped").Inc(float64(stats.SkippedBytes))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"runtime"
+	"sync"
 
 	"github.com/docker/docker/container"
 	daemonevents "github.com/docker/docker/daemon/events"
@@ -11,6 +12,7 @@ import (
 	"github.com/docker/docker/distribution/metadata"
 	"github.com/docker/docker/distribution/xfer"
 	"github.com/docker/docker/image"
+	"github.com/docker/docker/image/signature"
 	"github.com/docker/docker/layer"
 	dockerreference "github.com/docker/docker/reference"
 	"github.com/docker/docker/registry"
@@ -38,8 +40,11 @@ type ImageServiceConfig struct {
 	LayerStores               map[string]layer.Store
 	MaxConcurrentDownloads    int
 	MaxConcurrentUploads      int
+	DownloadRetryPolicy       xfer.RetryPolicy
 	ReferenceStore            dockerreference.Store
 	RegistryService           registry.Service
+	SignaturePolicy           *signature.Policy
+	SignatureStore            *signature.Store
 	TrustKey                  libtrust.PrivateKey
 }
 
@@ -47,15 +52,20 @@ type ImageServiceConfig struct {
 func NewImageService(config ImageServiceConfig) *ImageService {
 	logrus.Debugf("Max Concurrent Downloads: %d", config.MaxConcurrentDownloads)
 	logrus.Debugf("Max Concurrent Uploads: %d", config.MaxConcurrentUploads)
+	logrus.Debugf("Download Retry Policy: %+v", config.DownloadRetryPolicy)
+	downloadManager := xfer.NewLayerDownloadManager(config.LayerStores, config.MaxConcurrentDownloads)
+	downloadManager.SetRetryPolicy(config.DownloadRetryPolicy)
 	return &ImageService{
 		containers:                config.ContainerStore,
 		distributionMetadataStore: config.DistributionMetadataStore,
-		downloadManager:           xfer.NewLayerDownloadManager(config.LayerStores, config.MaxConcurrentDownloads),
+		downloadManager:           downloadManager,
 		eventsService:             config.EventsService,
 		imageStore:                config.ImageStore,
 		layerStores:               config.LayerStores,
 		referenceStore:            config.ReferenceStore,
 		registryService:           config.RegistryService,
+		signaturePolicy:           config.SignaturePolicy,
+		signatureStore:            config.SignatureStore,
 		trustKey:                  config.TrustKey,
 		uploadManager:             xfer.NewLayerUploadManager(config.MaxConcurrentUploads),
 	}
@@ -67,11 +77,15 @@ type ImageService struct {
 	distributionMetadataStore metadata.Store
 	downloadManager           *xfer.LayerDownloadManager
 	eventsService             *daemonevents.Events
+	gc                        *imageGC
+	gcMu                      sync.Mutex
 	imageStore                image.Store
 	layerStores               map[string]layer.Store // By operating system
 	pruneRunning              int32
 	referenceStore            dockerreference.Store
 	registryService           registry.Service
+	signaturePolicy           *signature.Policy
+	signatureStore            *signature.Store
 	trustKey                  libtrust.PrivateKey
 	uploadManager             *xfer.LayerUploadManager
 }
@@ -247,3 +261,10 @@ func (i *ImageService) UpdateConfig(maxDownloads, maxUploads *int) {
 		i.uploadManager.SetConcurrency(*maxUploads)
 	}
 }
+
+// UpdateDownloadRetryPolicy updates the retry policy of layer downloads.
+func (i *ImageService) UpdateDownloadRetryPolicy(policy xfer.RetryPolicy) {
+	if i.downloadManager != nil {
+		i.downloadManager.SetRetryPolicy(policy)
+	}
+}
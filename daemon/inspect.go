@@ -179,6 +179,15 @@ func (daemon *Daemon) getInspectData(container *container.Container) (*types.Con
 		HostConfig:   &hostConfig,
 	}
 
+	if !container.HostConfig.RestartPolicy.IsNone() {
+		state := container.RestartManager().State()
+		contJSONBase.RestartBackoff = &types.RestartBackoff{
+			Delay:          state.Delay,
+			RecentRestarts: state.RecentRestarts,
+			GaveUp:         state.GaveUp,
+		}
+	}
+
 	// Now set any platform-specific fields
 	contJSONBase = setPlatformSpecificContainerFields(container, contJSONBase)
 
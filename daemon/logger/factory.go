@@ -25,7 +25,8 @@ type logdriverFactory struct {
 }
 
 func (lf *logdriverFactory) list() []string {
-	ls := make([]string, 0, len(lf.registry))
+	ls := make([]string, 0, len(lf.registry)+1)
+	ls = append(ls, FanoutDriverName)
 	lf.m.Lock()
 	for name := range lf.registry {
 		ls = append(ls, name)
@@ -117,8 +118,16 @@ func GetLogDriver(name string) (Creator, error) {
 }
 
 var builtInLogOpts = map[string]bool{
-	"mode":            true,
-	"max-buffer-size": true,
+	"mode":               true,
+	"max-buffer-size":    true,
+	LogFormatOpt:         true,
+	LogFormatFieldsOpt:   true,
+	LogFormatLevelKeyOpt: true,
+	LogFormatTimeKeyOpt:  true,
+	RateLimitLinesOpt:    true,
+	RateLimitBytesOpt:    true,
+	RateLimitBurstOpt:    true,
+	RateLimitSampleOpt:   true,
 }
 
 // ValidateLogOpts checks the options for the given log driver. The
@@ -128,6 +137,19 @@ func ValidateLogOpts(name string, cfg map[string]string) error {
 		return nil
 	}
 
+	if name == FanoutDriverName {
+		configs, err := ParseFanoutConfig(cfg)
+		if err != nil {
+			return err
+		}
+		for _, c := range configs {
+			if err := ValidateLogOpts(c.Type, c.Config); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
 	switch containertypes.LogMode(cfg["mode"]) {
 	case containertypes.LogModeBlocking, containertypes.LogModeNonBlock, containertypes.LogModeUnset:
 	default:
@@ -143,6 +165,14 @@ func ValidateLogOpts(name string, cfg map[string]string) error {
 		}
 	}
 
+	if _, err := parseStructuredConfig(cfg); err != nil {
+		return err
+	}
+
+	if _, err := parseRateLimitConfig(cfg); err != nil {
+		return err
+	}
+
 	if !factory.driverRegistered(name) {
 		return fmt.Errorf("logger: no log driver named '%s' is registered", name)
 	}
@@ -0,0 +1,141 @@
+package logger // import "github.com/docker/docker/daemon/logger"
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/api/types/backend"
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// FanoutDriverName is the name of the logging driver which sends each
+// message to several other logging drivers.
+//
+// The drivers are listed, in order, in the "drivers" option. Options of
+// each driver are prefixed with the driver's name and a dot, for example
+// "gelf.gelf-address=udp://localhost:12201" or "local.mode=non-blocking".
+const FanoutDriverName = "fanout"
+
+const fanoutDriversOpt = "drivers"
+
+// ParseFanoutConfig returns the log configs of the drivers a "fanout" log
+// config sends messages to, in order.
+func ParseFanoutConfig(cfg map[string]string) ([]containertypes.LogConfig, error) {
+	if cfg[fanoutDriversOpt] == "" {
+		return nil, errors.Errorf("logger: the %s log driver requires the '%s' option", FanoutDriverName, fanoutDriversOpt)
+	}
+
+	var configs []containertypes.LogConfig
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(cfg[fanoutDriversOpt], ",") {
+		name = strings.TrimSpace(name)
+		switch {
+		case name == "":
+			return nil, errors.Errorf("logger: empty driver name in '%s' option", fanoutDriversOpt)
+		case name == FanoutDriverName || name == "none":
+			return nil, errors.Errorf("logger: the %s log driver cannot send messages to the %s log driver", FanoutDriverName, name)
+		case seen[name]:
+			return nil, errors.Errorf("logger: duplicate driver '%s' in '%s' option", name, fanoutDriversOpt)
+		}
+		seen[name] = true
+		configs = append(configs, containertypes.LogConfig{Type: name, Config: make(map[string]string)})
+	}
+
+	for k, v := range cfg {
+		if k == fanoutDriversOpt {
+			continue
+		}
+		// driver names may contain dots, so use the longest matching prefix
+		match := -1
+		for i, c := range configs {
+			if strings.HasPrefix(k, c.Type+".") && (match < 0 || len(c.Type) > len(configs[match].Type)) {
+				match = i
+			}
+		}
+		if match < 0 {
+			return nil, errors.Errorf("logger: option '%s' of the %s log driver does not match any driver in '%s'", k, FanoutDriverName, fanoutDriversOpt)
+		}
+		configs[match].Config[strings.TrimPrefix(k, configs[match].Type+".")] = v
+	}
+	return configs, nil
+}
+
+// fanout is a Logger which sends each message to several loggers. A failing
+// logger does not prevent messages from being sent to the others.
+type fanout struct {
+	loggers []Logger
+}
+
+type fanoutWithReader struct {
+	*fanout
+	reader LogReader
+}
+
+func (f *fanoutWithReader) ReadLogs(cfg ReadConfig) *LogWatcher {
+	return f.reader.ReadLogs(cfg)
+}
+
+// NewFanout returns a Logger which sends each message to all the loggers.
+// Logs are read from the first logger which implements LogReader, if any.
+//
+// Loggers are used as they are configured: a logger in blocking mode holds
+// back the others while it blocks, a logger in non-blocking mode is expected
+// to be wrapped in a RingLogger already.
+func NewFanout(loggers []Logger) Logger {
+	f := &fanout{loggers: loggers}
+	for _, l := range loggers {
+		if r, ok := l.(LogReader); ok {
+			return &fanoutWithReader{fanout: f, reader: r}
+		}
+	}
+	return f
+}
+
+// Log sends a copy of the message to each logger, as loggers may reuse the
+// message once they are done with it.
+func (f *fanout) Log(msg *Message) error {
+	var failed []string
+	for _, l := range f.loggers {
+		m := NewMessage()
+		m.Line = append(m.Line, msg.Line...)
+		m.Source = msg.Source
+		m.Timestamp = msg.Timestamp
+		if msg.Attrs != nil {
+			m.Attrs = append(make([]backend.LogAttr, 0, len(msg.Attrs)), msg.Attrs...)
+		}
+		if msg.PLogMetaData != nil {
+			plog := *msg.PLogMetaData
+			m.PLogMetaData = &plog
+		}
+		m.Err = msg.Err
+		if err := l.Log(m); err != nil {
+			logrus.WithError(err).WithField("driver", l.Name()).Debug("fanout: failed to log message")
+			failed = append(failed, l.Name())
+		}
+	}
+	PutMessage(msg)
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to log message to %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+func (f *fanout) Name() string {
+	return FanoutDriverName
+}
+
+func (f *fanout) Close() error {
+	var errs []string
+	for _, l := range f.loggers {
+		if err := l.Close(); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", l.Name(), err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("error closing loggers: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package logger // import "github.com/docker/docker/daemon/logger"
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/docker/docker/api/types/backend"
+
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+type recordingLogger struct {
+	name  string
+	err   error
+	block chan struct{}
+
+	mu    sync.Mutex
+	lines []string
+	attrs [][]backend.LogAttr
+}
+
+func (l *recordingLogger) Log(m *Message) error {
+	if l.block != nil {
+		<-l.block
+	}
+	if l.err != nil {
+		return l.err
+	}
+	l.mu.Lock()
+	l.lines = append(l.lines, string(m.Line))
+	l.attrs = append(l.attrs, m.Attrs)
+	l.mu.Unlock()
+	PutMessage(m)
+	return nil
+}
+
+func (l *recordingLogger) Name() string { return l.name }
+func (l *recordingLogger) Close() error { return nil }
+
+type readingLogger struct {
+	recordingLogger
+	watcher *LogWatcher
+}
+
+func (l *readingLogger) ReadLogs(ReadConfig) *LogWatcher { return l.watcher }
+
+func TestParseFanoutConfig(t *testing.T) {
+	configs, err := ParseFanoutConfig(map[string]string{
+		"drivers":                        "local, gelf,example.com/plugin",
+		"local.max-size":                 "10m",
+		"gelf.gelf-address":              "udp://localhost:12201",
+		"gelf.mode":                      "non-blocking",
+		"example.com/plugin.some-option": "value",
+	})
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(configs, 3))
+	assert.Check(t, is.Equal(configs[0].Type, "local"))
+	assert.Check(t, is.DeepEqual(configs[0].Config, map[string]string{"max-size": "10m"}))
+	assert.Check(t, is.Equal(configs[1].Type, "gelf"))
+	assert.Check(t, is.DeepEqual(configs[1].Config, map[string]string{"gelf-address": "udp://localhost:12201", "mode": "non-blocking"}))
+	assert.Check(t, is.Equal(configs[2].Type, "example.com/plugin"))
+	assert.Check(t, is.DeepEqual(configs[2].Config, map[string]string{"some-option": "value"}))
+
+	for _, cfg := range []map[string]string{
+		{},
+		{"drivers": "local,,gelf"},
+		{"drivers": "local,local"},
+		{"drivers": "local,fanout"},
+		{"drivers": "local", "max-size": "10m"},
+		{"drivers": "local", "gelf.gelf-address": "udp://localhost:12201"},
+	} {
+		_, err := ParseFanoutConfig(cfg)
+		assert.Check(t, err != nil, "expected error for %v", cfg)
+	}
+}
+
+func TestFanoutLog(t *testing.T) {
+	failing := &recordingLogger{name: "failing", err: errors.New("failed")}
+	first := &recordingLogger{name: "first"}
+	second := &readingLogger{recordingLogger: recordingLogger{name: "second"}, watcher: NewLogWatcher()}
+	nonBlocking := &recordingLogger{name: "non-blocking", block: make(chan struct{})}
+	ring := NewRingLogger(nonBlocking, Info{}, -1)
+
+	l := NewFanout([]Logger{failing, first, second, ring})
+	reader, ok := l.(LogReader)
+	assert.Assert(t, ok, "expected fanout to implement LogReader")
+	assert.Check(t, reader.ReadLogs(ReadConfig{}) == second.watcher)
+
+	// a failing logger, or a blocked logger in non-blocking mode, does not
+	// prevent messages from being logged
+	msg := NewMessage()
+	msg.Line = append(msg.Line, "hello"...)
+	msg.Attrs = []backend.LogAttr{{Key: "level", Value: "info"}}
+	assert.Check(t, is.ErrorContains(l.Log(msg), "failed to log message to failing"))
+	close(nonBlocking.block)
+	assert.Check(t, l.Close())
+
+	for _, r := range []*recordingLogger{first, &second.recordingLogger, nonBlocking} {
+		assert.Check(t, is.DeepEqual(r.lines, []string{"hello"}), r.name)
+		assert.Check(t, is.DeepEqual(r.attrs, [][]backend.LogAttr{{{Key: "level", Value: "info"}}}), r.name)
+	}
+	// each logger gets its own copy of the attributes
+	first.attrs[0][0].Value = "changed"
+	assert.Check(t, is.Equal(nonBlocking.attrs[0][0].Value, "info"))
+
+	_, ok = NewFanout([]Logger{first}).(LogReader)
+	assert.Check(t, !ok, "expected fanout not to implement LogReader")
+}
@@ -0,0 +1,105 @@
+package logger // import "github.com/docker/docker/daemon/logger"
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/docker/docker/api/types/filters"
+	"github.com/pkg/errors"
+)
+
+// LogFilter selects the messages returned by a LogReader. The zero value, as
+// well as a nil *LogFilter, matches all messages.
+type LogFilter struct {
+	// Pattern, if set, must match the line of a message.
+	Pattern *regexp.Regexp
+	// Sources, if not empty, lists the streams ("stdout", "stderr") to
+	// return messages from.
+	Sources []string
+	// Attrs lists attributes a message must have. An empty value matches
+	// any value of the attribute.
+	Attrs map[string]string
+}
+
+// NewLogFilter creates a LogFilter from the options of a logs request. grep
+// is a regular expression, and the "attr" filters have a "key" or
+// "key=value" form.
+func NewLogFilter(grep string, stdout, stderr bool, args filters.Args) (*LogFilter, error) {
+	if err := args.Validate(map[string]bool{"attr": true}); err != nil {
+		return nil, err
+	}
+
+	f := &LogFilter{}
+	if grep != "" {
+		re, err := regexp.Compile(grep)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid grep pattern")
+		}
+		f.Pattern = re
+	}
+	// if both streams are selected, messages without a known source (which
+	// some drivers may return) are kept as well.
+	if stdout != stderr {
+		if stdout {
+			f.Sources = []string{"stdout"}
+		} else {
+			f.Sources = []string{"stderr"}
+		}
+	}
+	for _, attr := range args.Get("attr") {
+		if f.Attrs == nil {
+			f.Attrs = make(map[string]string)
+		}
+		kv := strings.SplitN(attr, "=", 2)
+		if kv[0] == "" {
+			return nil, errors.Errorf("invalid attr filter '%s'", attr)
+		}
+		if len(kv) == 2 {
+			f.Attrs[kv[0]] = kv[1]
+		} else {
+			f.Attrs[kv[0]] = ""
+		}
+	}
+
+	if f.Pattern == nil && len(f.Sources) == 0 && len(f.Attrs) == 0 {
+		return nil, nil
+	}
+	return f, nil
+}
+
+// Match returns true if the message is selected by the filter.
+func (f *LogFilter) Match(m *Message) bool {
+	if f == nil {
+		return true
+	}
+	if len(f.Sources) > 0 && !matchSource(f.Sources, m.Source) {
+		return false
+	}
+	for k, v := range f.Attrs {
+		if !matchAttr(m, k, v) {
+			return false
+		}
+	}
+	if f.Pattern != nil && !f.Pattern.Match(m.Line) {
+		return false
+	}
+	return true
+}
+
+func matchSource(sources []string, source string) bool {
+	for _, s := range sources {
+		if s == source {
+			return true
+		}
+	}
+	return false
+}
+
+func matchAttr(m *Message, key, value string) bool {
+	for _, attr := range m.Attrs {
+		if attr.Key == key && (value == "" || attr.Value == value) {
+			return true
+		}
+	}
+	return false
+}
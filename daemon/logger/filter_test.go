@@ -0,0 +1,44 @@
+package logger // import "github.com/docker/docker/daemon/logger"
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/backend"
+	"github.com/docker/docker/api/types/filters"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestLogFilter(t *testing.T) {
+	f, err := NewLogFilter("", true, true, filters.NewArgs())
+	assert.NilError(t, err)
+	assert.Check(t, f == nil)
+	assert.Check(t, f.Match(&Message{Line: []byte("anything")}))
+
+	_, err = NewLogFilter("(", true, true, filters.NewArgs())
+	assert.Check(t, is.ErrorContains(err, "invalid grep pattern"))
+	_, err = NewLogFilter("", true, true, filters.NewArgs(filters.Arg("label", "foo")))
+	assert.Check(t, err != nil)
+	_, err = NewLogFilter("", true, true, filters.NewArgs(filters.Arg("attr", "=foo")))
+	assert.Check(t, err != nil)
+
+	f, err = NewLogFilter("err(or)?", false, true, filters.NewArgs(
+		filters.Arg("attr", "tier=frontend"),
+		filters.Arg("attr", "env"),
+	))
+	assert.NilError(t, err)
+
+	attrs := []backend.LogAttr{{Key: "tier", Value: "frontend"}, {Key: "env", Value: "prod"}}
+	for _, tc := range []struct {
+		msg     *Message
+		matches bool
+	}{
+		{msg: &Message{Line: []byte("an error occurred"), Source: "stderr", Attrs: attrs}, matches: true},
+		{msg: &Message{Line: []byte("an error occurred"), Source: "stdout", Attrs: attrs}},
+		{msg: &Message{Line: []byte("all is well"), Source: "stderr", Attrs: attrs}},
+		{msg: &Message{Line: []byte("an error occurred"), Source: "stderr", Attrs: attrs[1:]}},
+		{msg: &Message{Line: []byte("an error occurred"), Source: "stderr", Attrs: []backend.LogAttr{{Key: "tier", Value: "backend"}, {Key: "env", Value: "prod"}}}},
+	} {
+		assert.Check(t, is.Equal(f.Match(tc.msg), tc.matches), "%s %s %v", tc.msg.Line, tc.msg.Source, tc.msg.Attrs)
+	}
+}
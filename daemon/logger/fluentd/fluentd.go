@@ -163,6 +163,13 @@ func (f *fluentd) Log(msg *logger.Message) error {
 	for k, v := range f.extra {
 		data[k] = v
 	}
+	// attributes of the message, such as the fields of structured log
+	// lines, do not replace the fields set above.
+	for _, attr := range msg.Attrs {
+		if _, ok := data[attr.Key]; !ok {
+			data[attr.Key] = attr.Value
+		}
+	}
 	if msg.PLogMetaData != nil {
 		data["partial_message"] = "true"
 	}
@@ -9,6 +9,7 @@ import (
 	"net"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Graylog2/go-gelf/gelf"
@@ -24,6 +25,7 @@ type gelfLogger struct {
 	writer   gelf.Writer
 	info     logger.Info
 	hostname string
+	extra    map[string]interface{}
 	rawExtra json.RawMessage
 }
 
@@ -104,6 +106,7 @@ func New(info logger.Info) (logger.Logger, error) {
 		writer:   gelfWriter,
 		info:     info,
 		hostname: hostname,
+		extra:    extra,
 		rawExtra: rawExtra,
 	}, nil
 }
@@ -171,13 +174,39 @@ func (s *gelfLogger) Log(msg *logger.Message) error {
 		level = gelf.LOG_ERR
 	}
 
+	rawExtra := s.rawExtra
+	if len(msg.Attrs) > 0 {
+		// attributes of the message, such as the fields of structured log
+		// lines, are sent as additional fields.
+		extra := make(map[string]interface{}, len(s.extra)+len(msg.Attrs))
+		for k, v := range s.extra {
+			extra[k] = v
+		}
+		for _, attr := range msg.Attrs {
+			if attr.Key == logger.LevelAttr {
+				if l, ok := parseLevel(attr.Value); ok {
+					level = l
+					continue
+				}
+			}
+			if key := extraFieldName(attr.Key); key != "" {
+				extra[key] = attr.Value
+			}
+		}
+		var err error
+		if rawExtra, err = json.Marshal(extra); err != nil {
+			logger.PutMessage(msg)
+			return fmt.Errorf("gelf: cannot marshal message attributes: %v", err)
+		}
+	}
+
 	m := gelf.Message{
 		Version:  "1.1",
 		Host:     s.hostname,
 		Short:    string(msg.Line),
 		TimeUnix: float64(msg.Timestamp.UnixNano()/int64(time.Millisecond)) / 1000.0,
 		Level:    int32(level),
-		RawExtra: s.rawExtra,
+		RawExtra: rawExtra,
 	}
 	logger.PutMessage(msg)
 
@@ -187,6 +216,45 @@ func (s *gelfLogger) Log(msg *logger.Message) error {
 	return nil
 }
 
+// parseLevel returns the syslog level of a level name.
+func parseLevel(name string) (int, bool) {
+	switch strings.ToLower(name) {
+	case "emerg", "emergency", "panic":
+		return gelf.LOG_EMERG, true
+	case "alert":
+		return gelf.LOG_ALERT, true
+	case "crit", "critical", "fatal":
+		return gelf.LOG_CRIT, true
+	case "err", "error":
+		return gelf.LOG_ERR, true
+	case "warn", "warning":
+		return gelf.LOG_WARNING, true
+	case "notice":
+		return gelf.LOG_NOTICE, true
+	case "info":
+		return gelf.LOG_INFO, true
+	case "debug", "trace":
+		return gelf.LOG_DEBUG, true
+	}
+	return 0, false
+}
+
+// extraFieldName returns the name of the additional field of an attribute.
+// GELF only allows word characters, dots and dashes in field names, and
+// reserves the "_id" field.
+func extraFieldName(key string) string {
+	name := "_" + strings.Map(func(r rune) rune {
+		if r == '.' || r == '-' || r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, strings.TrimPrefix(key, "_"))
+	if name == "_" || name == "_id" {
+		return ""
+	}
+	return name
+}
+
 func (s *gelfLogger) Close() error {
 	return s.writer.Close()
 }
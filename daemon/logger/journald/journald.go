@@ -17,6 +17,12 @@ import (
 
 const name = "journald"
 
+// attrPrefix is prepended to the keys of the attributes of messages, which are
+// parsed from the output of the container, so that they cannot set the
+// trusted fields of the journal or the variables read back by the driver,
+// such as CONTAINER_PARTIAL_MESSAGE.
+const attrPrefix = "ATTR_"
+
 type journald struct {
 	mu      sync.Mutex
 	vars    map[string]string // additional variables and values to send to the journal along with the log message
@@ -53,6 +59,15 @@ func sanitizeKeyMod(s string) string {
 	return n
 }
 
+// attrKey returns the journal field of the given message attribute, or an
+// empty string if the attribute cannot be sent to the journal.
+func attrKey(k string) string {
+	if k = sanitizeKeyMod(k); k == "" {
+		return ""
+	}
+	return attrPrefix + k
+}
+
 // New creates a journald logger using the configuration passed in on
 // the context.
 func New(info logger.Info) (logger.Logger, error) {
@@ -108,6 +123,14 @@ func (s *journald) Log(msg *logger.Message) error {
 	if msg.PLogMetaData != nil && !msg.PLogMetaData.Last {
 		vars["CONTAINER_PARTIAL_MESSAGE"] = "true"
 	}
+	// attributes of the message, such as the fields of structured log
+	// lines, are namespaced so that they do not replace the variables set
+	// above.
+	for _, attr := range msg.Attrs {
+		if key := attrKey(attr.Key); key != "" {
+			vars[key] = attr.Value
+		}
+	}
 
 	line := string(msg.Line)
 	source := msg.Source
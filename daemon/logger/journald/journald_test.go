@@ -21,3 +21,18 @@ func TestSanitizeKeyMod(t *testing.T) {
 		}
 	}
 }
+
+func TestAttrKey(t *testing.T) {
+	entries := map[string]string{
+		"level":                     "ATTR_LEVEL",
+		"MESSAGE":                   "ATTR_MESSAGE",
+		"container_partial_message": "ATTR_CONTAINER_PARTIAL_MESSAGE",
+		"_SYSTEMD_UNIT":             "ATTR_SYSTEMD_UNIT",
+		"???":                       "",
+	}
+	for k, v := range entries {
+		if attrKey(k) != v {
+			t.Fatalf("Failed to get the key of attribute %s, got %s, expected %s", k, attrKey(k), v)
+		}
+	}
+}
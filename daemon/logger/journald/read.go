@@ -173,11 +173,59 @@ func (s *journald) Close() error {
 	return nil
 }
 
-func (s *journald) drainJournal(logWatcher *logger.LogWatcher, j *C.sd_journal, oldCursor *C.char, untilUnixMicro uint64) (*C.char, bool) {
-	var msg, data, cursor *C.char
+// readEntry reads the message of the current journal entry, and its
+// timestamp in microseconds. It returns a nil message if the entry has no
+// message.
+func readEntry(j *C.sd_journal) (*logger.Message, uint64, error) {
+	var msg, data *C.char
 	var length C.size_t
 	var stamp C.uint64_t
 	var priority, partial C.int
+
+	i := C.get_message(j, &msg, &length, &partial)
+	if i == -C.ENOENT || i == -C.EADDRNOTAVAIL {
+		return nil, 0, nil
+	}
+	// Read the entry's timestamp.
+	if C.sd_journal_get_realtime_usec(j, &stamp) != 0 {
+		return nil, 0, fmt.Errorf("error reading journal entry timestamp")
+	}
+
+	// Set up the time and text of the entry.
+	timestamp := time.Unix(int64(stamp)/1000000, (int64(stamp)%1000000)*1000)
+	line := C.GoBytes(unsafe.Pointer(msg), C.int(length))
+	if partial == 0 {
+		line = append(line, "\n"...)
+	}
+	// Recover the stream name by mapping
+	// from the journal priority back to
+	// the stream that we would have
+	// assigned that value.
+	source := ""
+	if C.get_priority(j, &priority) != 0 {
+		source = ""
+	} else if priority == C.int(journal.PriErr) {
+		source = "stderr"
+	} else if priority == C.int(journal.PriInfo) {
+		source = "stdout"
+	}
+	// Retrieve the values of any variables we're adding to the journal.
+	var attrs []backend.LogAttr
+	C.sd_journal_restart_data(j)
+	for C.get_attribute_field(j, &data, &length) > C.int(0) {
+		kv := strings.SplitN(C.GoStringN(data, C.int(length)), "=", 2)
+		attrs = append(attrs, backend.LogAttr{Key: kv[0], Value: kv[1]})
+	}
+	return &logger.Message{
+		Line:      line,
+		Source:    source,
+		Timestamp: timestamp.In(time.UTC),
+		Attrs:     attrs,
+	}, uint64(stamp), nil
+}
+
+func (s *journald) drainJournal(logWatcher *logger.LogWatcher, j *C.sd_journal, oldCursor *C.char, untilUnixMicro uint64, filter *logger.LogFilter) (*C.char, bool) {
+	var cursor *C.char
 	var done bool
 
 	// Walk the journal from here forward until we run out of new entries
@@ -193,49 +241,19 @@ drain:
 			}
 		}
 		// Read and send the logged message, if there is one to read.
-		i := C.get_message(j, &msg, &length, &partial)
-		if i != -C.ENOENT && i != -C.EADDRNOTAVAIL {
-			// Read the entry's timestamp.
-			if C.sd_journal_get_realtime_usec(j, &stamp) != 0 {
-				break
-			}
+		m, stamp, err := readEntry(j)
+		if err != nil {
+			break
+		}
+		if m != nil {
 			// Break if the timestamp exceeds any provided until flag.
-			if untilUnixMicro != 0 && untilUnixMicro < uint64(stamp) {
+			if untilUnixMicro != 0 && untilUnixMicro < stamp {
 				done = true
 				break
 			}
-
-			// Set up the time and text of the entry.
-			timestamp := time.Unix(int64(stamp)/1000000, (int64(stamp)%1000000)*1000)
-			line := C.GoBytes(unsafe.Pointer(msg), C.int(length))
-			if partial == 0 {
-				line = append(line, "\n"...)
-			}
-			// Recover the stream name by mapping
-			// from the journal priority back to
-			// the stream that we would have
-			// assigned that value.
-			source := ""
-			if C.get_priority(j, &priority) != 0 {
-				source = ""
-			} else if priority == C.int(journal.PriErr) {
-				source = "stderr"
-			} else if priority == C.int(journal.PriInfo) {
-				source = "stdout"
-			}
-			// Retrieve the values of any variables we're adding to the journal.
-			var attrs []backend.LogAttr
-			C.sd_journal_restart_data(j)
-			for C.get_attribute_field(j, &data, &length) > C.int(0) {
-				kv := strings.SplitN(C.GoStringN(data, C.int(length)), "=", 2)
-				attrs = append(attrs, backend.LogAttr{Key: kv[0], Value: kv[1]})
-			}
-			// Send the log message.
-			logWatcher.Msg <- &logger.Message{
-				Line:      line,
-				Source:    source,
-				Timestamp: timestamp.In(time.UTC),
-				Attrs:     attrs,
+			// Send the log message, if it's selected.
+			if filter.Match(m) {
+				logWatcher.Msg <- m
 			}
 		}
 		// If we're at the end of the journal, we're done (for now).
@@ -253,7 +271,7 @@ drain:
 	return cursor, done
 }
 
-func (s *journald) followJournal(logWatcher *logger.LogWatcher, j *C.sd_journal, pfd [2]C.int, cursor *C.char, untilUnixMicro uint64) *C.char {
+func (s *journald) followJournal(logWatcher *logger.LogWatcher, j *C.sd_journal, pfd [2]C.int, cursor *C.char, untilUnixMicro uint64, filter *logger.LogFilter) *C.char {
 	s.mu.Lock()
 	s.readers[logWatcher] = struct{}{}
 	if s.closed {
@@ -281,7 +299,7 @@ func (s *journald) followJournal(logWatcher *logger.LogWatcher, j *C.sd_journal,
 			}
 
 			var done bool
-			cursor, done = s.drainJournal(logWatcher, j, cursor, untilUnixMicro)
+			cursor, done = s.drainJournal(logWatcher, j, cursor, untilUnixMicro, filter)
 
 			if status != 1 || done {
 				// We were notified to stop
@@ -388,6 +406,19 @@ func (s *journald) readLogs(logWatcher *logger.LogWatcher, config logger.ReadCon
 					break
 				}
 			}
+			// Only count the entries selected by the filter.
+			if config.Filter != nil {
+				m, _, err := readEntry(j)
+				if err != nil {
+					break
+				}
+				if m == nil || !config.Filter.Match(m) {
+					if C.sd_journal_previous(j) <= 0 {
+						break
+					}
+					continue
+				}
+			}
 			lines--
 			// If we're at the start of the journal, or
 			// don't need to back up past any more entries,
@@ -412,7 +443,7 @@ func (s *journald) readLogs(logWatcher *logger.LogWatcher, config logger.ReadCon
 			return
 		}
 	}
-	cursor, _ = s.drainJournal(logWatcher, j, nil, untilUnixMicro)
+	cursor, _ = s.drainJournal(logWatcher, j, nil, untilUnixMicro, config.Filter)
 	if config.Follow {
 		// Allocate a descriptor for following the journal, if we'll
 		// need one.  Do it here so that we can report if it fails.
@@ -424,7 +455,7 @@ func (s *journald) readLogs(logWatcher *logger.LogWatcher, config logger.ReadCon
 			if C.pipe(&pipes[0]) == C.int(-1) {
 				logWatcher.Err <- fmt.Errorf("error opening journald close notification pipe")
 			} else {
-				cursor = s.followJournal(logWatcher, j, pipes, cursor, untilUnixMicro)
+				cursor = s.followJournal(logWatcher, j, pipes, cursor, untilUnixMicro, config.Filter)
 				// Let followJournal handle freeing the journal context
 				// object and closing the channel.
 				following = true
@@ -102,6 +102,25 @@ func New(info logger.Info) (logger.Logger, error) {
 
 	buf := bytes.NewBuffer(nil)
 	marshalFunc := func(msg *logger.Message) ([]byte, error) {
+		extra := extra
+		if len(msg.Attrs) > 0 {
+			// attributes of the message, such as the fields of structured
+			// log lines, are stored along with the extra attributes but
+			// do not replace them.
+			msgAttrs := make(map[string]string, len(attrs)+len(msg.Attrs))
+			for k, v := range attrs {
+				msgAttrs[k] = v
+			}
+			for _, attr := range msg.Attrs {
+				if _, ok := msgAttrs[attr.Key]; !ok {
+					msgAttrs[attr.Key] = attr.Value
+				}
+			}
+			var err error
+			if extra, err = json.Marshal(msgAttrs); err != nil {
+				return nil, err
+			}
+		}
 		if err := marshalMessage(msg, extra, buf); err != nil {
 			return nil, err
 		}
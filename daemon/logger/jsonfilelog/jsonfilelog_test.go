@@ -13,6 +13,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/docker/docker/api/types/backend"
 	"github.com/docker/docker/daemon/logger"
 	"github.com/docker/docker/daemon/logger/jsonfilelog/jsonlog"
 	"gotest.tools/assert"
@@ -289,7 +290,9 @@ func TestJSONFileLoggerWithLabelsEnv(t *testing.T) {
 		t.Fatal(err)
 	}
 	defer l.Close()
-	if err := l.Log(&logger.Message{Line: []byte("line"), Source: "src1"}); err != nil {
+	// attributes of the message do not replace labels and env attributes
+	attrs := []backend.LogAttr{{Key: "rack", Value: "666"}, {Key: "level", Value: "info"}}
+	if err := l.Log(&logger.Message{Line: []byte("line"), Source: "src1", Attrs: attrs}); err != nil {
 		t.Fatal(err)
 	}
 	res, err := ioutil.ReadFile(filename)
@@ -312,6 +315,7 @@ func TestJSONFileLoggerWithLabelsEnv(t *testing.T) {
 		"debug":     "false",
 		"ssl":       "true",
 		"dc_region": "west",
+		"level":     "info",
 	}
 	if !reflect.DeepEqual(extra, expected) {
 		t.Fatalf("Wrong log attrs: %q, expected %q", extra, expected)
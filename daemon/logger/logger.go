@@ -91,6 +91,9 @@ type ReadConfig struct {
 	Until  time.Time
 	Tail   int
 	Follow bool
+	// Filter selects the messages to return, including when following
+	// the logs. Tail applies to the messages which match the filter.
+	Filter *LogFilter
 }
 
 // LogReader is the interface for reading log messages for loggers that support reading.
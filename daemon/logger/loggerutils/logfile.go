@@ -373,7 +373,7 @@ func (w *LogFile) ReadLogs(config logger.ReadConfig, watcher *logger.LogWatcher)
 
 	notifyRotate := w.notifyRotate.Subscribe()
 	defer w.notifyRotate.Evict(notifyRotate)
-	followLogs(currentFile, watcher, notifyRotate, w.createDecoder, config.Since, config.Until)
+	followLogs(currentFile, watcher, notifyRotate, w.createDecoder, config.Since, config.Until, config.Filter)
 }
 
 func (w *LogFile) openRotatedFiles(config logger.ReadConfig) (files []*os.File, err error) {
@@ -495,7 +495,14 @@ func tailFiles(files []SizeReaderAt, watcher *logger.LogWatcher, createDecoder m
 
 	readers := make([]io.Reader, 0, len(files))
 
-	if config.Tail > 0 {
+	// with a filter, the last lines of the files may not match, so all the
+	// files are read and only the last matching messages are kept.
+	var matches *messageRing
+	if config.Tail > 0 && config.Filter != nil {
+		matches = newMessageRing(config.Tail)
+	}
+
+	if config.Tail > 0 && matches == nil {
 		for i := len(files) - 1; i >= 0 && nLines > 0; i-- {
 			tail, n, err := getTailReader(ctx, files[i], nLines)
 			if err != nil {
@@ -518,15 +525,34 @@ func tailFiles(files []SizeReaderAt, watcher *logger.LogWatcher, createDecoder m
 		if err != nil {
 			if errors.Cause(err) != io.EOF {
 				watcher.Err <- err
+				return
 			}
-			return
+			break
 		}
 		if !config.Since.IsZero() && msg.Timestamp.Before(config.Since) {
 			continue
 		}
 		if !config.Until.IsZero() && msg.Timestamp.After(config.Until) {
+			break
+		}
+		if !config.Filter.Match(msg) {
+			continue
+		}
+		if matches != nil {
+			matches.push(msg)
+			continue
+		}
+		select {
+		case <-ctx.Done():
 			return
+		case watcher.Msg <- msg:
 		}
+	}
+
+	if matches == nil {
+		return
+	}
+	for _, msg := range matches.messages() {
 		select {
 		case <-ctx.Done():
 			return
@@ -535,7 +561,31 @@ func tailFiles(files []SizeReaderAt, watcher *logger.LogWatcher, createDecoder m
 	}
 }
 
-func followLogs(f *os.File, logWatcher *logger.LogWatcher, notifyRotate chan interface{}, createDecoder makeDecoderFunc, since, until time.Time) {
+// messageRing keeps the last messages pushed to it.
+type messageRing struct {
+	msgs  []*logger.Message
+	start int
+}
+
+func newMessageRing(size int) *messageRing {
+	return &messageRing{msgs: make([]*logger.Message, 0, size)}
+}
+
+func (r *messageRing) push(msg *logger.Message) {
+	if len(r.msgs) < cap(r.msgs) {
+		r.msgs = append(r.msgs, msg)
+		return
+	}
+	r.msgs[r.start] = msg
+	r.start = (r.start + 1) % len(r.msgs)
+}
+
+// messages returns the messages of the ring, oldest first.
+func (r *messageRing) messages() []*logger.Message {
+	return append(r.msgs[r.start:len(r.msgs):len(r.msgs)], r.msgs[:r.start]...)
+}
+
+func followLogs(f *os.File, logWatcher *logger.LogWatcher, notifyRotate chan interface{}, createDecoder makeDecoderFunc, since, until time.Time, filter *logger.LogFilter) {
 	decodeLogLine := createDecoder(f)
 
 	name := f.Name()
@@ -655,6 +705,9 @@ func followLogs(f *os.File, logWatcher *logger.LogWatcher, notifyRotate chan int
 		if !until.IsZero() && msg.Timestamp.After(until) {
 			return
 		}
+		if !filter.Match(msg) {
+			continue
+		}
 		// send the message, unless the consumer is gone
 		select {
 		case logWatcher.Msg <- msg:
@@ -10,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/daemon/logger"
 	"github.com/docker/docker/pkg/tailfile"
 	"gotest.tools/assert"
@@ -77,6 +78,42 @@ func TestTailFiles(t *testing.T) {
 	}
 }
 
+func TestTailFilesFilter(t *testing.T) {
+	s1 := strings.NewReader("Hello.\nMy name is Inigo Montoya.\n")
+	s2 := strings.NewReader("I'm serious.\nDon't call me Shirley!\n")
+	s3 := strings.NewReader("Roads?\nWhere we're going we don't need roads.\n")
+
+	files := []SizeReaderAt{s1, s2, s3}
+	watcher := logger.NewLogWatcher()
+	createDecoder := func(r io.Reader) func() (*logger.Message, error) {
+		scanner := bufio.NewScanner(r)
+		return func() (*logger.Message, error) {
+			if !scanner.Scan() {
+				return nil, io.EOF
+			}
+			return &logger.Message{Line: []byte(scanner.Text()), Timestamp: time.Now()}, nil
+		}
+	}
+	tailReader := func(ctx context.Context, r SizeReaderAt, lines int) (io.Reader, int, error) {
+		return tailfile.NewTailReader(ctx, r, lines)
+	}
+
+	// the last two lines containing "m" are not the last two lines
+	filter, err := logger.NewLogFilter("m", true, true, filters.NewArgs())
+	assert.NilError(t, err)
+	config := logger.ReadConfig{Tail: 2, Filter: filter}
+	go func() {
+		tailFiles(files, watcher, createDecoder, tailReader, config)
+		close(watcher.Msg)
+	}()
+
+	var lines []string
+	for msg := range watcher.Msg {
+		lines = append(lines, string(msg.Line))
+	}
+	assert.DeepEqual(t, lines, []string{"I'm serious.", "Don't call me Shirley!"})
+}
+
 func TestFollowLogsConsumerGone(t *testing.T) {
 	lw := logger.NewLogWatcher()
 
@@ -96,7 +133,7 @@ func TestFollowLogsConsumerGone(t *testing.T) {
 	followLogsDone := make(chan struct{})
 	var since, until time.Time
 	go func() {
-		followLogs(f, lw, make(chan interface{}), makeDecoder, since, until)
+		followLogs(f, lw, make(chan interface{}), makeDecoder, since, until, nil)
 		close(followLogsDone)
 	}()
 
@@ -144,7 +181,7 @@ func TestFollowLogsProducerGone(t *testing.T) {
 
 	followLogsDone := make(chan struct{})
 	go func() {
-		followLogs(f, lw, make(chan interface{}), makeDecoder, since, until)
+		followLogs(f, lw, make(chan interface{}), makeDecoder, since, until, nil)
 		close(followLogsDone)
 	}()
 
@@ -8,6 +8,9 @@ var (
 	logWritesFailedCount metrics.Counter
 	logReadsFailedCount  metrics.Counter
 	totalPartialLogs     metrics.Counter
+
+	rateLimitedLogsCount  metrics.Counter
+	rateLimitedBytesCount metrics.Counter
 )
 
 func init() {
@@ -16,6 +19,8 @@ func init() {
 	logWritesFailedCount = loggerMetrics.NewCounter("log_write_operations_failed", "Number of log write operations that failed")
 	logReadsFailedCount = loggerMetrics.NewCounter("log_read_operations_failed", "Number of log reads from container stdio that failed")
 	totalPartialLogs = loggerMetrics.NewCounter("log_entries_size_greater_than_buffer", "Number of log entries which are larger than the log buffer")
+	rateLimitedLogsCount = loggerMetrics.NewCounter("log_entries_dropped_rate_limit", "Number of log entries dropped because they exceeded the log rate limits")
+	rateLimitedBytesCount = loggerMetrics.NewCounter("log_bytes_dropped_rate_limit", "Number of bytes of log entries dropped because they exceeded the log rate limits")
 
 	metrics.Register(loggerMetrics)
 }
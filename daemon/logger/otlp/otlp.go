@@ -0,0 +1,497 @@
+// Package otlp provides the log driver for forwarding server logs to
+// OpenTelemetry collectors using the OTLP/HTTP protocol.
+package otlp // import "github.com/docker/docker/daemon/logger/otlp"
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/daemon/logger"
+	"github.com/docker/docker/daemon/logger/loggerutils"
+	"github.com/docker/docker/pkg/pools"
+	"github.com/docker/docker/pkg/urlutil"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	driverName = "otlp"
+
+	endpointKey      = "otlp-endpoint"
+	headersKey       = "otlp-headers"
+	compressionKey   = "otlp-compression"
+	timeoutKey       = "otlp-timeout"
+	batchSizeKey     = "otlp-batch-size"
+	batchIntervalKey = "otlp-batch-interval"
+	maxRetriesKey    = "otlp-max-retries"
+	envKey           = "env"
+	envRegexKey      = "env-regex"
+	labelsKey        = "labels"
+	tagKey           = "tag"
+)
+
+const (
+	// logsPath is the path of the OTLP/HTTP logs endpoint, used when the
+	// endpoint option has no path.
+	logsPath = "/v1/logs"
+
+	defaultTimeout       = 10 * time.Second
+	defaultBatchSize     = 512
+	defaultBatchInterval = time.Second
+	defaultMaxRetries    = 5
+
+	initialRetryDelay = 500 * time.Millisecond
+	maxRetryDelay     = 30 * time.Second
+
+	// maxResponseSize is the max amount that will be read from an http response
+	maxResponseSize = 1024
+)
+
+// severity numbers of the OpenTelemetry log data model.
+const (
+	severityTrace = 1
+	severityDebug = 5
+	severityInfo  = 9
+	severityWarn  = 13
+	severityError = 17
+	severityFatal = 21
+)
+
+type otlpLogger struct {
+	client      *http.Client
+	url         string
+	headers     map[string]string
+	compression bool
+	resource    resource
+
+	batchSize     int
+	batchInterval time.Duration
+	maxRetries    int
+
+	// records are sent to the worker through the stream channel, which is
+	// closed, under lock, to flush the records and stop the worker.
+	stream chan logRecord
+	mu     sync.RWMutex
+	closed bool
+	done   chan struct{}
+}
+
+func init() {
+	if err := logger.RegisterLogDriver(driverName, New); err != nil {
+		logrus.Fatal(err)
+	}
+	if err := logger.RegisterLogOptValidator(driverName, ValidateLogOpt); err != nil {
+		logrus.Fatal(err)
+	}
+}
+
+// New creates an otlp logger using the configuration passed in on the
+// context.
+func New(info logger.Info) (logger.Logger, error) {
+	endpoint, err := parseEndpoint(info.Config[endpointKey])
+	if err != nil {
+		return nil, err
+	}
+	headers, err := parseHeaders(info.Config[headersKey])
+	if err != nil {
+		return nil, err
+	}
+
+	l := &otlpLogger{
+		url:           endpoint,
+		headers:       headers,
+		batchSize:     defaultBatchSize,
+		batchInterval: defaultBatchInterval,
+		maxRetries:    defaultMaxRetries,
+		done:          make(chan struct{}),
+	}
+	timeout := defaultTimeout
+	if s, ok := info.Config[timeoutKey]; ok {
+		if timeout, err = parsePositiveDuration(timeoutKey, s); err != nil {
+			return nil, err
+		}
+	}
+	if s, ok := info.Config[batchIntervalKey]; ok {
+		if l.batchInterval, err = parsePositiveDuration(batchIntervalKey, s); err != nil {
+			return nil, err
+		}
+	}
+	if s, ok := info.Config[batchSizeKey]; ok {
+		if l.batchSize, err = parseInt(batchSizeKey, s, 1); err != nil {
+			return nil, err
+		}
+	}
+	if s, ok := info.Config[maxRetriesKey]; ok {
+		if l.maxRetries, err = parseInt(maxRetriesKey, s, 0); err != nil {
+			return nil, err
+		}
+	}
+	if s, ok := info.Config[compressionKey]; ok {
+		switch s {
+		case "gzip":
+			l.compression = true
+		case "none":
+		default:
+			return nil, fmt.Errorf("%s: unsupported %s: %s", driverName, compressionKey, s)
+		}
+	}
+
+	l.resource, err = newResource(info)
+	if err != nil {
+		return nil, err
+	}
+	l.client = &http.Client{Timeout: timeout}
+	l.stream = make(chan logRecord, 4*l.batchSize)
+
+	go l.worker()
+	return l, nil
+}
+
+// newResource returns the resource describing the container, using the
+// OpenTelemetry semantic conventions.
+func newResource(info logger.Info) (resource, error) {
+	tag, err := loggerutils.ParseLogTag(info, "{{.Name}}")
+	if err != nil {
+		return resource{}, err
+	}
+	hostname, err := info.Hostname()
+	if err != nil {
+		return resource{}, err
+	}
+	extra, err := info.ExtraAttributes(nil)
+	if err != nil {
+		return resource{}, err
+	}
+
+	attrs := map[string]string{
+		"service.name":         tag,
+		"host.name":            hostname,
+		"container.id":         info.ContainerID,
+		"container.name":       info.Name(),
+		"container.runtime":    "docker",
+		"container.image.name": info.ImageName(),
+		"container.image.id":   info.ImageFullID(),
+	}
+	for k, v := range extra {
+		if _, ok := attrs[k]; !ok {
+			attrs[k] = v
+		}
+	}
+	return resource{Attributes: sortedAttributes(attrs)}, nil
+}
+
+func (l *otlpLogger) Log(msg *logger.Message) error {
+	record := newLogRecord(msg)
+	logger.PutMessage(msg)
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.closed {
+		return fmt.Errorf("%s: driver is closed", driverName)
+	}
+	l.stream <- record
+	return nil
+}
+
+func newLogRecord(msg *logger.Message) logRecord {
+	severity, severityText := severityInfo, "INFO"
+	if msg.Source == "stderr" {
+		severity, severityText = severityError, "ERROR"
+	}
+
+	attrs := []keyValue{stringAttribute("log.iostream", msg.Source)}
+	if msg.PLogMetaData != nil {
+		attrs = append(attrs, keyValue{Key: "log.partial", Value: anyValue{BoolValue: boolPtr(!msg.PLogMetaData.Last)}})
+	}
+	for _, attr := range msg.Attrs {
+		if attr.Key == logger.LevelAttr {
+			if n, ok := parseSeverity(attr.Value); ok {
+				severity, severityText = n, attr.Value
+				continue
+			}
+		}
+		attrs = append(attrs, stringAttribute(attr.Key, attr.Value))
+	}
+
+	return logRecord{
+		TimeUnixNano:         strconv.FormatInt(msg.Timestamp.UnixNano(), 10),
+		ObservedTimeUnixNano: strconv.FormatInt(time.Now().UnixNano(), 10),
+		SeverityNumber:       severity,
+		SeverityText:         severityText,
+		Body:                 anyValue{StringValue: stringPtr(string(msg.Line))},
+		Attributes:           attrs,
+	}
+}
+
+// parseSeverity returns the severity number of a level name.
+func parseSeverity(level string) (int, bool) {
+	switch strings.ToLower(level) {
+	case "trace":
+		return severityTrace, true
+	case "debug":
+		return severityDebug, true
+	case "info", "notice":
+		return severityInfo, true
+	case "warn", "warning":
+		return severityWarn, true
+	case "err", "error", "crit", "critical", "alert":
+		return severityError, true
+	case "fatal", "panic", "emerg", "emergency":
+		return severityFatal, true
+	}
+	return 0, false
+}
+
+func (l *otlpLogger) worker() {
+	defer close(l.done)
+
+	ticker := time.NewTicker(l.batchInterval)
+	defer ticker.Stop()
+
+	var records []logRecord
+	for {
+		select {
+		case record, open := <-l.stream:
+			if !open {
+				l.send(records, true)
+				return
+			}
+			records = append(records, record)
+			if len(records) >= l.batchSize {
+				l.send(records, false)
+				records = records[:0]
+			}
+		case <-ticker.C:
+			if len(records) > 0 {
+				l.send(records, false)
+				records = records[:0]
+			}
+		}
+	}
+}
+
+// send posts a batch of records to the collector, retrying with an
+// exponential backoff. Records are dropped once all retries failed, or if the
+// collector rejected them. When closing, records are only tried once, so
+// that stopping the container is not delayed by an unreachable collector.
+func (l *otlpLogger) send(records []logRecord, closing bool) {
+	if len(records) == 0 {
+		return
+	}
+	body, err := l.encode(records)
+	if err != nil {
+		logrus.WithError(err).WithField("module", "logger/otlp").Error("Error encoding logs")
+		return
+	}
+
+	delay := initialRetryDelay
+	for attempt := 0; ; attempt++ {
+		retry, err := l.post(body)
+		if err == nil {
+			return
+		}
+		if !retry || closing || attempt >= l.maxRetries {
+			logrus.WithError(err).WithField("module", "logger/otlp").Errorf("Failed to send %d log records, dropping them", len(records))
+			return
+		}
+		logrus.WithError(err).WithField("module", "logger/otlp").Debugf("Error while sending logs, retrying in %s", delay)
+		time.Sleep(delay)
+		delay *= 2
+		if delay > maxRetryDelay {
+			delay = maxRetryDelay
+		}
+	}
+}
+
+func (l *otlpLogger) encode(records []logRecord) ([]byte, error) {
+	req := exportLogsRequest{
+		ResourceLogs: []resourceLogs{{
+			Resource: l.resource,
+			ScopeLogs: []scopeLogs{{
+				Scope:      scope{Name: "docker"},
+				LogRecords: records,
+			}},
+		}},
+	}
+
+	var buf bytes.Buffer
+	var w io.Writer = &buf
+	var gz *gzip.Writer
+	if l.compression {
+		gz = gzip.NewWriter(&buf)
+		w = gz
+	}
+	if err := json.NewEncoder(w).Encode(req); err != nil {
+		return nil, err
+	}
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// post sends an encoded request to the collector. It returns whether the
+// request may be retried if it failed.
+func (l *otlpLogger) post(body []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, l.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), l.client.Timeout)
+	defer cancel()
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	if l.compression {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	for k, v := range l.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer func() {
+		pools.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	err = fmt.Errorf("%s: failed to send logs - %s - %s", driverName, resp.Status, strings.TrimSpace(string(msg)))
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true, err
+	}
+	return false, err
+}
+
+// Close flushes the buffered records and stops the logger.
+func (l *otlpLogger) Close() error {
+	l.mu.Lock()
+	if !l.closed {
+		l.closed = true
+		close(l.stream)
+	}
+	l.mu.Unlock()
+	<-l.done
+	return nil
+}
+
+func (l *otlpLogger) Name() string {
+	return driverName
+}
+
+// ValidateLogOpt looks for all supported by otlp driver options
+func ValidateLogOpt(cfg map[string]string) error {
+	for key, value := range cfg {
+		var err error
+		switch key {
+		case endpointKey:
+			_, err = parseEndpoint(value)
+		case headersKey:
+			_, err = parseHeaders(value)
+		case compressionKey:
+			if value != "gzip" && value != "none" {
+				err = fmt.Errorf("%s: unsupported %s: %s", driverName, compressionKey, value)
+			}
+		case timeoutKey, batchIntervalKey:
+			_, err = parsePositiveDuration(key, value)
+		case batchSizeKey:
+			_, err = parseInt(key, value, 1)
+		case maxRetriesKey:
+			_, err = parseInt(key, value, 0)
+		case envKey:
+		case envRegexKey:
+		case labelsKey:
+		case tagKey:
+		default:
+			return fmt.Errorf("unknown log opt '%s' for %s log driver", key, driverName)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if _, ok := cfg[endpointKey]; !ok {
+		return fmt.Errorf("%s: %s is expected", driverName, endpointKey)
+	}
+	return nil
+}
+
+// parseEndpoint returns the URL of the logs endpoint of a collector. The
+// default logs path is used if the endpoint has no path.
+func parseEndpoint(endpoint string) (string, error) {
+	if endpoint == "" {
+		return "", fmt.Errorf("%s: %s is expected", driverName, endpointKey)
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || !urlutil.IsURL(endpoint) || !u.IsAbs() || u.Host == "" {
+		return "", fmt.Errorf("%s: expected format http(s)://host:port[/path] for %s", driverName, endpointKey)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = logsPath
+	}
+	return u.String(), nil
+}
+
+// parseHeaders parses a comma-separated list of key=value headers.
+func parseHeaders(s string) (map[string]string, error) {
+	headers := make(map[string]string)
+	if s == "" {
+		return headers, nil
+	}
+	for _, h := range strings.Split(s, ",") {
+		kv := strings.SplitN(h, "=", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+			return nil, fmt.Errorf("%s: invalid header '%s' in %s, expected key=value", driverName, h, headersKey)
+		}
+		headers[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+	}
+	return headers, nil
+}
+
+func parsePositiveDuration(key, s string) (time.Duration, error) {
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("%s: %s must be a positive duration: %s", driverName, key, s)
+	}
+	return d, nil
+}
+
+func parseInt(key, s string, min int) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < min {
+		return 0, fmt.Errorf("%s: %s must be an integer greater than or equal to %d: %s", driverName, key, min, s)
+	}
+	return n, nil
+}
+
+func sortedAttributes(attrs map[string]string) []keyValue {
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	kvs := make([]keyValue, 0, len(keys))
+	for _, k := range keys {
+		kvs = append(kvs, stringAttribute(k, attrs[k]))
+	}
+	return kvs
+}
@@ -0,0 +1,197 @@
+package otlp // import "github.com/docker/docker/daemon/logger/otlp"
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/backend"
+	"github.com/docker/docker/daemon/logger"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+// mockCollector is an OTLP/HTTP collector which fails the first requests.
+type mockCollector struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	failures int
+	requests int
+	headers  []http.Header
+	received []exportLogsRequest
+}
+
+func newMockCollector(failures int) *mockCollector {
+	c := &mockCollector{failures: failures}
+	c.Server = httptest.NewServer(http.HandlerFunc(c.handle))
+	return c
+}
+
+func (c *mockCollector) handle(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests++
+	if r.URL.Path != logsPath {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if c.failures > 0 {
+		c.failures--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	var body io.Reader = r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body = gz
+	}
+	var req exportLogsRequest
+	if err := json.NewDecoder(body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	c.headers = append(c.headers, r.Header)
+	c.received = append(c.received, req)
+}
+
+func (c *mockCollector) records() []logRecord {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var records []logRecord
+	for _, req := range c.received {
+		for _, rl := range req.ResourceLogs {
+			for _, sl := range rl.ScopeLogs {
+				records = append(records, sl.LogRecords...)
+			}
+		}
+	}
+	return records
+}
+
+func newTestInfo(config map[string]string) logger.Info {
+	return logger.Info{
+		Config:             config,
+		ContainerID:        "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+		ContainerName:      "/web",
+		ContainerImageID:   "sha256:abcdef",
+		ContainerImageName: "nginx:latest",
+		ContainerLabels:    map[string]string{"tier": "frontend"},
+	}
+}
+
+func TestLogBatches(t *testing.T) {
+	collector := newMockCollector(0)
+	defer collector.Close()
+
+	l, err := New(newTestInfo(map[string]string{
+		endpointKey:      collector.URL,
+		headersKey:       "Authorization=Bearer secret",
+		compressionKey:   "gzip",
+		batchSizeKey:     "2",
+		batchIntervalKey: "1h",
+		labelsKey:        "tier",
+	}))
+	assert.NilError(t, err)
+
+	ts := time.Unix(1546398245, 0)
+	for i, line := range []string{"first", "second", "third"} {
+		msg := logger.NewMessage()
+		msg.Line = append(msg.Line, line...)
+		msg.Source = "stdout"
+		msg.Timestamp = ts
+		if i == 2 {
+			msg.Source = "stderr"
+			msg.Attrs = []backend.LogAttr{{Key: logger.LevelAttr, Value: "warn"}, {Key: "user", Value: "alice"}}
+		}
+		assert.NilError(t, l.Log(msg))
+	}
+	// the last record is sent when the logger is closed
+	assert.NilError(t, l.Close())
+
+	assert.Check(t, is.Len(collector.received, 2))
+	assert.Check(t, is.Equal(collector.headers[0].Get("Authorization"), "Bearer secret"))
+
+	res := collector.received[0].ResourceLogs[0].Resource
+	attrs := make(map[string]string)
+	for _, kv := range res.Attributes {
+		attrs[kv.Key] = *kv.Value.StringValue
+	}
+	assert.Check(t, is.Equal(attrs["service.name"], "web"))
+	assert.Check(t, is.Equal(attrs["container.name"], "web"))
+	assert.Check(t, is.Equal(attrs["container.image.name"], "nginx:latest"))
+	assert.Check(t, is.Equal(attrs["tier"], "frontend"))
+
+	records := collector.records()
+	assert.Assert(t, is.Len(records, 3))
+	assert.Check(t, is.Equal(*records[0].Body.StringValue, "first"))
+	assert.Check(t, is.Equal(records[0].TimeUnixNano, "1546398245000000000"))
+	assert.Check(t, is.Equal(records[0].SeverityNumber, severityInfo))
+	assert.Check(t, is.Equal(*records[2].Body.StringValue, "third"))
+	assert.Check(t, is.Equal(records[2].SeverityNumber, severityWarn))
+	assert.Check(t, is.DeepEqual(records[2].Attributes, []keyValue{
+		stringAttribute("log.iostream", "stderr"),
+		stringAttribute("user", "alice"),
+	}))
+}
+
+func TestLogRetries(t *testing.T) {
+	collector := newMockCollector(2)
+	defer collector.Close()
+
+	l, err := New(newTestInfo(map[string]string{
+		endpointKey:      collector.URL,
+		batchSizeKey:     "1",
+		batchIntervalKey: "1h",
+	}))
+	assert.NilError(t, err)
+
+	msg := logger.NewMessage()
+	msg.Line = append(msg.Line, "hello"...)
+	assert.NilError(t, l.Log(msg))
+
+	deadline := time.Now().Add(10 * time.Second)
+	for len(collector.records()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.NilError(t, l.Close())
+	assert.Check(t, is.Len(collector.records(), 1))
+	assert.Check(t, is.Equal(collector.requests, 3))
+}
+
+func TestValidateLogOpt(t *testing.T) {
+	assert.NilError(t, ValidateLogOpt(map[string]string{
+		endpointKey:   "https://collector:4318",
+		headersKey:    "a=b,c=d",
+		batchSizeKey:  "100",
+		maxRetriesKey: "0",
+		timeoutKey:    "5s",
+		tagKey:        "{{.Name}}",
+	}))
+
+	for _, cfg := range []map[string]string{
+		{},
+		{endpointKey: "collector:4318"},
+		{endpointKey: "http://collector:4318", headersKey: "a"},
+		{endpointKey: "http://collector:4318", batchSizeKey: "0"},
+		{endpointKey: "http://collector:4318", timeoutKey: "-1s"},
+		{endpointKey: "http://collector:4318", compressionKey: "zstd"},
+		{endpointKey: "http://collector:4318", "unknown": "value"},
+	} {
+		assert.Check(t, ValidateLogOpt(cfg) != nil, "expected error for %v", cfg)
+	}
+
+	endpoint, err := parseEndpoint("http://collector:4318/custom/logs")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(endpoint, "http://collector:4318/custom/logs"))
+}
@@ -0,0 +1,294 @@
+package logger // import "github.com/docker/docker/daemon/logger"
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	units "github.com/docker/go-units"
+	"github.com/pkg/errors"
+)
+
+// Log options to limit the rate of messages. These options are supported by
+// all log drivers.
+const (
+	// RateLimitLinesOpt is the maximum number of messages per second.
+	RateLimitLinesOpt = "rate-limit-lines"
+	// RateLimitBytesOpt is the maximum size of messages per second, for
+	// example "1m".
+	RateLimitBytesOpt = "rate-limit-bytes"
+	// RateLimitBurstOpt is the number of messages which can be logged at
+	// once when no message was logged for a while. It defaults to the
+	// number of messages per second.
+	RateLimitBurstOpt = "rate-limit-burst"
+	// RateLimitSampleOpt keeps one of every N messages exceeding the limits
+	// instead of dropping all of them.
+	RateLimitSampleOpt = "rate-limit-sample"
+)
+
+// dropReportInterval is the interval between the messages reporting how
+// many messages were dropped.
+const dropReportInterval = 10 * time.Second
+
+type rateLimitConfig struct {
+	lines  float64
+	bytes  float64
+	burst  float64
+	sample int
+}
+
+func parseRateLimitConfig(cfg map[string]string) (*rateLimitConfig, error) {
+	c := &rateLimitConfig{}
+	if s, ok := cfg[RateLimitLinesOpt]; ok {
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil || v <= 0 {
+			return nil, errors.Errorf("logger: %s must be a positive number: %s", RateLimitLinesOpt, s)
+		}
+		c.lines = v
+	}
+	if s, ok := cfg[RateLimitBytesOpt]; ok {
+		v, err := units.RAMInBytes(s)
+		if err != nil || v <= 0 {
+			return nil, errors.Errorf("logger: %s must be a positive size: %s", RateLimitBytesOpt, s)
+		}
+		c.bytes = float64(v)
+	}
+	if c.lines == 0 && c.bytes == 0 {
+		for _, k := range []string{RateLimitBurstOpt, RateLimitSampleOpt} {
+			if _, ok := cfg[k]; ok {
+				return nil, errors.Errorf("logger: %s option requires the %s or %s option", k, RateLimitLinesOpt, RateLimitBytesOpt)
+			}
+		}
+		return nil, nil
+	}
+
+	c.burst = c.lines
+	if s, ok := cfg[RateLimitBurstOpt]; ok {
+		if c.lines == 0 {
+			return nil, errors.Errorf("logger: %s option requires the %s option", RateLimitBurstOpt, RateLimitLinesOpt)
+		}
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil || v < 1 {
+			return nil, errors.Errorf("logger: %s must be a number greater than or equal to 1: %s", RateLimitBurstOpt, s)
+		}
+		c.burst = v
+	}
+	if c.burst > 0 && c.burst < 1 {
+		c.burst = 1
+	}
+	if s, ok := cfg[RateLimitSampleOpt]; ok {
+		v, err := strconv.Atoi(s)
+		if err != nil || v < 1 {
+			return nil, errors.Errorf("logger: %s must be a positive integer: %s", RateLimitSampleOpt, s)
+		}
+		c.sample = v
+	}
+	return c, nil
+}
+
+// tokenBucket is a token bucket holding up to capacity tokens, refilled at
+// rate tokens per second.
+type tokenBucket struct {
+	rate     float64
+	capacity float64
+	tokens   float64
+	last     time.Time
+}
+
+func newTokenBucket(rate, capacity float64, now time.Time) *tokenBucket {
+	return &tokenBucket{rate: rate, capacity: capacity, tokens: capacity, last: now}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+	b.last = now
+}
+
+// has returns true if n tokens can be taken from the bucket. Requests larger
+// than the capacity of the bucket are accepted when the bucket is full.
+func (b *tokenBucket) has(n float64) bool {
+	if n > b.capacity {
+		n = b.capacity
+	}
+	return b.tokens >= n
+}
+
+func (b *tokenBucket) take(n float64) {
+	b.tokens -= n
+}
+
+// rateLimiter is a Logger which drops the messages exceeding the configured
+// rates before they reach the driver, and regularly logs how many messages
+// were dropped.
+type rateLimiter struct {
+	driver Logger
+	sample int
+
+	mu       sync.Mutex
+	lines    *tokenBucket
+	bytes    *tokenBucket
+	exceeded int
+	dropped  int
+	now      func() time.Time
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+type rateLimiterWithReader struct {
+	*rateLimiter
+}
+
+func (r *rateLimiterWithReader) ReadLogs(cfg ReadConfig) *LogWatcher {
+	reader, ok := r.driver.(LogReader)
+	if !ok {
+		// something is wrong if we get here
+		panic("expected log reader")
+	}
+	return reader.ReadLogs(cfg)
+}
+
+// NewRateLimiter wraps the driver with a logger which limits the rate of
+// messages as configured by the rate-limit options of cfg. It returns the
+// driver as is if cfg has no rate-limit option.
+func NewRateLimiter(driver Logger, cfg map[string]string) (Logger, error) {
+	c, err := parseRateLimitConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return driver, nil
+	}
+	ticker := time.NewTicker(dropReportInterval)
+	r := newRateLimiter(driver, c, time.Now, ticker.C)
+	go func() {
+		<-r.done
+		ticker.Stop()
+	}()
+	if _, ok := driver.(LogReader); ok {
+		return &rateLimiterWithReader{r}, nil
+	}
+	return r, nil
+}
+
+// newRateLimiter returns a rateLimiter which reports the dropped messages
+// each time it receives from tick.
+func newRateLimiter(driver Logger, c *rateLimitConfig, now func() time.Time, tick <-chan time.Time) *rateLimiter {
+	r := &rateLimiter{
+		driver: driver,
+		sample: c.sample,
+		now:    now,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	start := now()
+	if c.lines > 0 {
+		r.lines = newTokenBucket(c.lines, c.burst, start)
+	}
+	if c.bytes > 0 {
+		// the bytes bucket holds one second worth of messages
+		r.bytes = newTokenBucket(c.bytes, c.bytes, start)
+	}
+	go r.run(tick)
+	return r
+}
+
+// run reports the dropped messages regularly, so that they are reported even
+// if the container stops logging, until the rateLimiter is closed.
+func (r *rateLimiter) run(tick <-chan time.Time) {
+	defer close(r.done)
+	for {
+		select {
+		case <-tick:
+			r.flush()
+		case <-r.stop:
+			return
+		}
+	}
+}
+
+// Log passes the message to the driver, unless it exceeds the rate limits.
+func (r *rateLimiter) Log(msg *Message) error {
+	r.mu.Lock()
+	now := r.now()
+	allowed := r.allow(now, len(msg.Line))
+	if !allowed && r.sample > 0 {
+		// keep one of every sample messages exceeding the limits
+		r.exceeded++
+		allowed = r.exceeded%r.sample == 0
+	}
+	if !allowed {
+		r.dropped++
+	}
+	r.mu.Unlock()
+
+	if !allowed {
+		rateLimitedLogsCount.Inc(1)
+		rateLimitedBytesCount.Inc(float64(len(msg.Line)))
+		PutMessage(msg)
+		return nil
+	}
+	return r.driver.Log(msg)
+}
+
+// allow takes tokens from the buckets for a message of size bytes, and
+// returns false if the message exceeds the limits.
+func (r *rateLimiter) allow(now time.Time, size int) bool {
+	if r.lines != nil {
+		r.lines.refill(now)
+	}
+	if r.bytes != nil {
+		r.bytes.refill(now)
+	}
+	if (r.lines != nil && !r.lines.has(1)) || (r.bytes != nil && !r.bytes.has(float64(size))) {
+		return false
+	}
+	if r.lines != nil {
+		r.lines.take(1)
+	}
+	if r.bytes != nil {
+		r.bytes.take(float64(size))
+	}
+	return true
+}
+
+// flush logs a message reporting the number of messages dropped since the
+// last report, if any.
+func (r *rateLimiter) flush() {
+	r.mu.Lock()
+	dropped := r.dropped
+	r.dropped = 0
+	now := r.now()
+	r.mu.Unlock()
+
+	if dropped > 0 {
+		if err := r.driver.Log(newDropReport(dropped, now)); err != nil {
+			logWritesFailedCount.Inc(1)
+		}
+	}
+}
+
+func newDropReport(dropped int, now time.Time) *Message {
+	m := NewMessage()
+	m.Source = "stderr"
+	m.Timestamp = now.UTC()
+	m.Line = append(m.Line, fmt.Sprintf("%d messages dropped by log rate limiting", dropped)...)
+	return m
+}
+
+func (r *rateLimiter) Name() string {
+	return r.driver.Name()
+}
+
+// Close reports the messages dropped since the last report, and closes the
+// driver.
+func (r *rateLimiter) Close() error {
+	close(r.stop)
+	<-r.done
+	r.flush()
+	return r.driver.Close()
+}
@@ -0,0 +1,128 @@
+package logger // import "github.com/docker/docker/daemon/logger"
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time { return c.t }
+
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func logLines(t *testing.T, l Logger, n int, line string) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		msg := NewMessage()
+		msg.Line = append(msg.Line, line...)
+		assert.NilError(t, l.Log(msg))
+	}
+}
+
+func TestRateLimiterLines(t *testing.T) {
+	driver := &attrsLogger{}
+	clock := &fakeClock{t: time.Now()}
+	c, err := parseRateLimitConfig(map[string]string{RateLimitLinesOpt: "10", RateLimitBurstOpt: "20"})
+	assert.NilError(t, err)
+	l := newRateLimiter(driver, c, clock.now, nil)
+
+	// the burst is logged, the rest is dropped
+	logLines(t, l, 30, "hello")
+	assert.Check(t, is.Len(driver.msgs, 20))
+
+	// one second later, 10 more messages can be logged
+	clock.advance(time.Second)
+	logLines(t, l, 30, "hello")
+	assert.Check(t, is.Len(driver.msgs, 30))
+
+	// the dropped messages are reported regularly
+	l.flush()
+	assert.Assert(t, is.Len(driver.msgs, 31))
+	assert.Check(t, is.Equal(string(driver.msgs[30].Line), "30 messages dropped by log rate limiting"))
+	assert.Check(t, is.Equal(driver.msgs[30].Source, "stderr"))
+	l.flush()
+	assert.Check(t, is.Len(driver.msgs, 31))
+
+	// messages dropped since the last report are reported on close
+	logLines(t, l, 30, "hello")
+	assert.NilError(t, l.Close())
+	last := driver.msgs[len(driver.msgs)-1]
+	assert.Check(t, strings.HasSuffix(string(last.Line), "messages dropped by log rate limiting"), string(last.Line))
+}
+
+func TestRateLimiterReportTicker(t *testing.T) {
+	driver := &attrsLogger{}
+	c, err := parseRateLimitConfig(map[string]string{RateLimitLinesOpt: "1"})
+	assert.NilError(t, err)
+	tick := make(chan time.Time)
+	l := newRateLimiter(driver, c, time.Now, tick)
+
+	// the report is logged without waiting for another message
+	msg := NewMessage()
+	msg.Line = append(msg.Line, "hello"...)
+	msg.Source = "stdout"
+	assert.NilError(t, l.Log(msg))
+	msg = NewMessage()
+	msg.Line = append(msg.Line, "hello"...)
+	msg.Source = "stdout"
+	assert.NilError(t, l.Log(msg))
+	tick <- time.Now()
+	assert.NilError(t, l.Close())
+
+	assert.Assert(t, is.Len(driver.msgs, 2))
+	assert.Check(t, is.Equal(string(driver.msgs[1].Line), "1 messages dropped by log rate limiting"))
+	assert.Check(t, is.Equal(driver.msgs[1].Source, "stderr"))
+}
+
+func TestRateLimiterBytes(t *testing.T) {
+	driver := &attrsLogger{}
+	clock := &fakeClock{t: time.Now()}
+	c, err := parseRateLimitConfig(map[string]string{RateLimitBytesOpt: "100b"})
+	assert.NilError(t, err)
+	l := newRateLimiter(driver, c, clock.now, nil)
+
+	logLines(t, l, 3, strings.Repeat("a", 40))
+	assert.Check(t, is.Len(driver.msgs, 2))
+
+	// messages larger than the limit are logged when the bucket is full
+	clock.advance(time.Second)
+	logLines(t, l, 2, strings.Repeat("a", 200))
+	assert.Check(t, is.Len(driver.msgs, 3))
+}
+
+func TestRateLimiterSample(t *testing.T) {
+	driver := &attrsLogger{}
+	clock := &fakeClock{t: time.Now()}
+	c, err := parseRateLimitConfig(map[string]string{RateLimitLinesOpt: "1", RateLimitSampleOpt: "10"})
+	assert.NilError(t, err)
+	l := newRateLimiter(driver, c, clock.now, nil)
+
+	logLines(t, l, 101, "hello")
+	assert.Check(t, is.Len(driver.msgs, 11))
+}
+
+func TestRateLimitConfig(t *testing.T) {
+	c, err := parseRateLimitConfig(map[string]string{})
+	assert.NilError(t, err)
+	assert.Check(t, c == nil)
+
+	for _, cfg := range []map[string]string{
+		{RateLimitLinesOpt: "0"},
+		{RateLimitLinesOpt: "abc"},
+		{RateLimitBytesOpt: "-1"},
+		{RateLimitBurstOpt: "10"},
+		{RateLimitBytesOpt: "1m", RateLimitBurstOpt: "10"},
+		{RateLimitLinesOpt: "10", RateLimitBurstOpt: "0.5"},
+		{RateLimitLinesOpt: "10", RateLimitSampleOpt: "0"},
+	} {
+		_, err := parseRateLimitConfig(cfg)
+		assert.Check(t, err != nil, "expected error for %v", cfg)
+	}
+}
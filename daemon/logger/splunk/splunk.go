@@ -346,6 +346,7 @@ func (l *splunkLoggerInline) Log(msg *logger.Message) error {
 	event := *l.nullEvent
 	event.Line = string(msg.Line)
 	event.Source = msg.Source
+	event.Attrs = withMessageAttrs(event.Attrs, msg)
 
 	message.Event = &event
 	logger.PutMessage(msg)
@@ -364,12 +365,32 @@ func (l *splunkLoggerJSON) Log(msg *logger.Message) error {
 	}
 
 	event.Source = msg.Source
+	event.Attrs = withMessageAttrs(event.Attrs, msg)
 
 	message.Event = &event
 	logger.PutMessage(msg)
 	return l.queueMessageAsync(message)
 }
 
+// withMessageAttrs returns the attributes of an event, with the attributes of
+// the message, such as the fields of structured log lines, added to them.
+// The attributes of the message do not replace the attributes of the event.
+func withMessageAttrs(attrs map[string]string, msg *logger.Message) map[string]string {
+	if len(msg.Attrs) == 0 {
+		return attrs
+	}
+	merged := make(map[string]string, len(attrs)+len(msg.Attrs))
+	for k, v := range attrs {
+		merged[k] = v
+	}
+	for _, attr := range msg.Attrs {
+		if _, ok := merged[attr.Key]; !ok {
+			merged[attr.Key] = attr.Value
+		}
+	}
+	return merged
+}
+
 func (l *splunkLoggerRaw) Log(msg *logger.Message) error {
 	// empty or whitespace-only messages are not accepted by HEC
 	if strings.TrimSpace(string(msg.Line)) == "" {
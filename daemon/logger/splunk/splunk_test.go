@@ -10,8 +10,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/docker/docker/api/types/backend"
 	"github.com/docker/docker/daemon/logger"
 	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
 	"gotest.tools/env"
 )
 
@@ -1387,3 +1389,12 @@ func TestDeadlockOnBlockedEndpoint(t *testing.T) {
 	case <-done:
 	}
 }
+
+func TestWithMessageAttrs(t *testing.T) {
+	attrs := map[string]string{"rack": "101"}
+	msg := &logger.Message{Attrs: []backend.LogAttr{{Key: "rack", Value: "666"}, {Key: "level", Value: "info"}}}
+
+	merged := withMessageAttrs(attrs, msg)
+	assert.Check(t, is.DeepEqual(merged, map[string]string{"rack": "101", "level": "info"}))
+	assert.Check(t, is.DeepEqual(attrs, map[string]string{"rack": "101"}))
+}
@@ -0,0 +1,295 @@
+package logger // import "github.com/docker/docker/daemon/logger"
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/backend"
+	"github.com/pkg/errors"
+)
+
+// Log options to parse structured log lines. These options are supported by
+// all log drivers.
+const (
+	// LogFormatOpt is the format of the log lines, "json" or "logfmt".
+	LogFormatOpt = "log-format"
+	// LogFormatFieldsOpt is a comma-separated list of the fields to add to
+	// the attributes of messages. All fields are added if it's not set.
+	LogFormatFieldsOpt = "log-format-fields"
+	// LogFormatLevelKeyOpt is the field holding the level of messages.
+	LogFormatLevelKeyOpt = "log-format-level-key"
+	// LogFormatTimeKeyOpt is the field holding the time of messages. The
+	// time is added to the attributes of messages, the timestamp of
+	// messages is always the time they were received by the daemon.
+	LogFormatTimeKeyOpt = "log-format-time-key"
+)
+
+const (
+	logFormatJSON   = "json"
+	logFormatLogfmt = "logfmt"
+
+	defaultLevelKey = "level"
+	defaultTimeKey  = "time"
+)
+
+// LevelAttr is the attribute holding the level parsed from structured log
+// lines, for drivers which have a notion of level.
+const LevelAttr = "level"
+
+// TimeAttr is the attribute holding the time parsed from structured log
+// lines, formatted as an RFC 3339 timestamp.
+const TimeAttr = "time"
+
+type structuredConfig struct {
+	parse    func([]byte) ([]backend.LogAttr, bool)
+	fields   map[string]bool
+	levelKey string
+	timeKey  string
+}
+
+func parseStructuredConfig(cfg map[string]string) (*structuredConfig, error) {
+	c := &structuredConfig{
+		levelKey: defaultLevelKey,
+		timeKey:  defaultTimeKey,
+	}
+	switch cfg[LogFormatOpt] {
+	case "":
+		for _, k := range []string{LogFormatFieldsOpt, LogFormatLevelKeyOpt, LogFormatTimeKeyOpt} {
+			if _, ok := cfg[k]; ok {
+				return nil, errors.Errorf("logger: %s option is only supported with the %s option", k, LogFormatOpt)
+			}
+		}
+		return nil, nil
+	case logFormatJSON:
+		c.parse = parseJSONLine
+	case logFormatLogfmt:
+		c.parse = parseLogfmtLine
+	default:
+		return nil, errors.Errorf("logger: unsupported %s: %s", LogFormatOpt, cfg[LogFormatOpt])
+	}
+
+	if s := cfg[LogFormatFieldsOpt]; s != "" {
+		c.fields = make(map[string]bool)
+		for _, f := range strings.Split(s, ",") {
+			if f = strings.TrimSpace(f); f != "" {
+				c.fields[f] = true
+			}
+		}
+	}
+	if k, ok := cfg[LogFormatLevelKeyOpt]; ok {
+		c.levelKey = k
+	}
+	if k, ok := cfg[LogFormatTimeKeyOpt]; ok {
+		c.timeKey = k
+	}
+	return c, nil
+}
+
+// structuredLogger parses log lines and adds their fields to the attributes
+// of messages before passing them to a driver.
+type structuredLogger struct {
+	driver Logger
+	config *structuredConfig
+}
+
+type structuredWithReader struct {
+	*structuredLogger
+}
+
+func (s *structuredWithReader) ReadLogs(cfg ReadConfig) *LogWatcher {
+	reader, ok := s.driver.(LogReader)
+	if !ok {
+		// something is wrong if we get here
+		panic("expected log reader")
+	}
+	return reader.ReadLogs(cfg)
+}
+
+// NewStructuredLogger wraps the driver with a logger which parses log lines
+// as configured by the log-format options of cfg. It returns the driver as is
+// if cfg has no log-format option.
+func NewStructuredLogger(driver Logger, cfg map[string]string) (Logger, error) {
+	c, err := parseStructuredConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return driver, nil
+	}
+	l := &structuredLogger{driver: driver, config: c}
+	if _, ok := driver.(LogReader); ok {
+		return &structuredWithReader{l}, nil
+	}
+	return l, nil
+}
+
+// Log parses the message and passes it to the driver. Lines which cannot be
+// parsed, and partial messages, are passed unchanged.
+func (s *structuredLogger) Log(msg *Message) error {
+	if msg.PLogMetaData == nil {
+		if attrs, ok := s.config.parse(msg.Line); ok {
+			s.addAttrs(msg, attrs)
+		}
+	}
+	return s.driver.Log(msg)
+}
+
+func (s *structuredLogger) addAttrs(msg *Message, attrs []backend.LogAttr) {
+	for _, attr := range attrs {
+		switch {
+		case attr.Key == s.config.timeKey:
+			// the timestamp of the message is not replaced, as readers
+			// rely on timestamps being monotonic in the log.
+			if ts, err := parseLogTime(attr.Value); err == nil {
+				msg.Attrs = append(msg.Attrs, backend.LogAttr{Key: TimeAttr, Value: ts.Format(time.RFC3339Nano)})
+			}
+		case attr.Key == s.config.levelKey:
+			msg.Attrs = append(msg.Attrs, backend.LogAttr{Key: LevelAttr, Value: attr.Value})
+		case s.config.fields == nil || s.config.fields[attr.Key]:
+			msg.Attrs = append(msg.Attrs, attr)
+		}
+	}
+}
+
+func (s *structuredLogger) Name() string {
+	return s.driver.Name()
+}
+
+func (s *structuredLogger) Close() error {
+	return s.driver.Close()
+}
+
+// parseLogTime parses an RFC 3339 timestamp, or a number of seconds,
+// milliseconds, microseconds or nanoseconds since the epoch. The unit of
+// numbers is guessed from their magnitude.
+func parseLogTime(s string) (time.Time, error) {
+	if ts, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return ts.UTC(), nil
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(n) || math.IsInf(n, 0) {
+		return time.Time{}, fmt.Errorf("invalid timestamp: %s", s)
+	}
+	var unit time.Duration
+	switch abs := math.Abs(n); {
+	case abs >= 1e17:
+		unit = time.Nanosecond
+	case abs >= 1e14:
+		unit = time.Microsecond
+	case abs >= 1e11:
+		unit = time.Millisecond
+	default:
+		unit = time.Second
+	}
+	return time.Unix(0, int64(n*float64(unit))).UTC(), nil
+}
+
+// parseJSONLine parses a line holding a JSON object. Nested values are kept
+// as JSON.
+func parseJSONLine(line []byte) ([]backend.LogAttr, bool) {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 || line[0] != '{' {
+		return nil, false
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(line, &fields); err != nil {
+		return nil, false
+	}
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	attrs := make([]backend.LogAttr, 0, len(fields))
+	for _, k := range keys {
+		raw := fields[k]
+		var value string
+		switch raw[0] {
+		case '"':
+			if err := json.Unmarshal(raw, &value); err != nil {
+				return nil, false
+			}
+		default:
+			value = string(raw)
+		}
+		attrs = append(attrs, backend.LogAttr{Key: k, Value: value})
+	}
+	return attrs, true
+}
+
+// parseLogfmtLine parses a line of key=value pairs. Values may be quoted, and
+// keys without a value are set to "true". Lines without any key=value pair
+// are not considered as logfmt.
+func parseLogfmtLine(line []byte) ([]backend.LogAttr, bool) {
+	s := string(bytes.TrimSpace(line))
+	if s == "" {
+		return nil, false
+	}
+
+	var attrs []backend.LogAttr
+	var pairs int
+	for s != "" {
+		i := strings.IndexAny(s, "= ")
+		if i == 0 {
+			return nil, false
+		}
+		if i < 0 || s[i] == ' ' {
+			key := s
+			if i >= 0 {
+				key, s = s[:i], strings.TrimLeft(s[i:], " ")
+			} else {
+				s = ""
+			}
+			attrs = append(attrs, backend.LogAttr{Key: key, Value: "true"})
+			continue
+		}
+
+		key := s[:i]
+		s = s[i+1:]
+		var value string
+		if strings.HasPrefix(s, `"`) {
+			end := closingQuote(s)
+			if end < 0 {
+				return nil, false
+			}
+			v, err := strconv.Unquote(s[:end+1])
+			if err != nil {
+				return nil, false
+			}
+			value, s = v, s[end+1:]
+			if s != "" && s[0] != ' ' {
+				return nil, false
+			}
+		} else if j := strings.IndexByte(s, ' '); j >= 0 {
+			value, s = s[:j], s[j:]
+		} else {
+			value, s = s, ""
+		}
+		attrs = append(attrs, backend.LogAttr{Key: key, Value: value})
+		pairs++
+		s = strings.TrimLeft(s, " ")
+	}
+	return attrs, pairs > 0
+}
+
+// closingQuote returns the index of the quote closing the quoted string at
+// the start of s, or -1.
+func closingQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,114 @@
+package logger // import "github.com/docker/docker/daemon/logger"
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/backend"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+type attrsLogger struct {
+	msgs []*Message
+}
+
+func (l *attrsLogger) Log(m *Message) error {
+	l.msgs = append(l.msgs, m)
+	return nil
+}
+
+func (l *attrsLogger) Name() string { return "attrs" }
+func (l *attrsLogger) Close() error { return nil }
+
+func TestStructuredLoggerJSON(t *testing.T) {
+	driver := &attrsLogger{}
+	l, err := NewStructuredLogger(driver, map[string]string{
+		LogFormatOpt:       "json",
+		LogFormatFieldsOpt: "user,status",
+	})
+	assert.NilError(t, err)
+
+	now := time.Now().UTC()
+	lines := []string{
+		`{"time":"2019-01-02T03:04:05.5Z","level":"warn","user":"alice","status":404,"path":"/"}`,
+		`not json`,
+		`{"user":{"name":"bob"}}`,
+	}
+	for _, line := range lines {
+		assert.NilError(t, l.Log(&Message{Line: []byte(line), Timestamp: now}))
+	}
+
+	assert.Assert(t, is.Len(driver.msgs, 3))
+	assert.Check(t, is.Equal(driver.msgs[0].Timestamp, now))
+	assert.Check(t, is.DeepEqual(driver.msgs[0].Attrs, []backend.LogAttr{
+		{Key: LevelAttr, Value: "warn"},
+		{Key: "status", Value: "404"},
+		{Key: TimeAttr, Value: "2019-01-02T03:04:05.5Z"},
+		{Key: "user", Value: "alice"},
+	}))
+	assert.Check(t, is.Equal(driver.msgs[1].Timestamp, now))
+	assert.Check(t, is.Len(driver.msgs[1].Attrs, 0))
+	assert.Check(t, is.DeepEqual(driver.msgs[2].Attrs, []backend.LogAttr{{Key: "user", Value: `{"name":"bob"}`}}))
+}
+
+func TestStructuredLoggerLogfmt(t *testing.T) {
+	driver := &attrsLogger{}
+	l, err := NewStructuredLogger(driver, map[string]string{
+		LogFormatOpt:         "logfmt",
+		LogFormatLevelKeyOpt: "lvl",
+		LogFormatTimeKeyOpt:  "ts",
+	})
+	assert.NilError(t, err)
+
+	lines := []string{
+		`ts=1546398245 lvl=info msg="hello \"world\"" cached`,
+		`hello world`,
+		`msg="unterminated`,
+	}
+	for _, line := range lines {
+		assert.NilError(t, l.Log(&Message{Line: []byte(line)}))
+	}
+
+	assert.Assert(t, is.Len(driver.msgs, 3))
+	assert.Check(t, driver.msgs[0].Timestamp.IsZero())
+	assert.Check(t, is.DeepEqual(driver.msgs[0].Attrs, []backend.LogAttr{
+		{Key: TimeAttr, Value: "2019-01-02T03:04:05Z"},
+		{Key: LevelAttr, Value: "info"},
+		{Key: "msg", Value: `hello "world"`},
+		{Key: "cached", Value: "true"},
+	}))
+	assert.Check(t, is.Len(driver.msgs[1].Attrs, 0))
+	assert.Check(t, is.Len(driver.msgs[2].Attrs, 0))
+}
+
+func TestStructuredLoggerConfig(t *testing.T) {
+	driver := &attrsLogger{}
+	l, err := NewStructuredLogger(driver, map[string]string{})
+	assert.NilError(t, err)
+	assert.Check(t, l == Logger(driver))
+
+	_, err = NewStructuredLogger(driver, map[string]string{LogFormatOpt: "xml"})
+	assert.Check(t, is.ErrorContains(err, "unsupported log-format"))
+	_, err = NewStructuredLogger(driver, map[string]string{LogFormatFieldsOpt: "user"})
+	assert.Check(t, is.ErrorContains(err, "only supported with the log-format option"))
+}
+
+func TestParseLogTime(t *testing.T) {
+	expected := time.Date(2019, 1, 2, 3, 4, 5, 5e8, time.UTC)
+	for _, s := range []string{
+		"2019-01-02T03:04:05.5Z",
+		"2019-01-02T04:04:05.5+01:00",
+		"1546398245.5",
+		"1546398245500",
+		"1546398245500000",
+		"1546398245500000000",
+	} {
+		ts, err := parseLogTime(s)
+		assert.Check(t, err, s)
+		assert.Check(t, is.Equal(ts, expected), s)
+	}
+
+	_, err := parseLogTime("yesterday")
+	assert.Check(t, is.ErrorContains(err, "invalid timestamp"))
+}
@@ -88,11 +88,17 @@ func (daemon *Daemon) ContainerLogs(ctx context.Context, containerName string, c
 		until = time.Unix(s, n)
 	}
 
+	filter, err := logger.NewLogFilter(config.Grep, config.ShowStdout, config.ShowStderr, config.Filters)
+	if err != nil {
+		return nil, false, errdefs.InvalidParameter(err)
+	}
+
 	readConfig := logger.ReadConfig{
 		Since:  since,
 		Until:  until,
 		Tail:   tailLines,
 		Follow: follow,
+		Filter: filter,
 	}
 
 	logs := logReader.ReadLogs(readConfig)
@@ -142,6 +148,12 @@ func (daemon *Daemon) ContainerLogs(ctx context.Context, containerName string, c
 				if !ok {
 					return
 				}
+				// not all log readers support filtering messages, so the
+				// filter is applied here as well. readers which do support
+				// it only return matching messages.
+				if !filter.Match(msg) {
+					continue
+				}
 				m := msg.AsLogMessage() // just a pointer conversion, does not copy data
 
 				// there could be a case where the reader stops accepting
@@ -64,7 +64,13 @@ func (daemon *Daemon) ProcessEvent(id string, e libcontainerd.EventType, ei libc
 				ExitedAt:  ei.ExitedAt,
 				OOMKilled: ei.OOMKilled,
 			}
-			restart, wait, err := c.RestartManager().ShouldRestart(ei.ExitCode, daemon.IsShuttingDown() || c.HasBeenManuallyStopped, time.Since(c.StartedAt))
+			exitCode := ei.ExitCode
+			if exitCode == 0 && c.State.Health != nil && c.State.Health.UnhealthyRestart() {
+				// a container stopped because it was unhealthy has failed, even
+				// if it exited cleanly, so that the on-failure policy applies.
+				exitCode = 1
+			}
+			restart, wait, err := c.RestartManager().ShouldRestart(exitCode, daemon.IsShuttingDown() || c.HasBeenManuallyStopped, time.Since(c.StartedAt))
 			if err == nil && restart {
 				c.RestartCount++
 				c.SetRestarting(&exitStatus)
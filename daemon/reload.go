@@ -44,6 +44,8 @@ func (daemon *Daemon) Reload(conf *config.Config) (err error) {
 	}
 	daemon.reloadDebug(conf, attributes)
 	daemon.reloadMaxConcurrentDownloadsAndUploads(conf, attributes)
+	daemon.reloadDownloadRetryPolicy(conf, attributes)
+	daemon.reloadImageGC(conf, attributes)
 	daemon.reloadShutdownTimeout(conf, attributes)
 	daemon.reloadFeatures(conf, attributes)
 
@@ -110,6 +112,49 @@ func (daemon *Daemon) reloadMaxConcurrentDownloadsAndUploads(conf *config.Config
 	attributes["max-concurrent-uploads"] = fmt.Sprintf("%d", *daemon.configStore.MaxConcurrentUploads)
 }
 
+// reloadDownloadRetryPolicy updates configuration with the retry options of
+// layer downloads and updates the passed attributes
+func (daemon *Daemon) reloadDownloadRetryPolicy(conf *config.Config, attributes map[string]string) {
+	// Unset values are reset to their default, as for max-concurrent-downloads.
+	maxDownloadAttempts := config.DefaultMaxDownloadAttempts
+	if conf.IsValueSet("max-download-attempts") && conf.MaxDownloadAttempts != nil {
+		maxDownloadAttempts = *conf.MaxDownloadAttempts
+	}
+	downloadRetryDelay := config.DefaultDownloadRetryDelay
+	if conf.IsValueSet("download-retry-delay") && conf.DownloadRetryDelay != nil {
+		downloadRetryDelay = *conf.DownloadRetryDelay
+	}
+	downloadRetryMaxDelay := 0
+	if conf.IsValueSet("download-retry-max-delay") && conf.DownloadRetryMaxDelay != nil {
+		downloadRetryMaxDelay = *conf.DownloadRetryMaxDelay
+	}
+	daemon.configStore.MaxDownloadAttempts = &maxDownloadAttempts
+	daemon.configStore.DownloadRetryDelay = &downloadRetryDelay
+	daemon.configStore.DownloadRetryMaxDelay = &downloadRetryMaxDelay
+	logrus.Debugf("Reset Download Retry Policy: %+v", downloadRetryPolicy(daemon.configStore))
+
+	if daemon.imageService != nil {
+		daemon.imageService.UpdateDownloadRetryPolicy(downloadRetryPolicy(daemon.configStore))
+	}
+
+	// prepare reload event attributes with updatable configurations
+	attributes["max-download-attempts"] = fmt.Sprintf("%d", maxDownloadAttempts)
+	attributes["download-retry-delay"] = fmt.Sprintf("%d", downloadRetryDelay)
+	attributes["download-retry-max-delay"] = fmt.Sprintf("%d", downloadRetryMaxDelay)
+}
+
+// reloadImageGC updates the configuration of the garbage collection of
+// unused images and updates the passed attributes
+func (daemon *Daemon) reloadImageGC(conf *config.Config, attributes map[string]string) {
+	daemon.configStore.ImageGC = conf.ImageGC
+	if daemon.imageService != nil {
+		daemon.imageService.SetGCPolicy(daemon.imageGCPolicy(daemon.configStore))
+	}
+
+	// prepare reload event attributes with updatable configurations
+	attributes["image-gc"] = fmt.Sprintf("%t", daemon.configStore.ImageGC.Enabled)
+}
+
 // reloadShutdownTimeout updates configuration with daemon shutdown timeout option
 // and updates the passed attributes
 func (daemon *Daemon) reloadShutdownTimeout(conf *config.Config, attributes map[string]string) {
@@ -281,6 +326,13 @@ func (daemon *Daemon) reloadRegistryMirrors(conf *config.Config, attributes map[
 		}
 	}
 
+	if conf.IsValueSet("registry-host-mirrors") {
+		daemon.configStore.RegistryMirrors = conf.RegistryMirrors
+		if err := daemon.RegistryService.LoadRegistryMirrors(conf.RegistryMirrors); err != nil {
+			return err
+		}
+	}
+
 	// prepare reload event attributes with updatable configurations
 	if daemon.configStore.Mirrors != nil {
 		mirrors, err := json.Marshal(daemon.configStore.Mirrors)
@@ -292,6 +344,16 @@ func (daemon *Daemon) reloadRegistryMirrors(conf *config.Config, attributes map[
 		attributes["registry-mirrors"] = "[]"
 	}
 
+	if daemon.configStore.RegistryMirrors != nil {
+		registryMirrors, err := json.Marshal(daemon.configStore.RegistryMirrors)
+		if err != nil {
+			return err
+		}
+		attributes["registry-host-mirrors"] = string(registryMirrors)
+	} else {
+		attributes["registry-host-mirrors"] = "{}"
+	}
+
 	return nil
 }
 
@@ -117,6 +117,10 @@ func (daemon *Daemon) containerStart(container *container.Container, checkpoint
 		return errdefs.Forbidden(errors.New("custom checkpointdir is not supported"))
 	}
 
+	if err := daemon.checkContainerEmulation(container); err != nil {
+		return err
+	}
+
 	// if we encounter an error during start we need to ensure that any other
 	// setup has been cleaned up properly
 	defer func() {
@@ -121,7 +121,7 @@ func (daemon *Daemon) registerMountPoints(container *container.Container, hostCo
 				if err != nil {
 					return err
 				}
-				cp.Volume = &volumeWrapper{v: v, s: daemon.volumes}
+				cp.Volume = &volumeWrapper{v: v, s: daemon.volumes, container: container.ID}
 			}
 			dereferenceIfExists(cp.Destination)
 			mountPoints[cp.Destination] = cp
@@ -154,7 +154,7 @@ func (daemon *Daemon) registerMountPoints(container *container.Container, hostCo
 			if err != nil {
 				return err
 			}
-			bind.Volume = &volumeWrapper{v: v, s: daemon.volumes}
+			bind.Volume = &volumeWrapper{v: v, s: daemon.volumes, container: container.ID}
 			bind.Source = v.Mountpoint
 			// bind.Name is an already existing volume, we need to use that here
 			bind.Driver = v.Driver
@@ -206,7 +206,7 @@ func (daemon *Daemon) registerMountPoints(container *container.Container, hostCo
 				return err
 			}
 
-			mp.Volume = &volumeWrapper{v: v, s: daemon.volumes}
+			mp.Volume = &volumeWrapper{v: v, s: daemon.volumes, container: container.ID}
 			mp.Name = v.Name
 			mp.Driver = v.Driver
 
@@ -251,7 +251,7 @@ func (daemon *Daemon) lazyInitializeVolume(containerID string, m *volumemounts.M
 		if err != nil {
 			return err
 		}
-		m.Volume = &volumeWrapper{v: v, s: daemon.volumes}
+		m.Volume = &volumeWrapper{v: v, s: daemon.volumes, container: containerID}
 	}
 	return nil
 }
@@ -385,13 +385,15 @@ func (daemon *Daemon) VolumesService() *service.VolumesService {
 }
 
 type volumeMounter interface {
-	Mount(ctx context.Context, v *types.Volume, ref string) (string, error)
-	Unmount(ctx context.Context, v *types.Volume, ref string) error
+	Mount(ctx context.Context, v *types.Volume, ref string, opts ...volumeopts.MountOption) (string, error)
+	Unmount(ctx context.Context, v *types.Volume, ref string, opts ...volumeopts.MountOption) error
 }
 
 type volumeWrapper struct {
 	v *types.Volume
 	s volumeMounter
+	// container is the ID of the container using the volume
+	container string
 }
 
 func (v *volumeWrapper) Name() string {
@@ -407,11 +409,11 @@ func (v *volumeWrapper) Path() string {
 }
 
 func (v *volumeWrapper) Mount(ref string) (string, error) {
-	return v.s.Mount(context.TODO(), v.v, ref)
+	return v.s.Mount(context.TODO(), v.v, ref, volumeopts.WithMountContainer(v.container))
 }
 
 func (v *volumeWrapper) Unmount(ref string) error {
-	return v.s.Unmount(context.TODO(), v.v, ref)
+	return v.s.Unmount(context.TODO(), v.v, ref, volumeopts.WithMountContainer(v.container))
 }
 
 func (v *volumeWrapper) CreatedAt() (time.Time, error) {
@@ -48,7 +48,7 @@ func (daemon *Daemon) setupMounts(c *container.Container) ([]container.Mount, er
 			return nil
 		}
 
-		path, err := m.Setup(c.MountLabel, daemon.idMapping.RootPair(), checkfunc)
+		path, err := m.Setup(c.MountLabel, daemon.idMapping.RootPair(), getSubpathStagingDir(daemon.configStore), checkfunc)
 		if err != nil {
 			return nil, err
 		}
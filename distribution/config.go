@@ -9,10 +9,12 @@ import (
 
 	"github.com/docker/distribution"
 	"github.com/docker/distribution/manifest/schema2"
+	"github.com/docker/distribution/reference"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/distribution/metadata"
 	"github.com/docker/docker/distribution/xfer"
 	"github.com/docker/docker/image"
+	"github.com/docker/docker/image/signature"
 	"github.com/docker/docker/layer"
 	"github.com/docker/docker/pkg/progress"
 	"github.com/docker/docker/pkg/system"
@@ -62,6 +64,12 @@ type ImagePullConfig struct {
 	Schema2Types []string
 	// Platform is the requested platform of the image being pulled
 	Platform *specs.Platform
+	// SignaturePolicy is the policy images are verified against. Images
+	// are not verified if it is nil.
+	SignaturePolicy *signature.Policy
+	// SignatureStore stores the signatures of the images verified by the
+	// signature policy.
+	SignatureStore *signature.Store
 }
 
 // ImagePushConfig stores push configuration.
@@ -78,6 +86,22 @@ type ImagePushConfig struct {
 	TrustKey libtrust.PrivateKey
 	// UploadManager dispatches uploads.
 	UploadManager *xfer.LayerUploadManager
+	// MountCandidates holds repositories, in the registry the image is
+	// pushed to, which layers are mounted from before any repository
+	// known from the metadata of the layers.
+	MountCandidates []reference.Named
+	// PushStatsRecorder, if set, is called with the statistics of the
+	// layers of each image pushed.
+	PushStatsRecorder func(stats types.PushStats)
+}
+
+// ManifestListEntry is an image pushed as part of a manifest list.
+type ManifestListEntry struct {
+	// ID is the ID of the image.
+	ID digest.Digest
+	// Platform overrides the platform of the image in the manifest list.
+	// Only its fields which are set override the ones of the image.
+	Platform *specs.Platform
 }
 
 // ImageConfigStore handles storing and getting image configurations
@@ -170,7 +194,7 @@ func (s *imageConfigStore) PlatformFromConfig(c []byte) (*specs.Platform, error)
 	if !system.IsOSSupported(os) {
 		return nil, system.ErrNotSupportedOperatingSystem
 	}
-	return &specs.Platform{OS: os, Architecture: unmarshalledConfig.Architecture, OSVersion: unmarshalledConfig.OSVersion}, nil
+	return &specs.Platform{OS: os, Architecture: unmarshalledConfig.Architecture, Variant: unmarshalledConfig.Variant, OSVersion: unmarshalledConfig.OSVersion}, nil
 }
 
 type storeLayerProvider struct {
@@ -133,6 +133,10 @@ func continueOnError(err error, mirrorEndpoint bool) bool {
 		// Failures from a mirror endpoint should result in fallback to the
 		// canonical repo.
 		return mirrorEndpoint
+	case signatureVerificationError:
+		// Mirrors may not hold the signatures of the images they hold:
+		// fall back to the canonical repo for those.
+		return mirrorEndpoint
 	case error:
 		return !strings.Contains(err.Error(), strings.ToLower(syscall.ESRCH.Error()))
 	}
@@ -198,6 +202,17 @@ func (invalidManifestFormatError) Error() string {
 
 func (invalidManifestFormatError) InvalidParameter() {}
 
+type signatureVerificationError struct {
+	ref reference.Named
+	err error
+}
+
+func (e signatureVerificationError) Error() string {
+	return fmt.Sprintf("image %s rejected by signature policy: %v", reference.FamiliarString(e.ref), e.err)
+}
+
+func (signatureVerificationError) Forbidden() {}
+
 type reservedNameError string
 
 func (e reservedNameError) Error() string {
@@ -95,6 +95,11 @@ func Pull(ctx context.Context, ref reference.Named, imagePullConfig *ImagePullCo
 			continue
 		}
 
+		if endpoint.Version == registry.APIVersion1 && imagePullConfig.SignaturePolicy.Requirement(repoInfo.Name) != nil {
+			logrus.Debugf("Skipping v1 endpoint %s because the signature policy requires verification", endpoint.URL)
+			continue
+		}
+
 		if confirmedV2 && endpoint.Version == registry.APIVersion1 {
 			logrus.Debugf("Skipping v1 endpoint %s because v2 registry was detected", endpoint.URL)
 			continue
@@ -232,7 +232,15 @@ func (ld *v2LayerDescriptor) Download(ctx context.Context, progressOutput progre
 		}
 	}
 
-	reader := progress.NewProgressReader(ioutils.NewCancelReadCloser(ctx, layerDownload), progressOutput, size-offset, ld.ID(), "Downloading")
+	blob := &resumableBlobReader{
+		ctx:    ctx,
+		digest: ld.digest,
+		open:   ld.open,
+		rsc:    layerDownload,
+		offset: offset,
+		size:   ld.src.Size,
+	}
+	reader := progress.NewProgressReader(ioutils.NewCancelReadCloser(ctx, blob), progressOutput, size-offset, ld.ID(), "Downloading")
 	defer reader.Close()
 
 	if ld.verifier == nil {
@@ -297,6 +305,71 @@ func (ld *v2LayerDescriptor) Download(ctx context.Context, progressOutput progre
 	}), size, nil
 }
 
+// maxBlobResumes is the maximum number of times the download of a blob is
+// resumed after a read error, before the download attempt fails.
+const maxBlobResumes = 10
+
+// resumableBlobReader reads a blob from a registry. When reading fails, or
+// the blob is shorter than expected, it opens the blob again and resumes
+// reading from the current offset with a range request.
+type resumableBlobReader struct {
+	ctx     context.Context
+	digest  digest.Digest
+	open    func(context.Context) (distribution.ReadSeekCloser, error)
+	rsc     distribution.ReadSeekCloser
+	offset  int64
+	size    int64 // 0 if unknown
+	resumes int
+}
+
+func (r *resumableBlobReader) Read(p []byte) (int, error) {
+	for {
+		n, err := r.rsc.Read(p)
+		r.offset += int64(n)
+		if err == io.EOF && r.size > 0 && r.offset < r.size {
+			err = io.ErrUnexpectedEOF
+		}
+		if err == nil || err == io.EOF || !r.resumable(err) {
+			return n, err
+		}
+		if rerr := r.resume(); rerr != nil {
+			logrus.Debugf("failed to resume download of %s from %d bytes: %v", r.digest, r.offset, rerr)
+			return n, err
+		}
+		logrus.Debugf("resumed download of %s from %d bytes after error: %v", r.digest, r.offset, err)
+		if n > 0 {
+			return n, nil
+		}
+	}
+}
+
+func (r *resumableBlobReader) resumable(err error) bool {
+	if r.resumes >= maxBlobResumes || r.ctx.Err() != nil || err == transport.ErrWrongCodeForByteRange {
+		return false
+	}
+	_, isDNR := retryOnError(err).(xfer.DoNotRetry)
+	return !isDNR
+}
+
+func (r *resumableBlobReader) resume() error {
+	r.resumes++
+	r.rsc.Close()
+	rsc, err := r.open(r.ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := rsc.Seek(r.offset, os.SEEK_SET); err != nil {
+		rsc.Close()
+		return err
+	}
+	r.rsc = rsc
+	return nil
+}
+
+func (r *resumableBlobReader) Close() error {
+	return r.rsc.Close()
+}
+
 func (ld *v2LayerDescriptor) Close() {
 	if ld.tmpFile != nil {
 		ld.tmpFile.Close()
@@ -325,7 +398,11 @@ func (ld *v2LayerDescriptor) truncateDownloadFile() error {
 
 func (ld *v2LayerDescriptor) Registered(diffID layer.DiffID) {
 	// Cache mapping from this layer's DiffID to the blobsum
-	ld.V2MetadataService.Add(diffID, metadata.V2Metadata{Digest: ld.digest, SourceRepository: ld.repoInfo.Name.Name()})
+	meta := metadata.V2Metadata{Digest: ld.digest, SourceRepository: ld.repoInfo.Name.Name()}
+	if isZstdLayerType(ld.src.MediaType) {
+		meta.MediaType = ld.src.MediaType
+	}
+	ld.V2MetadataService.Add(diffID, meta)
 }
 
 func (p *v2Puller) pullV2Tag(ctx context.Context, ref reference.Named, platform *specs.Platform) (tagUpdated bool, err error) {
@@ -379,6 +456,16 @@ func (p *v2Puller) pullV2Tag(ctx context.Context, ref reference.Named, platform
 	// the other side speaks the v2 protocol.
 	p.confirmedV2 = true
 
+	if p.config.SignaturePolicy != nil {
+		signedDigest, err := manifestDigestOf(ref, manifest)
+		if err != nil {
+			return false, err
+		}
+		if err := p.verifySignatures(ctx, ref, signedDigest); err != nil {
+			return false, err
+		}
+	}
+
 	logrus.Debugf("Pulling ref from V2 registry: %s", reference.FamiliarString(ref))
 	progress.Message(p.config.ProgressOutput, tagOrDigest, "Pulling from "+reference.FamiliarName(p.repo.Named()))
 
@@ -413,6 +500,16 @@ func (p *v2Puller) pullV2Tag(ctx context.Context, ref reference.Named, platform
 	progress.Message(p.config.ProgressOutput, "", "Digest: "+manifestDigest.String())
 
 	if p.config.ReferenceStore != nil {
+		if _, ok := ref.(reference.Canonical); !ok {
+			imgPlatform, err := p.imagePlatform(id)
+			if err != nil {
+				return false, err
+			}
+			if isForeignPlatform(imgPlatform) {
+				return p.addPlatformReferences(ref, imgPlatform, manifestDigest, id)
+			}
+		}
+
 		oldTagID, err := p.config.ReferenceStore.Get(ref)
 		if err == nil {
 			if oldTagID == id {
@@ -438,6 +535,52 @@ func (p *v2Puller) pullV2Tag(ctx context.Context, ref reference.Named, platform
 	return true, nil
 }
 
+// imagePlatform returns the platform of the pulled image id.
+func (p *v2Puller) imagePlatform(id digest.Digest) (specs.Platform, error) {
+	config, err := p.config.ImageStore.Get(id)
+	if err != nil {
+		return specs.Platform{}, err
+	}
+	platform, err := p.config.ImageStore.PlatformFromConfig(config)
+	if err != nil {
+		return specs.Platform{}, err
+	}
+	return *platform, nil
+}
+
+// addPlatformReferences references the pulled image id of a foreign
+// platform by ref for that platform, side by side with the images of other
+// platforms. It returns whether the image ref refers to for platform was
+// updated.
+func (p *v2Puller) addPlatformReferences(ref reference.Named, platform specs.Platform, manifestDigest, id digest.Digest) (bool, error) {
+	store := p.config.ReferenceStore
+	if err := addDigestReference(store, ref, manifestDigest, id); err != nil {
+		return false, err
+	}
+	oldID, err := store.GetPlatform(ref, platform)
+	if err == nil && oldID == id {
+		return false, nil
+	} else if err != nil && err != refstore.ErrDoesNotExist {
+		return false, err
+	}
+	return true, store.AddPlatformReference(ref, platform, id)
+}
+
+// isForeignPlatform returns whether images of platform are of another
+// architecture than the host, and cannot run natively.
+func isForeignPlatform(platform specs.Platform) bool {
+	if platform.Architecture == "" || platform.OS != runtime.GOOS {
+		return false
+	}
+	host := platforms.Normalize(platforms.DefaultSpec())
+	normalized := platforms.Normalize(platform)
+	if normalized.Architecture != host.Architecture {
+		return true
+	}
+	// images which don't specify a variant run on any variant
+	return platform.Variant != "" && normalized.Variant != host.Variant
+}
+
 func (p *v2Puller) pullSchema1(ctx context.Context, ref reference.Reference, unverifiedManifest *schema1.SignedManifest, platform *specs.Platform) (id digest.Digest, manifestDigest digest.Digest, err error) {
 	var verifiedManifest *schema1.Manifest
 	verifiedManifest, err = verifySchema1Manifest(unverifiedManifest, ref)
@@ -566,6 +709,12 @@ func (p *v2Puller) pullSchema2(ctx context.Context, ref reference.Named, mfst *s
 	// Note that the order of this loop is in the direction of bottom-most
 	// to top-most, so that the downloads slice gets ordered correctly.
 	for _, d := range mfst.Layers {
+		if isUnsupportedLayerType(d.MediaType) {
+			return "", "", fmt.Errorf("unsupported layer media type %s", d.MediaType)
+		}
+		if err := checkLayerDecompression(d.MediaType); err != nil {
+			return "", "", err
+		}
 		layerDescriptor := &v2LayerDescriptor{
 			digest:            d.Digest,
 			repo:              p.repo,
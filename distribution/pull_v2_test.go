@@ -1,8 +1,11 @@
 package distribution // import "github.com/docker/docker/distribution"
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"reflect"
 	"regexp"
@@ -10,6 +13,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/docker/distribution"
 	"github.com/docker/distribution/manifest/schema1"
 	"github.com/docker/distribution/reference"
 	"github.com/opencontainers/go-digest"
@@ -205,3 +209,83 @@ func TestFormatPlatform(t *testing.T) {
 		}
 	}
 }
+
+func TestIsForeignPlatform(t *testing.T) {
+	foreignArch := "arm64"
+	if runtime.GOARCH == foreignArch {
+		foreignArch = "amd64"
+	}
+	assert.Check(t, !isForeignPlatform(specs.Platform{OS: runtime.GOOS}))
+	assert.Check(t, !isForeignPlatform(specs.Platform{OS: runtime.GOOS, Architecture: runtime.GOARCH}))
+	assert.Check(t, isForeignPlatform(specs.Platform{OS: runtime.GOOS, Architecture: foreignArch}))
+	assert.Check(t, !isForeignPlatform(specs.Platform{OS: "foo", Architecture: foreignArch}))
+}
+
+// flakyBlob is a blob opened by a resumableBlobReader, which fails after
+// reading a number of bytes.
+type flakyBlob struct {
+	*strings.Reader
+	failAfter int64
+	read      int64
+}
+
+func (b *flakyBlob) Read(p []byte) (int, error) {
+	if b.read >= b.failAfter {
+		return 0, errors.New("connection reset by peer")
+	}
+	if remaining := b.failAfter - b.read; int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+	n, err := b.Reader.Read(p)
+	b.read += int64(n)
+	return n, err
+}
+
+func (b *flakyBlob) Close() error {
+	return nil
+}
+
+func TestResumableBlobReader(t *testing.T) {
+	const content = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+	var opened int
+	open := func(context.Context) (distribution.ReadSeekCloser, error) {
+		opened++
+		return &flakyBlob{Reader: strings.NewReader(content), failAfter: 10}, nil
+	}
+	rsc, err := open(context.Background())
+	assert.NilError(t, err)
+
+	r := &resumableBlobReader{
+		ctx:  context.Background(),
+		open: open,
+		rsc:  rsc,
+		size: int64(len(content)),
+	}
+	b, err := ioutil.ReadAll(r)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(b), content))
+	assert.Check(t, is.Equal(opened, 4))
+}
+
+func TestResumableBlobReaderShortBlob(t *testing.T) {
+	const content = "0123456789"
+
+	var opened int
+	open := func(context.Context) (distribution.ReadSeekCloser, error) {
+		opened++
+		return &flakyBlob{Reader: strings.NewReader(content), failAfter: 100}, nil
+	}
+	rsc, err := open(context.Background())
+	assert.NilError(t, err)
+
+	r := &resumableBlobReader{
+		ctx:  context.Background(),
+		open: open,
+		rsc:  rsc,
+		size: 20,
+	}
+	_, err = ioutil.ReadAll(r)
+	assert.Check(t, is.Error(err, io.ErrUnexpectedEOF.Error()))
+	assert.Check(t, is.Equal(opened, maxBlobResumes+1))
+}
@@ -4,13 +4,16 @@ import (
 	"bufio"
 	"compress/gzip"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 
+	"github.com/docker/distribution/manifest/manifestlist"
 	"github.com/docker/distribution/reference"
 	"github.com/docker/docker/distribution/metadata"
 	"github.com/docker/docker/pkg/progress"
 	"github.com/docker/docker/registry"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/sirupsen/logrus"
 )
 
@@ -76,6 +79,56 @@ func Push(ctx context.Context, ref reference.Named, imagePushConfig *ImagePushCo
 		return fmt.Errorf("An image does not exist locally with the tag: %s", reference.FamiliarName(repoInfo.Name))
 	}
 
+	return push(ctx, ref, repoInfo, endpoints, imagePushConfig, func(endpoint registry.APIEndpoint) (Pusher, error) {
+		return NewPusher(ref, endpoint, repoInfo, imagePushConfig)
+	})
+}
+
+// PushManifestList pushes the images of entries to the repository of ref,
+// and then a manifest list of these images tagged as ref. mediaType is the
+// media type of the manifest list, manifestlist.MediaTypeManifestList or
+// ocispec.MediaTypeImageIndex.
+func PushManifestList(ctx context.Context, ref reference.NamedTagged, entries []ManifestListEntry, mediaType string, imagePushConfig *ImagePushConfig) error {
+	if len(entries) == 0 {
+		return errors.New("no image to push in the manifest list")
+	}
+	switch mediaType {
+	case manifestlist.MediaTypeManifestList, ocispec.MediaTypeImageIndex:
+	default:
+		return fmt.Errorf("unsupported manifest list media type: %s", mediaType)
+	}
+
+	repoInfo, err := imagePushConfig.RegistryService.ResolveRepository(ref)
+	if err != nil {
+		return err
+	}
+
+	endpoints, err := imagePushConfig.RegistryService.LookupPushEndpoints(reference.Domain(repoInfo.Name))
+	if err != nil {
+		return err
+	}
+
+	progress.Messagef(imagePushConfig.ProgressOutput, "", "The push refers to repository [%s]", repoInfo.Name.Name())
+
+	return push(ctx, ref, repoInfo, endpoints, imagePushConfig, func(endpoint registry.APIEndpoint) (Pusher, error) {
+		if endpoint.Version != registry.APIVersion2 {
+			return nil, fmt.Errorf("manifest lists cannot be pushed to registry %s: v2 API required", endpoint.URL)
+		}
+		return &v2Pusher{
+			v2MetadataService:     metadata.NewV2MetadataService(imagePushConfig.MetadataStore),
+			ref:                   ref,
+			endpoint:              endpoint,
+			repoInfo:              repoInfo,
+			config:                imagePushConfig,
+			manifestList:          entries,
+			manifestListMediaType: mediaType,
+		}, nil
+	})
+}
+
+// push tries the pushers created by newPusher for endpoints in turn, until
+// one of them succeeds or fails without allowing a fallback.
+func push(ctx context.Context, ref reference.Named, repoInfo *registry.RepositoryInfo, endpoints []registry.APIEndpoint, imagePushConfig *ImagePushConfig, newPusher func(registry.APIEndpoint) (Pusher, error)) error {
 	var (
 		lastErr error
 
@@ -108,7 +161,7 @@ func Push(ctx context.Context, ref reference.Named, imagePushConfig *ImagePushCo
 
 		logrus.Debugf("Trying to push %s to %s %s", repoInfo.Name.Name(), endpoint.URL, endpoint.Version)
 
-		pusher, err := NewPusher(ref, endpoint, repoInfo, imagePushConfig)
+		pusher, err := newPusher(endpoint)
 		if err != nil {
 			lastErr = err
 			continue
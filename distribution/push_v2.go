@@ -41,6 +41,12 @@ type v2Pusher struct {
 	config            *ImagePushConfig
 	repo              distribution.Repository
 
+	// manifestList holds the images of the manifest list pushed as ref.
+	// Only the image ref refers to is pushed if it is empty.
+	manifestList []ManifestListEntry
+	// manifestListMediaType is the media type of the manifest list.
+	manifestListMediaType string
+
 	// pushState is state built by the Upload functions.
 	pushState pushState
 }
@@ -81,6 +87,10 @@ func (p *v2Pusher) Push(ctx context.Context) (err error) {
 }
 
 func (p *v2Pusher) pushV2Repository(ctx context.Context) (err error) {
+	if len(p.manifestList) != 0 {
+		return p.pushV2ManifestList(ctx, p.ref.(reference.NamedTagged))
+	}
+
 	if namedTagged, isNamedTagged := p.ref.(reference.NamedTagged); isNamedTagged {
 		imageID, err := p.config.ReferenceStore.Get(p.ref)
 		if err != nil {
@@ -115,55 +125,8 @@ func (p *v2Pusher) pushV2Repository(ctx context.Context) (err error) {
 func (p *v2Pusher) pushV2Tag(ctx context.Context, ref reference.NamedTagged, id digest.Digest) error {
 	logrus.Debugf("Pushing repository: %s", reference.FamiliarString(ref))
 
-	imgConfig, err := p.config.ImageStore.Get(id)
-	if err != nil {
-		return fmt.Errorf("could not find image from tag %s: %v", reference.FamiliarString(ref), err)
-	}
-
-	rootfs, err := p.config.ImageStore.RootFSFromConfig(imgConfig)
-	if err != nil {
-		return fmt.Errorf("unable to get rootfs for image %s: %s", reference.FamiliarString(ref), err)
-	}
-
-	platform, err := p.config.ImageStore.PlatformFromConfig(imgConfig)
-	if err != nil {
-		return fmt.Errorf("unable to get platform for image %s: %s", reference.FamiliarString(ref), err)
-	}
-
-	l, err := p.config.LayerStores[platform.OS].Get(rootfs.ChainID())
+	imgConfig, descriptors, err := p.uploadImage(ctx, reference.FamiliarString(ref), id)
 	if err != nil {
-		return fmt.Errorf("failed to get top layer from image: %v", err)
-	}
-	defer l.Release()
-
-	hmacKey, err := metadata.ComputeV2MetadataHMACKey(p.config.AuthConfig)
-	if err != nil {
-		return fmt.Errorf("failed to compute hmac key of auth config: %v", err)
-	}
-
-	var descriptors []xfer.UploadDescriptor
-
-	descriptorTemplate := v2PushDescriptor{
-		v2MetadataService: p.v2MetadataService,
-		hmacKey:           hmacKey,
-		repoInfo:          p.repoInfo.Name,
-		ref:               p.ref,
-		endpoint:          p.endpoint,
-		repo:              p.repo,
-		pushState:         &p.pushState,
-	}
-
-	// Loop bounds condition is to avoid pushing the base layer on Windows.
-	for range rootfs.DiffIDs {
-		descriptor := descriptorTemplate
-		descriptor.layer = l
-		descriptor.checkedDigests = make(map[digest.Digest]struct{})
-		descriptors = append(descriptors, &descriptor)
-
-		l = l.Parent()
-	}
-
-	if err := p.config.UploadManager.Upload(ctx, descriptors, p.config.ProgressOutput); err != nil {
 		return err
 	}
 
@@ -222,6 +185,8 @@ func (p *v2Pusher) pushV2Tag(ctx context.Context, ref reference.NamedTagged, id
 		return err
 	}
 
+	p.sendPushStats(pushStats(descriptors))
+
 	// Signal digest to the trust client so it can sign the
 	// push, if appropriate.
 	progress.Aux(p.config.ProgressOutput, apitypes.PushResult{Tag: ref.Tag(), Digest: manifestDigest.String(), Size: len(canonicalManifest)})
@@ -229,6 +194,65 @@ func (p *v2Pusher) pushV2Tag(ctx context.Context, ref reference.NamedTagged, id
 	return nil
 }
 
+// uploadImage uploads the layers of the image with ID id, referred to as
+// name in errors, that are missing in the repository. It returns the
+// configuration of the image and the descriptors of its layers.
+func (p *v2Pusher) uploadImage(ctx context.Context, name string, id digest.Digest) ([]byte, []xfer.UploadDescriptor, error) {
+	imgConfig, err := p.config.ImageStore.Get(id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not find image from tag %s: %v", name, err)
+	}
+
+	rootfs, err := p.config.ImageStore.RootFSFromConfig(imgConfig)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to get rootfs for image %s: %s", name, err)
+	}
+
+	platform, err := p.config.ImageStore.PlatformFromConfig(imgConfig)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to get platform for image %s: %s", name, err)
+	}
+
+	l, err := p.config.LayerStores[platform.OS].Get(rootfs.ChainID())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get top layer from image: %v", err)
+	}
+	defer l.Release()
+
+	hmacKey, err := metadata.ComputeV2MetadataHMACKey(p.config.AuthConfig)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to compute hmac key of auth config: %v", err)
+	}
+
+	var descriptors []xfer.UploadDescriptor
+
+	descriptorTemplate := v2PushDescriptor{
+		v2MetadataService: p.v2MetadataService,
+		hmacKey:           hmacKey,
+		repoInfo:          p.repoInfo.Name,
+		ref:               p.ref,
+		endpoint:          p.endpoint,
+		repo:              p.repo,
+		pushState:         &p.pushState,
+		mountCandidates:   p.config.MountCandidates,
+	}
+
+	// Loop bounds condition is to avoid pushing the base layer on Windows.
+	for range rootfs.DiffIDs {
+		descriptor := descriptorTemplate
+		descriptor.layer = l
+		descriptor.checkedDigests = make(map[digest.Digest]struct{})
+		descriptors = append(descriptors, &descriptor)
+
+		l = l.Parent()
+	}
+
+	if err := p.config.UploadManager.Upload(ctx, descriptors, p.config.ProgressOutput); err != nil {
+		return nil, nil, err
+	}
+	return imgConfig, descriptors, nil
+}
+
 func manifestFromBuilder(ctx context.Context, builder distribution.ManifestBuilder, descriptors []xfer.UploadDescriptor) (distribution.Manifest, error) {
 	// descriptors is in reverse order; iterate backwards to get references
 	// appended in the right order.
@@ -251,6 +275,9 @@ type v2PushDescriptor struct {
 	repo              distribution.Repository
 	pushState         *pushState
 	remoteDescriptor  distribution.Descriptor
+	mountCandidates   []reference.Named
+	// transfer is how the layer was made available in the registry.
+	transfer layerTransfer
 	// a set of digests whose presence has been checked in a target repository
 	checkedDigests map[digest.Digest]struct{}
 }
@@ -294,6 +321,7 @@ func (pd *v2PushDescriptor) Upload(ctx context.Context, progressOutput progress.
 
 	// Do we have any metadata associated with this layer's DiffID?
 	v2Metadata, err := pd.v2MetadataService.GetMetadata(diffID)
+	v2Metadata = schema2LayerMetadata(v2Metadata)
 	if err == nil {
 		// check for blob existence in the target repository
 		descriptor, exists, err := pd.layerAlreadyExists(ctx, progressOutput, diffID, true, 1, v2Metadata)
@@ -309,7 +337,8 @@ func (pd *v2PushDescriptor) Upload(ctx context.Context, progressOutput progress.
 	var layerUpload distribution.BlobWriter
 
 	// Attempt to find another repository in the same registry to mount the layer from to avoid an unnecessary upload
-	candidates := getRepositoryMountCandidates(pd.repoInfo, pd.hmacKey, maxMountAttempts, v2Metadata)
+	candidates := append(extraMountCandidates(pd.repoInfo, pd.mountCandidates, v2Metadata),
+		getRepositoryMountCandidates(pd.repoInfo, pd.hmacKey, maxMountAttempts, v2Metadata)...)
 	isUnauthorizedError := false
 	for _, mountCandidate := range candidates {
 		logrus.Debugf("attempting to mount layer %s (%s) from %s", diffID, mountCandidate.Digest, mountCandidate.SourceRepository)
@@ -348,17 +377,19 @@ func (pd *v2PushDescriptor) Upload(ctx context.Context, progressOutput progress.
 		case distribution.ErrBlobMounted:
 			progress.Updatef(progressOutput, pd.ID(), "Mounted from %s", err.From.Name())
 
-			err.Descriptor.MediaType = schema2.MediaTypeLayer
+			err.Descriptor.MediaType = layerMediaType(&mountCandidate)
 
 			pd.pushState.Lock()
 			pd.pushState.confirmedV2 = true
 			pd.pushState.remoteLayers[diffID] = err.Descriptor
 			pd.pushState.Unlock()
+			pd.transfer = layerMounted
 
 			// Cache mapping from this layer's DiffID to the blobsum
 			if err := pd.v2MetadataService.TagAndAdd(diffID, pd.hmacKey, metadata.V2Metadata{
 				Digest:           err.Descriptor.Digest,
 				SourceRepository: pd.repoInfo.Name(),
+				MediaType:        mountCandidate.MediaType,
 			}); err != nil {
 				return distribution.Descriptor{}, xfer.DoNotRetry{Err: err}
 			}
@@ -495,6 +526,7 @@ func (pd *v2PushDescriptor) uploadUsingSession(
 	pd.pushState.confirmedV2 = true
 	pd.pushState.remoteLayers[diffID] = desc
 	pd.pushState.Unlock()
+	pd.transfer = layerUploaded
 
 	return desc, nil
 }
@@ -555,11 +587,12 @@ attempts:
 				if err := pd.v2MetadataService.TagAndAdd(diffID, pd.hmacKey, metadata.V2Metadata{
 					Digest:           desc.Digest,
 					SourceRepository: pd.repoInfo.Name(),
+					MediaType:        meta.MediaType,
 				}); err != nil {
 					return distribution.Descriptor{}, false, xfer.DoNotRetry{Err: err}
 				}
 			}
-			desc.MediaType = schema2.MediaTypeLayer
+			desc.MediaType = layerMediaType(meta)
 			exists = true
 			break attempts
 		case distribution.ErrBlobUnknown:
@@ -582,6 +615,27 @@ attempts:
 	return desc, exists, nil
 }
 
+// schema2LayerMetadata returns the metadata of v2Metadata describing blobs
+// that schema2 manifests may refer to. zstd-compressed blobs, recorded when
+// pulling layers, require OCI image manifests and are not reused.
+func schema2LayerMetadata(v2Metadata []metadata.V2Metadata) []metadata.V2Metadata {
+	var filtered []metadata.V2Metadata
+	for _, meta := range v2Metadata {
+		if layerMediaType(&meta) == schema2.MediaTypeLayer {
+			filtered = append(filtered, meta)
+		}
+	}
+	return filtered
+}
+
+// layerMediaType returns the media type of the layer blob described by meta.
+func layerMediaType(meta *metadata.V2Metadata) string {
+	if meta.MediaType != "" {
+		return meta.MediaType
+	}
+	return schema2.MediaTypeLayer
+}
+
 // getMaxMountAndExistenceCheckAttempts returns a maximum number of cross repository mount attempts from
 // source repositories of target registry, maximum number of layer existence checks performed on the target
 // repository and whether the check shall be done also with digests mapped to different repositories. The
@@ -641,6 +695,33 @@ func getRepositoryMountCandidates(
 	return candidates
 }
 
+// extraMountCandidates returns v2 metadata items to mount the layer described
+// by v2Metadata from the repositories of repos which belong to the registry
+// of repoInfo, one for each digest the layer is known by.
+func extraMountCandidates(repoInfo reference.Named, repos []reference.Named, v2Metadata []metadata.V2Metadata) []metadata.V2Metadata {
+	var candidates []metadata.V2Metadata
+	for _, repo := range repos {
+		if reference.Domain(repo) != reference.Domain(repoInfo) || repo.Name() == repoInfo.Name() {
+			continue
+		}
+		seen := make(map[digest.Digest]struct{})
+		// the newest metadata comes last
+		for i := len(v2Metadata) - 1; i >= 0; i-- {
+			meta := v2Metadata[i]
+			if _, ok := seen[meta.Digest]; ok {
+				continue
+			}
+			seen[meta.Digest] = struct{}{}
+			candidates = append(candidates, metadata.V2Metadata{
+				Digest:           meta.Digest,
+				SourceRepository: repo.Name(),
+				MediaType:        meta.MediaType,
+			})
+		}
+	}
+	return candidates
+}
+
 // byLikeness is a sorting container for v2 metadata candidates for cross repository mount. The
 // candidate "a" is preferred over "b":
 //
@@ -707,3 +788,48 @@ func cancelLayerUpload(ctx context.Context, dgst digest.Digest, layerUpload dist
 		}
 	}
 }
+
+// layerTransfer is how a layer was made available in the registry by a push.
+type layerTransfer int
+
+const (
+	// layerSkipped is a layer which already existed in the registry, or
+	// which was pushed by another upload.
+	layerSkipped layerTransfer = iota
+	// layerMounted is a layer mounted from another repository.
+	layerMounted
+	// layerUploaded is a layer uploaded to the registry.
+	layerUploaded
+)
+
+// pushStats returns the statistics of the layers of descriptors, once they
+// are uploaded.
+func pushStats(descriptors []xfer.UploadDescriptor) apitypes.PushStats {
+	var stats apitypes.PushStats
+	for _, d := range descriptors {
+		pd := d.(*v2PushDescriptor)
+		size := pd.remoteDescriptor.Size
+		switch pd.transfer {
+		case layerUploaded:
+			stats.UploadedLayers++
+			stats.UploadedBytes += size
+		case layerMounted:
+			stats.MountedLayers++
+			stats.MountedBytes += size
+		default:
+			stats.SkippedLayers++
+			stats.SkippedBytes += size
+		}
+	}
+	return stats
+}
+
+// sendPushStats sends stats as an aux message, and records them if the push
+// configuration has a recorder.
+func (p *v2Pusher) sendPushStats(stats apitypes.PushStats) {
+	logrus.Debugf("push stats for %s: %+v", p.repoInfo.Name.Name(), stats)
+	progress.Aux(p.config.ProgressOutput, stats)
+	if p.config.PushStatsRecorder != nil {
+		p.config.PushStatsRecorder(stats)
+	}
+}
@@ -0,0 +1,148 @@
+package distribution // import "github.com/docker/docker/distribution"
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/containerd/containerd/platforms"
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest"
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/docker/distribution/reference"
+	apitypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/distribution/xfer"
+	"github.com/docker/docker/pkg/progress"
+	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+)
+
+// pushV2ManifestList pushes the images of the manifest list by digest, and
+// then the manifest list referencing them, tagged as ref.
+func (p *v2Pusher) pushV2ManifestList(ctx context.Context, ref reference.NamedTagged) error {
+	logrus.Debugf("Pushing manifest list: %s", reference.FamiliarString(ref))
+
+	manSvc, err := p.repo.Manifests(ctx)
+	if err != nil {
+		return err
+	}
+
+	var (
+		descriptors []manifestlist.ManifestDescriptor
+		// pushed holds the layers of all the images, for statistics
+		pushed []xfer.UploadDescriptor
+	)
+	seen := make(map[string]digest.Digest)
+	for _, entry := range p.manifestList {
+		imgConfig, layers, err := p.uploadImage(ctx, entry.ID.String(), entry.ID)
+		if err != nil {
+			return err
+		}
+		imgPlatform, err := p.config.ImageStore.PlatformFromConfig(imgConfig)
+		if err != nil {
+			return fmt.Errorf("unable to get platform for image %s: %s", entry.ID, err)
+		}
+		platform := manifestListPlatform(*imgPlatform, entry.Platform)
+		name := platforms.Format(specs.Platform{OS: platform.OS, Architecture: platform.Architecture, Variant: platform.Variant})
+		key := name + " " + platform.OSVersion
+		if other, ok := seen[key]; ok {
+			return fmt.Errorf("images %s and %s have the same platform %s", other, entry.ID, name)
+		}
+		seen[key] = entry.ID
+
+		pushed = append(pushed, layers...)
+
+		builder := schema2.NewManifestBuilder(p.repo.Blobs(ctx), p.config.ConfigMediaType, imgConfig)
+		m, err := manifestFromBuilder(ctx, builder, layers)
+		if err != nil {
+			return err
+		}
+		mediaType, payload, err := m.Payload()
+		if err != nil {
+			return err
+		}
+		dgst := digest.FromBytes(payload)
+		if exists, err := manSvc.Exists(ctx, dgst); err != nil || !exists {
+			if _, err := manSvc.Put(ctx, m); err != nil {
+				return err
+			}
+		}
+		progress.Messagef(p.config.ProgressOutput, "", "%s: digest: %s size: %d", name, dgst, len(payload))
+
+		descriptors = append(descriptors, manifestlist.ManifestDescriptor{
+			Descriptor: distribution.Descriptor{MediaType: mediaType, Size: int64(len(payload)), Digest: dgst},
+			Platform:   platform,
+		})
+	}
+
+	list, err := newManifestList(p.manifestListMediaType, descriptors)
+	if err != nil {
+		return err
+	}
+	_, payload, err := list.Payload()
+	if err != nil {
+		return err
+	}
+	if _, err := manSvc.Put(ctx, list, distribution.WithTag(ref.Tag())); err != nil {
+		return err
+	}
+
+	dgst := digest.FromBytes(payload)
+	progress.Messagef(p.config.ProgressOutput, "", "%s: digest: %s size: %d", ref.Tag(), dgst, len(payload))
+	p.sendPushStats(pushStats(pushed))
+	progress.Aux(p.config.ProgressOutput, apitypes.PushResult{Tag: ref.Tag(), Digest: dgst.String(), Size: len(payload)})
+	return nil
+}
+
+// manifestListPlatform returns the platform of an image in a manifest list,
+// the platform of the image overridden by the fields of override which are
+// set.
+func manifestListPlatform(platform specs.Platform, override *specs.Platform) manifestlist.PlatformSpec {
+	if override != nil {
+		if override.OS != "" {
+			platform.OS = override.OS
+		}
+		if override.Architecture != "" {
+			platform.Architecture = override.Architecture
+		}
+		if override.Variant != "" {
+			platform.Variant = override.Variant
+		}
+		if override.OSVersion != "" {
+			platform.OSVersion = override.OSVersion
+		}
+		if len(override.OSFeatures) != 0 {
+			platform.OSFeatures = override.OSFeatures
+		}
+	}
+	return manifestlist.PlatformSpec{
+		OS:           platform.OS,
+		Architecture: platform.Architecture,
+		Variant:      platform.Variant,
+		OSVersion:    platform.OSVersion,
+		OSFeatures:   platform.OSFeatures,
+	}
+}
+
+// newManifestList returns a manifest list of descriptors with media type
+// mediaType, either a Docker manifest list or an OCI image index.
+func newManifestList(mediaType string, descriptors []manifestlist.ManifestDescriptor) (*manifestlist.DeserializedManifestList, error) {
+	if mediaType == manifestlist.MediaTypeManifestList {
+		return manifestlist.FromDescriptors(descriptors)
+	}
+
+	b, err := json.MarshalIndent(manifestlist.ManifestList{
+		Versioned: manifest.Versioned{SchemaVersion: 2, MediaType: mediaType},
+		Manifests: descriptors,
+	}, "", "   ")
+	if err != nil {
+		return nil, err
+	}
+	list := new(manifestlist.DeserializedManifestList)
+	if err := list.UnmarshalJSON(b); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
@@ -0,0 +1,57 @@
+package distribution // import "github.com/docker/docker/distribution"
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestManifestListPlatform(t *testing.T) {
+	img := specs.Platform{OS: "linux", Architecture: "arm", OSVersion: "1.0"}
+
+	assert.Check(t, is.DeepEqual(manifestListPlatform(img, nil), manifestlist.PlatformSpec{
+		OS: "linux", Architecture: "arm", OSVersion: "1.0",
+	}))
+	assert.Check(t, is.DeepEqual(manifestListPlatform(img, &specs.Platform{Variant: "v7", OSFeatures: []string{"f"}}), manifestlist.PlatformSpec{
+		OS: "linux", Architecture: "arm", Variant: "v7", OSVersion: "1.0", OSFeatures: []string{"f"},
+	}))
+	assert.Check(t, is.DeepEqual(manifestListPlatform(img, &specs.Platform{Architecture: "arm64"}), manifestlist.PlatformSpec{
+		OS: "linux", Architecture: "arm64", OSVersion: "1.0",
+	}))
+}
+
+func TestNewManifestList(t *testing.T) {
+	descriptors := []manifestlist.ManifestDescriptor{{
+		Descriptor: distribution.Descriptor{MediaType: schema2.MediaTypeManifest, Size: 42, Digest: digest.FromString("amd64")},
+		Platform:   manifestlist.PlatformSpec{OS: "linux", Architecture: "amd64"},
+	}, {
+		Descriptor: distribution.Descriptor{MediaType: schema2.MediaTypeManifest, Size: 43, Digest: digest.FromString("arm64")},
+		Platform:   manifestlist.PlatformSpec{OS: "linux", Architecture: "arm64", Variant: "v8"},
+	}}
+
+	for _, mediaType := range []string{manifestlist.MediaTypeManifestList, specs.MediaTypeImageIndex} {
+		list, err := newManifestList(mediaType, descriptors)
+		assert.NilError(t, err)
+		payloadType, payload, err := list.Payload()
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(payloadType, mediaType))
+
+		// the payload is parsed back the way it is when pulled
+		m, desc, err := distribution.UnmarshalManifest(mediaType, payload)
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(desc.Digest, digest.FromBytes(payload)))
+		assert.Check(t, is.DeepEqual(m.(*manifestlist.DeserializedManifestList).Manifests, descriptors))
+
+		var index specs.Index
+		assert.NilError(t, json.Unmarshal(payload, &index))
+		assert.Check(t, is.Equal(index.SchemaVersion, 2))
+		assert.Check(t, is.Len(index.Manifests, 2))
+	}
+}
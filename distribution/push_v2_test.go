@@ -2,6 +2,7 @@ package distribution // import "github.com/docker/docker/distribution"
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/url"
 	"reflect"
@@ -13,11 +14,13 @@ import (
 	"github.com/docker/distribution/registry/api/errcode"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/distribution/metadata"
+	"github.com/docker/docker/distribution/xfer"
 	"github.com/docker/docker/layer"
 	"github.com/docker/docker/pkg/progress"
 	refstore "github.com/docker/docker/reference"
 	"github.com/docker/docker/registry"
 	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
 func TestGetRepositoryMountCandidates(t *testing.T) {
@@ -239,6 +242,16 @@ func TestLayerAlreadyExists(t *testing.T) {
 			expectedRequests:   []string{"apple"},
 			expectedAdditions:  []metadata.V2Metadata{{Digest: digest.Digest("apple"), SourceRepository: "docker.io/library/busybox"}},
 		},
+		{
+			name:               "find existing zstd blob",
+			targetRepo:         "busybox",
+			metadata:           []metadata.V2Metadata{{Digest: digest.Digest("apple"), SourceRepository: "docker.io/library/busybox", MediaType: MediaTypeImageLayerZstd}},
+			maxExistenceChecks: 3,
+			remoteBlobs:        map[digest.Digest]distribution.Descriptor{digest.Digest("apple"): {Digest: digest.Digest("apple")}},
+			expectedDescriptor: distribution.Descriptor{Digest: digest.Digest("apple"), MediaType: MediaTypeImageLayerZstd},
+			expectedExists:     true,
+			expectedRequests:   []string{"apple"},
+		},
 		{
 			name:               "overwrite media types",
 			targetRepo:         "busybox",
@@ -487,6 +500,15 @@ func (s *mockReferenceStore) Delete(ref reference.Named) (bool, error) {
 func (s *mockReferenceStore) Get(ref reference.Named) (digest.Digest, error) {
 	return "", nil
 }
+func (s *mockReferenceStore) AddPlatformReference(ref reference.Named, platform specs.Platform, id digest.Digest) error {
+	return nil
+}
+func (s *mockReferenceStore) DeleteImageReference(ref reference.Named, id digest.Digest) (bool, error) {
+	return true, nil
+}
+func (s *mockReferenceStore) GetPlatform(ref reference.Named, platform specs.Platform) (digest.Digest, error) {
+	return "", nil
+}
 
 func TestWhenEmptyAuthConfig(t *testing.T) {
 	for _, authInfo := range []struct {
@@ -618,6 +640,112 @@ func TestPushRegistryWhenAuthInfoEmpty(t *testing.T) {
 	}
 }
 
+func TestGetExtraMountCandidates(t *testing.T) {
+	target, _ := reference.ParseNormalizedNamed("user/app")
+	var repos []reference.Named
+	for _, name := range []string{"user/base", "user/app", "127.0.0.1/user/base"} {
+		repo, _ := reference.ParseNormalizedNamed(name)
+		repos = append(repos, repo)
+	}
+	v2Metadata := []metadata.V2Metadata{
+		taggedMetadata("key", "sha256:old", "docker.io/user/foo"),
+		taggedMetadata("key", "sha256:new", "docker.io/user/bar"),
+		{Digest: "sha256:new", SourceRepository: "docker.io/user/app", MediaType: MediaTypeImageLayerZstd},
+	}
+
+	candidates := extraMountCandidates(target, repos, v2Metadata)
+	expected := []metadata.V2Metadata{
+		{Digest: "sha256:new", SourceRepository: "docker.io/user/base", MediaType: MediaTypeImageLayerZstd},
+		{Digest: "sha256:old", SourceRepository: "docker.io/user/base"},
+	}
+	if !reflect.DeepEqual(candidates, expected) {
+		t.Fatalf("expected candidates %v, got %v", expected, candidates)
+	}
+}
+
+type mockBlobStoreWithMount struct {
+	mockBlobStore
+	mountable string
+}
+
+func (m *mockBlobStoreWithMount) Create(ctx context.Context, options ...distribution.BlobCreateOption) (distribution.BlobWriter, error) {
+	var opts distribution.CreateOptions
+	for _, o := range options {
+		if err := o.Apply(&opts); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Mount.ShouldMount && opts.Mount.From.Name() == m.mountable {
+		return nil, distribution.ErrBlobMounted{
+			From:       opts.Mount.From,
+			Descriptor: distribution.Descriptor{Digest: opts.Mount.From.Digest(), Size: 42},
+		}
+	}
+	return nil, errors.New("cannot mount")
+}
+
+type mockRepoWithMount struct {
+	mockRepo
+	mountable string
+}
+
+func (m *mockRepoWithMount) Blobs(ctx context.Context) distribution.BlobStore {
+	return &mockBlobStoreWithMount{mockBlobStore: mockBlobStore{repo: &m.mockRepo}, mountable: m.mountable}
+}
+
+type mockMetadataServiceWithLayer struct {
+	mockV2MetadataService
+}
+
+func (m *mockMetadataServiceWithLayer) GetMetadata(diffID layer.DiffID) ([]metadata.V2Metadata, error) {
+	return []metadata.V2Metadata{
+		taggedMetadata("abcd", "sha256:ff3a5c916c92643ff77519ffa742d3ec61b7f591b6b7504599d95a4a41134e28", "docker.io/user/other"),
+	}, nil
+}
+
+func TestUploadMountFromExtraCandidate(t *testing.T) {
+	repoInfo, _ := reference.ParseNormalizedNamed("user/app")
+	extra, _ := reference.ParseNormalizedNamed("user/extra")
+	repo := &mockRepoWithMount{mockRepo: mockRepo{t: t}, mountable: "user/extra"}
+	pd := &v2PushDescriptor{
+		hmacKey:  []byte("abcd"),
+		repoInfo: repoInfo,
+		layer: &storeLayer{
+			Layer: layer.EmptyLayer,
+		},
+		repo:              repo,
+		v2MetadataService: &mockMetadataServiceWithLayer{},
+		pushState: &pushState{
+			remoteLayers: make(map[layer.DiffID]distribution.Descriptor),
+		},
+		mountCandidates: []reference.Named{extra},
+		checkedDigests:  make(map[digest.Digest]struct{}),
+	}
+	desc, err := pd.Upload(context.Background(), &progressSink{t})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pd.SetRemoteDescriptor(desc)
+	if pd.transfer != layerMounted {
+		t.Fatalf("expected layer to be mounted, got %v", pd.transfer)
+	}
+
+	// the layer is known to exist when pushed again
+	again := *pd
+	again.transfer = layerSkipped
+	desc, err = again.Upload(context.Background(), &progressSink{t})
+	if err != nil {
+		t.Fatal(err)
+	}
+	again.SetRemoteDescriptor(desc)
+
+	stats := pushStats([]xfer.UploadDescriptor{pd, &again})
+	expected := types.PushStats{MountedLayers: 1, MountedBytes: 42, SkippedLayers: 1, SkippedBytes: 42}
+	if stats != expected {
+		t.Fatalf("expected stats %+v, got %+v", expected, stats)
+	}
+}
+
 func taggedMetadata(key string, dgst string, sourceRepo string) metadata.V2Metadata {
 	meta := metadata.V2Metadata{
 		Digest:           digest.Digest(dgst),
@@ -738,3 +866,17 @@ func (s *progressSink) WriteProgress(p progress.Progress) error {
 	s.t.Logf("progress update: %#+v", p)
 	return nil
 }
+
+func TestSchema2LayerMetadata(t *testing.T) {
+	v2Metadata := []metadata.V2Metadata{
+		{Digest: "sha256:gzip", SourceRepository: "docker.io/user/app"},
+		{Digest: "sha256:zstd", SourceRepository: "docker.io/user/app", MediaType: MediaTypeImageLayerZstd},
+		{Digest: "sha256:schema2", SourceRepository: "docker.io/user/base", MediaType: schema2.MediaTypeLayer},
+	}
+
+	filtered := schema2LayerMetadata(v2Metadata)
+	expected := []metadata.V2Metadata{v2Metadata[0], v2Metadata[2]}
+	if !reflect.DeepEqual(filtered, expected) {
+		t.Fatalf("expected metadata %v, got %v", expected, filtered)
+	}
+}
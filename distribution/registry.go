@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"os/exec"
+	"strings"
 	"time"
 
 	"github.com/docker/distribution"
@@ -39,6 +41,30 @@ var PluginTypes = []string{
 	schema2.MediaTypePluginConfig,
 }
 
+// Media types of zstd-compressed layers.
+const (
+	// MediaTypeImageLayerZstd is the media type of zstd-compressed layers.
+	MediaTypeImageLayerZstd = "application/vnd.oci.image.layer.v1.tar+zstd"
+	// MediaTypeImageLayerNonDistributableZstd is the media type of
+	// zstd-compressed layers with distribution restrictions.
+	MediaTypeImageLayerNonDistributableZstd = "application/vnd.oci.image.layer.nondistributable.v1.tar+zstd"
+)
+
+// LayerTypes represents the media types of the layers of images
+var LayerTypes = []string{
+	schema2.MediaTypeLayer,
+	schema2.MediaTypeForeignLayer,
+	schema2.MediaTypeUncompressedLayer,
+	ocispec.MediaTypeImageLayer,
+	ocispec.MediaTypeImageLayerGzip,
+	MediaTypeImageLayerZstd,
+	ocispec.MediaTypeImageLayerNonDistributable,
+	ocispec.MediaTypeImageLayerNonDistributableGzip,
+	MediaTypeImageLayerNonDistributableZstd,
+	// Treat defaulted values as gzip-compressed layers
+	"",
+}
+
 var mediaTypeClasses map[string]string
 
 func init() {
@@ -53,6 +79,51 @@ func init() {
 	}
 }
 
+// layerTypePrefixes are the prefixes of the media types of layers.
+var layerTypePrefixes = []string{
+	"application/vnd.docker.image.rootfs.",
+	ocispec.MediaTypeImageLayer,
+	ocispec.MediaTypeImageLayerNonDistributable,
+}
+
+// isUnsupportedLayerType returns whether the media type is recognized as
+// the media type of a layer, but one using a compression or an encryption
+// which is not supported. Other media types are not rejected, as the
+// compression of layers is detected when they are extracted.
+func isUnsupportedLayerType(mediaType string) bool {
+	for _, t := range LayerTypes {
+		if mediaType == t {
+			return false
+		}
+	}
+	for _, p := range layerTypePrefixes {
+		if strings.HasPrefix(mediaType, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func isZstdLayerType(mediaType string) bool {
+	return mediaType == MediaTypeImageLayerZstd || mediaType == MediaTypeImageLayerNonDistributableZstd
+}
+
+// lookPath is exec.LookPath, replaced in tests.
+var lookPath = exec.LookPath
+
+// checkLayerDecompression returns an error if layers of mediaType can't be
+// decompressed, so that pulls fail before downloading them.
+func checkLayerDecompression(mediaType string) error {
+	if !isZstdLayerType(mediaType) {
+		return nil
+	}
+	// zstd-compressed layers are decompressed with the zstd command
+	if _, err := lookPath("zstd"); err != nil {
+		return fmt.Errorf("layer media type %s requires the zstd command: %v", mediaType, err)
+	}
+	return nil
+}
+
 // NewV2Repository returns a repository (v2 only). It creates an HTTP transport
 // providing timeout settings and authentication support, and also verifies the
 // remote API version.
@@ -2,12 +2,14 @@ package distribution // import "github.com/docker/docker/distribution"
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"strings"
 	"testing"
 
+	"github.com/docker/distribution/manifest/schema2"
 	"github.com/docker/distribution/reference"
 	"github.com/docker/docker/api/types"
 	registrytypes "github.com/docker/docker/api/types/registry"
@@ -125,3 +127,42 @@ func TestTokenPassThruDifferentHost(t *testing.T) {
 		t.Fatal("Redirect should not forward Authorization header to another host")
 	}
 }
+
+func TestIsUnsupportedLayerType(t *testing.T) {
+	for _, mediaType := range append(LayerTypes, "application/octet-stream", "application/x-custom-layer") {
+		if isUnsupportedLayerType(mediaType) {
+			t.Errorf("expected media type %q to be supported", mediaType)
+		}
+	}
+	for _, mediaType := range []string{
+		"application/vnd.oci.image.layer.v1.tar+encrypted",
+		"application/vnd.oci.image.layer.nondistributable.v1.tar+lz4",
+		"application/vnd.docker.image.rootfs.diff.tar.xz",
+	} {
+		if !isUnsupportedLayerType(mediaType) {
+			t.Errorf("expected media type %q to be unsupported", mediaType)
+		}
+	}
+}
+
+func TestCheckLayerDecompression(t *testing.T) {
+	defer func(f func(string) (string, error)) { lookPath = f }(lookPath)
+	lookPath = func(file string) (string, error) {
+		return "", errors.New("executable file not found in $PATH")
+	}
+
+	if err := checkLayerDecompression(schema2.MediaTypeLayer); err != nil {
+		t.Errorf("expected gzip layers to be decompressed without the zstd command, got %v", err)
+	}
+	err := checkLayerDecompression(MediaTypeImageLayerZstd)
+	if err == nil || !strings.Contains(err.Error(), "requires the zstd command") {
+		t.Errorf("expected zstd layers to require the zstd command, got %v", err)
+	}
+
+	lookPath = func(file string) (string, error) {
+		return "/usr/bin/" + file, nil
+	}
+	if err := checkLayerDecompression(MediaTypeImageLayerZstd); err != nil {
+		t.Errorf("expected zstd layers to be decompressed with the zstd command, got %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+package distribution // import "github.com/docker/docker/distribution"
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/reference"
+	"github.com/docker/docker/image/signature"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+)
+
+// cosignSignatureAnnotation is the annotation holding the base64 encoded
+// signature of the payload of a layer of a signature manifest.
+const cosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+
+// maxSignaturePayloadSize is the maximum size of a signature payload.
+const maxSignaturePayloadSize = 1 << 20
+
+// signatureTag returns the tag of the signatures of the manifest with digest
+// dgst, as pushed by cosign.
+func signatureTag(dgst digest.Digest) string {
+	return fmt.Sprintf("%s-%s.sig", dgst.Algorithm(), dgst.Hex())
+}
+
+// manifestDigestOf returns the digest of manifest, which ref resolved to.
+func manifestDigestOf(ref reference.Named, manifest distribution.Manifest) (digest.Digest, error) {
+	if canonical, ok := ref.(reference.Canonical); ok {
+		return canonical.Digest(), nil
+	}
+	_, payload, err := manifest.Payload()
+	if err != nil {
+		return "", err
+	}
+	return digest.FromBytes(payload), nil
+}
+
+// fetchSignatures fetches the signatures of the manifest with digest dgst
+// stored in the repository, next to the manifest.
+func fetchSignatures(ctx context.Context, repo distribution.Repository, dgst digest.Digest) ([]signature.Signature, error) {
+	manSvc, err := repo.Manifests(ctx)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := manSvc.Get(ctx, "", distribution.WithTag(signatureTag(dgst)))
+	if err != nil {
+		return nil, err
+	}
+	// Layer annotations are lost when deserializing the manifest.
+	_, payload, err := manifest.Payload()
+	if err != nil {
+		return nil, err
+	}
+	var m ocispec.Manifest
+	if err := json.Unmarshal(payload, &m); err != nil {
+		return nil, err
+	}
+
+	var signatures []signature.Signature
+	for _, l := range m.Layers {
+		encoded, ok := l.Annotations[cosignSignatureAnnotation]
+		if !ok {
+			continue
+		}
+		sig, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			logrus.Debugf("Ignoring invalid signature of %s: %v", dgst, err)
+			continue
+		}
+		if l.Size > maxSignaturePayloadSize {
+			logrus.Debugf("Ignoring signature of %s: payload too large (%d bytes)", dgst, l.Size)
+			continue
+		}
+		payload, err := repo.Blobs(ctx).Get(ctx, l.Digest)
+		if err != nil {
+			return nil, err
+		}
+		if digest.FromBytes(payload) != l.Digest {
+			logrus.Debugf("Ignoring signature of %s: payload does not match digest %s", dgst, l.Digest)
+			continue
+		}
+		signatures = append(signatures, signature.Signature{Payload: payload, Signature: sig})
+	}
+	return signatures, nil
+}
+
+// verifySignatures checks the manifest with digest dgst against the
+// signature policy, using the signatures of the local store and of the
+// registry. The valid signatures found in the registry are added to the
+// local store, for the image to be verified again when containers are
+// created.
+func (p *v2Puller) verifySignatures(ctx context.Context, ref reference.Named, dgst digest.Digest) error {
+	req := p.config.SignaturePolicy.Requirement(ref)
+	if req == nil {
+		return nil
+	}
+
+	var signatures []signature.Signature
+	if p.config.SignatureStore != nil {
+		local, err := p.config.SignatureStore.Get(dgst)
+		if err != nil {
+			return err
+		}
+		signatures = append(signatures, local...)
+	}
+	var remote []signature.Signature
+	if req.Type == signature.Signed {
+		var err error
+		remote, err = fetchSignatures(ctx, p.repo, dgst)
+		if err != nil {
+			logrus.Debugf("Failed to fetch signatures of %s: %v", dgst, err)
+		}
+	}
+
+	if err := req.Verify(dgst, append(signatures, remote...)); err != nil {
+		p.config.ImageEventLogger(reference.FamiliarString(ref), reference.FamiliarName(p.repo.Named()), "reject")
+		return signatureVerificationError{ref: ref, err: err}
+	}
+
+	if p.config.SignatureStore != nil {
+		for _, sig := range remote {
+			if req.Verify(dgst, []signature.Signature{sig}) != nil {
+				continue
+			}
+			if err := p.config.SignatureStore.Add(dgst, sig); err != nil {
+				logrus.Warnf("Failed to store signature of %s: %v", dgst, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package distribution // import "github.com/docker/docker/distribution"
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/distribution/reference"
+	"github.com/docker/docker/api/types"
+	registrytypes "github.com/docker/docker/api/types/registry"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/image/signature"
+	"github.com/docker/docker/registry"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+// signatureRegistry is a registry stand-in serving the cosign signatures of
+// a manifest.
+type signatureRegistry struct {
+	manifests map[string][]byte
+	blobs     map[digest.Digest][]byte
+}
+
+func (r *signatureRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path == "/v2/" {
+		w.Header().Set("Docker-Distribution-API-Version", "registry/2.0")
+		return
+	}
+	for tag, b := range r.manifests {
+		if req.URL.Path == "/v2/foo/manifests/"+tag {
+			w.Header().Set("Content-Type", ocispec.MediaTypeImageManifest)
+			w.Header().Set("Docker-Content-Digest", digest.FromBytes(b).String())
+			w.Write(b)
+			return
+		}
+	}
+	for dgst, b := range r.blobs {
+		if req.URL.Path == "/v2/foo/blobs/"+dgst.String() {
+			w.Write(b)
+			return
+		}
+	}
+	http.NotFound(w, req)
+}
+
+// addSignature signs the manifest with digest dgst with key, and stores the
+// signature in the registry the way cosign does.
+func (r *signatureRegistry) addSignature(t *testing.T, key crypto.Signer, dgst digest.Digest) {
+	t.Helper()
+	payload := []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"example.com/foo"},"image":{"docker-manifest-digest":%q},"type":"cosign container image signature"},"optional":null}`, dgst))
+	hashed := sha256.Sum256(payload)
+	sig, err := key.Sign(rand.Reader, hashed[:], crypto.SHA256)
+	assert.NilError(t, err)
+
+	config := []byte("{}")
+	r.blobs[digest.FromBytes(config)] = config
+	r.blobs[digest.FromBytes(payload)] = payload
+	m := ocispec.Manifest{
+		Config: ocispec.Descriptor{MediaType: ocispec.MediaTypeImageConfig, Digest: digest.FromBytes(config), Size: int64(len(config))},
+		Layers: []ocispec.Descriptor{{
+			MediaType:   "application/vnd.dev.cosign.simplesigning.v1+json",
+			Digest:      digest.FromBytes(payload),
+			Size:        int64(len(payload)),
+			Annotations: map[string]string{cosignSignatureAnnotation: base64.StdEncoding.EncodeToString(sig)},
+		}},
+	}
+	m.SchemaVersion = 2
+	b, err := json.Marshal(m)
+	assert.NilError(t, err)
+	r.manifests[signatureTag(dgst)] = b
+}
+
+func newSignaturePuller(t *testing.T, ts *httptest.Server, config *ImagePullConfig) *v2Puller {
+	t.Helper()
+	uri, err := url.Parse(ts.URL)
+	assert.NilError(t, err)
+	n, err := reference.ParseNormalizedNamed(uri.Host + "/foo")
+	assert.NilError(t, err)
+	endpoint := registry.APIEndpoint{URL: uri, Version: registry.APIVersion2, TrimHostname: true}
+	repoInfo := &registry.RepositoryInfo{Name: n, Index: &registrytypes.IndexInfo{Name: uri.Host}}
+
+	puller, err := newPuller(endpoint, repoInfo, config)
+	assert.NilError(t, err)
+	p := puller.(*v2Puller)
+	p.repo, _, err = NewV2Repository(context.Background(), p.repoInfo, p.endpoint, nil, config.AuthConfig, "pull")
+	assert.NilError(t, err)
+	return p
+}
+
+func TestVerifySignatures(t *testing.T) {
+	dir, err := ioutil.TempDir("", "signature-pull")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	trusted, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+	untrusted, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(trusted.Public())
+	assert.NilError(t, err)
+	keyPath := filepath.Join(dir, "key.pub")
+	assert.NilError(t, ioutil.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0644))
+
+	reg := &signatureRegistry{manifests: map[string][]byte{}, blobs: map[digest.Digest][]byte{}}
+	ts := httptest.NewServer(reg)
+	defer ts.Close()
+	uri, err := url.Parse(ts.URL)
+	assert.NilError(t, err)
+
+	policyPath := filepath.Join(dir, "policy.json")
+	policy := fmt.Sprintf(`{"scopes":{%q:{"type":"signed","keys":[%q]}}}`, uri.Host, keyPath)
+	assert.NilError(t, ioutil.WriteFile(policyPath, []byte(policy), 0644))
+	p, err := signature.LoadPolicy(policyPath)
+	assert.NilError(t, err)
+	store, err := signature.NewStore(filepath.Join(dir, "signatures"))
+	assert.NilError(t, err)
+
+	var events []string
+	config := &ImagePullConfig{
+		Config: Config{
+			AuthConfig: &types.AuthConfig{},
+			ImageEventLogger: func(id, name, action string) {
+				events = append(events, action+" "+id)
+			},
+		},
+		Schema2Types:    ImageTypes,
+		SignaturePolicy: p,
+		SignatureStore:  store,
+	}
+	puller := newSignaturePuller(t, ts, config)
+	ref, err := reference.WithTag(puller.repoInfo.Name, "latest")
+	assert.NilError(t, err)
+
+	signed := digest.FromString("signed manifest")
+	reg.addSignature(t, trusted, signed)
+	assert.NilError(t, puller.verifySignatures(context.Background(), ref, signed))
+	sigs, err := store.Get(signed)
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(sigs, 1))
+
+	wrongKey := digest.FromString("manifest signed with another key")
+	reg.addSignature(t, untrusted, wrongKey)
+	err = puller.verifySignatures(context.Background(), ref, wrongKey)
+	assert.Check(t, is.ErrorContains(err, "does not match any trusted key"))
+	assert.Check(t, errdefs.IsForbidden(err))
+	sigs, err = store.Get(wrongKey)
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(sigs, 0))
+
+	unsigned := digest.FromString("unsigned manifest")
+	err = puller.verifySignatures(context.Background(), ref, unsigned)
+	assert.Check(t, is.ErrorContains(err, "no signature found"))
+
+	assert.Check(t, is.DeepEqual(events, []string{
+		"reject " + reference.FamiliarString(ref),
+		"reject " + reference.FamiliarString(ref),
+	}))
+
+	// other repositories are not verified
+	other, err := reference.ParseNormalizedNamed("busybox:latest")
+	assert.NilError(t, err)
+	assert.Check(t, puller.verifySignatures(context.Background(), other, unsigned))
+}
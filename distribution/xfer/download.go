@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"runtime"
+	"sync"
 	"time"
 
 	"github.com/docker/distribution"
@@ -18,7 +19,34 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-const maxDownloadAttempts = 5
+// RetryPolicy configures the retries of failed downloads.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts to download a layer.
+	MaxAttempts int
+	// Delay is the delay before the first retry. The delay is increased by
+	// Delay after each failed attempt.
+	Delay time.Duration
+	// MaxDelay is the maximum delay between two attempts. The delay is not
+	// limited if it's 0.
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy is the retry policy of downloads, unless another one
+// is set with SetRetryPolicy.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 5,
+	Delay:       5 * time.Second,
+}
+
+// delay returns the delay before the next attempt, after the given number of
+// failed attempts.
+func (p RetryPolicy) delay(attempts int) time.Duration {
+	d := time.Duration(attempts) * p.Delay
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	return d
+}
 
 // LayerDownloadManager figures out which layers need to be downloaded, then
 // registers and downloads those, taking into account dependencies between
@@ -27,6 +55,9 @@ type LayerDownloadManager struct {
 	layerStores  map[string]layer.Store
 	tm           TransferManager
 	waitDuration time.Duration
+
+	mu          sync.Mutex
+	retryPolicy RetryPolicy
 }
 
 // SetConcurrency sets the max concurrent downloads for each pull
@@ -34,12 +65,26 @@ func (ldm *LayerDownloadManager) SetConcurrency(concurrency int) {
 	ldm.tm.SetConcurrency(concurrency)
 }
 
+// SetRetryPolicy sets the retry policy of the downloads started afterwards.
+func (ldm *LayerDownloadManager) SetRetryPolicy(policy RetryPolicy) {
+	ldm.mu.Lock()
+	ldm.retryPolicy = policy
+	ldm.mu.Unlock()
+}
+
+func (ldm *LayerDownloadManager) getRetryPolicy() RetryPolicy {
+	ldm.mu.Lock()
+	defer ldm.mu.Unlock()
+	return ldm.retryPolicy
+}
+
 // NewLayerDownloadManager returns a new LayerDownloadManager.
 func NewLayerDownloadManager(layerStores map[string]layer.Store, concurrencyLimit int, options ...func(*LayerDownloadManager)) *LayerDownloadManager {
 	manager := LayerDownloadManager{
 		layerStores:  layerStores,
 		tm:           NewTransferManager(concurrencyLimit),
 		waitDuration: time.Second,
+		retryPolicy:  DefaultRetryPolicy,
 	}
 	for _, option := range options {
 		option(&manager)
@@ -267,6 +312,7 @@ func (ldm *LayerDownloadManager) makeDownloadFunc(descriptor DownloadDescriptor,
 
 			defer descriptor.Close()
 
+			retryPolicy := ldm.getRetryPolicy()
 			for {
 				downloadReader, size, err = descriptor.Download(d.Transfer.Context(), progressOutput)
 				if err == nil {
@@ -283,14 +329,17 @@ func (ldm *LayerDownloadManager) makeDownloadFunc(descriptor DownloadDescriptor,
 				}
 
 				retries++
-				if _, isDNR := err.(DoNotRetry); isDNR || retries == maxDownloadAttempts {
+				if _, isDNR := err.(DoNotRetry); isDNR || retries >= retryPolicy.MaxAttempts {
 					logrus.Errorf("Download failed: %v", err)
 					d.err = err
 					return
 				}
 
 				logrus.Errorf("Download failed, retrying: %v", err)
-				delay := retries * 5
+				delay := int(retryPolicy.delay(retries) / time.Second)
+				if delay <= 0 {
+					continue
+				}
 				ticker := time.NewTicker(ldm.waitDuration)
 
 			selectLoop:
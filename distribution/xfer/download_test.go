@@ -360,3 +360,55 @@ func TestCancelledDownload(t *testing.T) {
 	close(progressChan)
 	<-progressDone
 }
+
+func TestDownloadRetryPolicy(t *testing.T) {
+	layerStore := &mockLayerStore{make(map[layer.ChainID]*mockLayer)}
+	lsMap := make(map[string]layer.Store)
+	lsMap[runtime.GOOS] = layerStore
+	ldm := NewLayerDownloadManager(lsMap, maxDownloadConcurrency, func(m *LayerDownloadManager) { m.waitDuration = time.Millisecond })
+
+	progressChan := make(chan progress.Progress)
+	progressDone := make(chan struct{})
+	go func() {
+		for range progressChan {
+		}
+		close(progressDone)
+	}()
+	defer func() {
+		close(progressChan)
+		<-progressDone
+	}()
+
+	ldm.SetRetryPolicy(RetryPolicy{MaxAttempts: 3, Delay: time.Second})
+
+	// two retries are allowed with three attempts
+	descriptor := &mockDownloadDescriptor{
+		id:              "id1",
+		expectedDiffID:  layer.DiffID("sha256:68e2c75dc5c78ea9240689c60d7599766c213ae210434c53af18470ae8c53ec1"),
+		simulateRetries: 2,
+	}
+	_, release, err := ldm.Download(context.Background(), *image.NewRootFS(), runtime.GOOS, []DownloadDescriptor{descriptor}, progress.ChanOutput(progressChan))
+	if err != nil {
+		t.Fatalf("download error: %v", err)
+	}
+	release()
+
+	descriptor = &mockDownloadDescriptor{
+		id:              "id2",
+		expectedDiffID:  layer.DiffID("sha256:64a636223116aa837973a5d9c2bdd17d9b204e4f95ac423e20e65dfbb3655473"),
+		simulateRetries: 3,
+	}
+	_, _, err = ldm.Download(context.Background(), *image.NewRootFS(), runtime.GOOS, []DownloadDescriptor{descriptor}, progress.ChanOutput(progressChan))
+	if err == nil || err.Error() != "simulating retry" {
+		t.Fatalf("expected the download to fail after three attempts, got %v", err)
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 5, Delay: 5 * time.Second, MaxDelay: 12 * time.Second}
+	for attempts, expected := range []time.Duration{0, 5 * time.Second, 10 * time.Second, 12 * time.Second, 12 * time.Second} {
+		if d := p.delay(attempts); d != expected {
+			t.Errorf("expected a delay of %s after %d attempts, got %s", expected, attempts, d)
+		}
+	}
+}
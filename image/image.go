@@ -12,6 +12,7 @@ import (
 	"github.com/docker/docker/dockerversion"
 	"github.com/docker/docker/layer"
 	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
 // ID is the content-addressable ID of an image.
@@ -67,6 +68,7 @@ type Image struct {
 	History    []History `json:"history,omitempty"`
 	OSVersion  string    `json:"os.version,omitempty"`
 	OSFeatures []string  `json:"os.features,omitempty"`
+	Variant    string    `json:"variant,omitempty"`
 
 	// rawJSON caches the immutable JSON associated with this image.
 	rawJSON []byte
@@ -114,6 +116,18 @@ func (img *Image) OperatingSystem() string {
 	return os
 }
 
+// Platform returns the platform the image runs on, defaulting to the host
+// OS and architecture if not populated.
+func (img *Image) Platform() specs.Platform {
+	return specs.Platform{
+		OS:           img.OperatingSystem(),
+		Architecture: img.BaseImgArch(),
+		Variant:      img.Variant,
+		OSVersion:    img.OSVersion,
+		OSFeatures:   img.OSFeatures,
+	}
+}
+
 // MarshalJSON serializes the image to JSON. It sorts the top-level keys so
 // that JSON that's been manipulated by a push/pull cycle with a legacy
 // registry won't end up with a different key order.
@@ -177,6 +191,7 @@ func NewChildImage(img *Image, child ChildConfig, platform string) *Image {
 		History:    append(img.History, imgHistory),
 		OSFeatures: img.OSFeatures,
 		OSVersion:  img.OSVersion,
+		Variant:    img.Variant,
 	}
 }
 
@@ -213,6 +228,8 @@ type Exporter interface {
 	Load(io.ReadCloser, io.Writer, bool) error
 	// TODO: Load(net.Context, io.ReadCloser, <- chan StatusMessage) error
 	Save([]string, io.Writer) error
+	// SaveOCI saves the images as an OCI image layout
+	SaveOCI([]string, io.Writer) error
 }
 
 // NewFromJSON creates an Image configuration from json.
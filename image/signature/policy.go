@@ -0,0 +1,239 @@
+// Package signature verifies detached image signatures against a policy of
+// trusted public keys.
+//
+// Signatures follow the cosign "simple signing" format: a JSON payload
+// naming the digest of the signed manifest, and a signature of that payload
+// made with a private key.
+package signature // import "github.com/docker/docker/image/signature"
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/docker/distribution/reference"
+	"github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+)
+
+// Requirement types of a policy.
+const (
+	// Accept accepts images without verifying their signatures.
+	Accept = "accept"
+	// Reject rejects all images.
+	Reject = "reject"
+	// Signed accepts images with a valid signature made with one of the
+	// keys of the requirement.
+	Signed = "signed"
+)
+
+// Policy defines which images are trusted.
+type Policy struct {
+	// Default is the requirement for images that are not in any scope.
+	// Images are accepted if it is not set.
+	Default *Requirement `json:"default,omitempty"`
+	// Scopes holds the requirements for registries and repositories,
+	// by scope. A scope is either a registry hostname, or a fully
+	// qualified repository name or prefix of one, such as
+	// "docker.io/library/busybox" or "registry.example.com/team".
+	// The longest scope matching the name of an image applies.
+	Scopes map[string]*Requirement `json:"scopes,omitempty"`
+}
+
+// Requirement is the requirement an image must satisfy to be trusted.
+type Requirement struct {
+	// Type is one of Accept, Reject or Signed.
+	Type string `json:"type"`
+	// Keys holds the paths to the PEM encoded public keys trusted by a
+	// Signed requirement.
+	Keys []string `json:"keys,omitempty"`
+
+	keys []crypto.PublicKey
+}
+
+// LoadPolicy reads a policy from the JSON file at path, and loads the
+// public keys it references.
+func LoadPolicy(path string) (*Policy, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read signature policy")
+	}
+	var p Policy
+	if err := json.Unmarshal(b, &p); err != nil {
+		return nil, errors.Wrapf(err, "invalid signature policy %s", path)
+	}
+	if err := p.init(); err != nil {
+		return nil, errors.Wrapf(err, "invalid signature policy %s", path)
+	}
+	return &p, nil
+}
+
+func (p *Policy) init() error {
+	if p.Default != nil {
+		if err := p.Default.init(); err != nil {
+			return errors.Wrap(err, "default")
+		}
+	}
+	for scope, r := range p.Scopes {
+		if r == nil {
+			return fmt.Errorf("scope %s: missing requirement", scope)
+		}
+		if err := r.init(); err != nil {
+			return errors.Wrapf(err, "scope %s", scope)
+		}
+	}
+	return nil
+}
+
+func (r *Requirement) init() error {
+	switch r.Type {
+	case Accept, Reject:
+		if len(r.Keys) != 0 {
+			return fmt.Errorf("keys are not allowed for requirement type %q", r.Type)
+		}
+	case Signed:
+		if len(r.Keys) == 0 {
+			return errors.New("at least one key is required for signed images")
+		}
+		r.keys = nil
+		for _, path := range r.Keys {
+			key, err := loadPublicKey(path)
+			if err != nil {
+				return err
+			}
+			r.keys = append(r.keys, key)
+		}
+	default:
+		return fmt.Errorf("invalid requirement type %q", r.Type)
+	}
+	return nil
+}
+
+func loadPublicKey(path string) (crypto.PublicKey, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read public key")
+	}
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data in public key %s", path)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid public key %s", path)
+	}
+	switch key.(type) {
+	case *ecdsa.PublicKey, *rsa.PublicKey:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T in %s", key, path)
+	}
+}
+
+// Requirement returns the requirement applying to images of the repository
+// named name. It returns nil if images of the repository are accepted
+// without verification.
+func (p *Policy) Requirement(name reference.Named) *Requirement {
+	if p == nil {
+		return nil
+	}
+	r := p.Default
+	if name != nil {
+		var scopes []string
+		for scope := range p.Scopes {
+			if matchScope(scope, name.Name()) {
+				scopes = append(scopes, scope)
+			}
+		}
+		if len(scopes) > 0 {
+			sort.Slice(scopes, func(i, j int) bool { return len(scopes[i]) > len(scopes[j]) })
+			r = p.Scopes[scopes[0]]
+		}
+	}
+	if r == nil || r.Type == Accept {
+		return nil
+	}
+	return r
+}
+
+// matchScope returns whether the repository named name is in scope, scope
+// matching at path component boundaries only.
+func matchScope(scope, name string) bool {
+	scope = strings.TrimSuffix(scope, "/")
+	return name == scope || strings.HasPrefix(name, scope+"/")
+}
+
+// Verify checks that one of the signatures is a valid signature of the
+// manifest with digest dgst, made with one of the keys of the requirement.
+func (r *Requirement) Verify(dgst digest.Digest, signatures []Signature) error {
+	if r == nil || r.Type == Accept {
+		return nil
+	}
+	if r.Type == Reject {
+		return errors.New("images are rejected by the signature policy")
+	}
+	if len(signatures) == 0 {
+		return fmt.Errorf("no signature found for %s", dgst)
+	}
+	var lastErr error
+	for _, sig := range signatures {
+		if lastErr = r.verify(dgst, sig); lastErr == nil {
+			return nil
+		}
+	}
+	return errors.Wrapf(lastErr, "no valid signature found for %s", dgst)
+}
+
+func (r *Requirement) verify(dgst digest.Digest, sig Signature) error {
+	var payload simpleSigning
+	if err := json.Unmarshal(sig.Payload, &payload); err != nil {
+		return errors.Wrap(err, "invalid signature payload")
+	}
+	if payload.Critical.Image.DockerManifestDigest != dgst.String() {
+		return fmt.Errorf("signature is for %s", payload.Critical.Image.DockerManifestDigest)
+	}
+	hashed := sha256.Sum256(sig.Payload)
+	for _, key := range r.keys {
+		if verifySignature(key, hashed[:], sig.Signature) {
+			return nil
+		}
+	}
+	return errors.New("signature does not match any trusted key")
+}
+
+func verifySignature(key crypto.PublicKey, hashed, sig []byte) bool {
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		var s struct{ R, S *big.Int }
+		if rest, err := asn1.Unmarshal(sig, &s); err != nil || len(rest) != 0 {
+			return false
+		}
+		return ecdsa.Verify(k, hashed, s.R, s.S)
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(k, crypto.SHA256, hashed, sig) == nil
+	}
+	return false
+}
+
+// simpleSigning is the payload of a signature.
+type simpleSigning struct {
+	Critical struct {
+		Identity struct {
+			DockerReference string `json:"docker-reference"`
+		} `json:"identity"`
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+}
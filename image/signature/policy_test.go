@@ -0,0 +1,154 @@
+package signature // import "github.com/docker/docker/image/signature"
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/distribution/reference"
+	"github.com/opencontainers/go-digest"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+const testDigest = digest.Digest("sha256:86e0e091d0da6bde2456dbb48306f3956bbeb2eae1b5b9a43045843f69fe4aaa")
+
+func writePublicKey(t *testing.T, dir, name string, key crypto.PublicKey) string {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(key)
+	assert.NilError(t, err)
+	path := filepath.Join(dir, name)
+	assert.NilError(t, ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0644))
+	return path
+}
+
+func sign(t *testing.T, key crypto.Signer, dgst digest.Digest) Signature {
+	t.Helper()
+	payload := []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"example.com/foo"},"image":{"docker-manifest-digest":%q},"type":"cosign container image signature"},"optional":null}`, dgst))
+	hashed := sha256.Sum256(payload)
+	sig, err := key.Sign(rand.Reader, hashed[:], crypto.SHA256)
+	assert.NilError(t, err)
+	return Signature{Payload: payload, Signature: sig}
+}
+
+func writePolicy(t *testing.T, dir string, p Policy) string {
+	t.Helper()
+	b, err := json.Marshal(p)
+	assert.NilError(t, err)
+	path := filepath.Join(dir, "policy.json")
+	assert.NilError(t, ioutil.WriteFile(path, b, 0644))
+	return path
+}
+
+func TestPolicyRequirement(t *testing.T) {
+	dir, err := ioutil.TempDir("", "signature-policy")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+	keyPath := writePublicKey(t, dir, "key.pub", key.Public())
+
+	p, err := LoadPolicy(writePolicy(t, dir, Policy{
+		Default: &Requirement{Type: Reject},
+		Scopes: map[string]*Requirement{
+			"docker.io":                 {Type: Accept},
+			"example.com":               {Type: Signed, Keys: []string{keyPath}},
+			"example.com/team/unsigned": {Type: Accept},
+		},
+	}))
+	assert.NilError(t, err)
+
+	for _, tc := range []struct {
+		name     string
+		expected string
+	}{
+		{name: "busybox", expected: Accept},
+		{name: "example.com/foo", expected: Signed},
+		{name: "example.com/team/unsigned", expected: Accept},
+		{name: "example.com/team/unsigned-not", expected: Signed},
+		{name: "example.com/team/unsigned/sub", expected: Accept},
+		{name: "example.community/foo", expected: Reject},
+		{name: "quay.io/foo", expected: Reject},
+	} {
+		named, err := reference.ParseNormalizedNamed(tc.name)
+		assert.NilError(t, err)
+		r := p.Requirement(named)
+		if tc.expected == Accept {
+			assert.Check(t, is.Nil(r), tc.name)
+			continue
+		}
+		assert.Assert(t, r != nil, tc.name)
+		assert.Check(t, is.Equal(r.Type, tc.expected), tc.name)
+	}
+
+	assert.Check(t, is.Equal(p.Requirement(nil).Type, Reject))
+	var nilPolicy *Policy
+	assert.Check(t, is.Nil(nilPolicy.Requirement(nil)))
+}
+
+func TestLoadPolicyInvalid(t *testing.T) {
+	dir, err := ioutil.TempDir("", "signature-policy")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	for _, tc := range []struct {
+		policy   Policy
+		expected string
+	}{
+		{policy: Policy{Default: &Requirement{Type: "maybe"}}, expected: `invalid requirement type "maybe"`},
+		{policy: Policy{Default: &Requirement{Type: Signed}}, expected: "at least one key is required"},
+		{policy: Policy{Default: &Requirement{Type: Accept, Keys: []string{"key.pub"}}}, expected: "keys are not allowed"},
+		{policy: Policy{Scopes: map[string]*Requirement{"example.com": {Type: Signed, Keys: []string{filepath.Join(dir, "missing.pub")}}}}, expected: "failed to read public key"},
+	} {
+		_, err := LoadPolicy(writePolicy(t, dir, tc.policy))
+		assert.Check(t, is.ErrorContains(err, tc.expected))
+	}
+}
+
+func TestRequirementVerify(t *testing.T) {
+	dir, err := ioutil.TempDir("", "signature-policy")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NilError(t, err)
+	untrusted, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+
+	r := &Requirement{Type: Signed, Keys: []string{
+		writePublicKey(t, dir, "ec.pub", ecKey.Public()),
+		writePublicKey(t, dir, "rsa.pub", rsaKey.Public()),
+	}}
+	assert.NilError(t, r.init())
+
+	assert.Check(t, r.Verify(testDigest, []Signature{sign(t, ecKey, testDigest)}))
+	assert.Check(t, r.Verify(testDigest, []Signature{sign(t, rsaKey, testDigest)}))
+	assert.Check(t, r.Verify(testDigest, []Signature{sign(t, untrusted, testDigest), sign(t, ecKey, testDigest)}))
+
+	assert.Check(t, is.ErrorContains(r.Verify(testDigest, nil), "no signature found"))
+	assert.Check(t, is.ErrorContains(r.Verify(testDigest, []Signature{sign(t, untrusted, testDigest)}), "does not match any trusted key"))
+
+	other := digest.FromString("other")
+	assert.Check(t, is.ErrorContains(r.Verify(testDigest, []Signature{sign(t, ecKey, other)}), "signature is for "+other.String()))
+
+	tampered := sign(t, ecKey, testDigest)
+	tampered.Payload = append(tampered.Payload, ' ')
+	assert.Check(t, is.ErrorContains(r.Verify(testDigest, []Signature{tampered}), "does not match any trusted key"))
+
+	reject := &Requirement{Type: Reject}
+	assert.Check(t, is.ErrorContains(reject.Verify(testDigest, []Signature{sign(t, ecKey, testDigest)}), "rejected"))
+}
@@ -0,0 +1,97 @@
+package signature // import "github.com/docker/docker/image/signature"
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/docker/docker/pkg/ioutils"
+	"github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Signature is a detached signature of a manifest.
+type Signature struct {
+	// Payload is the signed payload, naming the digest of the manifest.
+	Payload []byte `json:"payload"`
+	// Signature is the signature of the payload.
+	Signature []byte `json:"signature"`
+}
+
+// Store is a local store of signatures, by manifest digest. Signatures are
+// stored as JSON files in <root>/<algorithm>/<hex>/, and may be added by
+// hand as well as by the daemon.
+type Store struct {
+	sync.Mutex
+	root string
+}
+
+// NewStore returns a signature store rooted at root.
+func NewStore(root string) (*Store, error) {
+	if err := os.MkdirAll(root, 0700); err != nil {
+		return nil, err
+	}
+	return &Store{root: root}, nil
+}
+
+func (s *Store) dir(dgst digest.Digest) string {
+	return filepath.Join(s.root, string(dgst.Algorithm()), dgst.Hex())
+}
+
+// Get returns the signatures stored for the manifest with digest dgst.
+// Invalid files are ignored.
+func (s *Store) Get(dgst digest.Digest) ([]Signature, error) {
+	if err := dgst.Validate(); err != nil {
+		return nil, err
+	}
+	s.Lock()
+	defer s.Unlock()
+
+	files, err := ioutil.ReadDir(s.dir(dgst))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var signatures []Signature
+	for _, f := range files {
+		if f.IsDir() || filepath.Ext(f.Name()) != ".json" {
+			continue
+		}
+		path := filepath.Join(s.dir(dgst), f.Name())
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var sig Signature
+		if err := json.Unmarshal(b, &sig); err != nil {
+			logrus.Warnf("Ignoring invalid signature %s: %v", path, err)
+			continue
+		}
+		signatures = append(signatures, sig)
+	}
+	return signatures, nil
+}
+
+// Add stores a signature of the manifest with digest dgst.
+func (s *Store) Add(dgst digest.Digest, sig Signature) error {
+	if err := dgst.Validate(); err != nil {
+		return err
+	}
+	b, err := json.Marshal(sig)
+	if err != nil {
+		return err
+	}
+	s.Lock()
+	defer s.Unlock()
+
+	if err := os.MkdirAll(s.dir(dgst), 0700); err != nil {
+		return errors.Wrap(err, "failed to store signature")
+	}
+	path := filepath.Join(s.dir(dgst), digest.FromBytes(sig.Signature).Hex()+".json")
+	return ioutils.AtomicWriteFile(path, b, 0600)
+}
@@ -0,0 +1,47 @@
+package signature // import "github.com/docker/docker/image/signature"
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestStore(t *testing.T) {
+	root, err := ioutil.TempDir("", "signature-store")
+	assert.NilError(t, err)
+	defer os.RemoveAll(root)
+
+	s, err := NewStore(root)
+	assert.NilError(t, err)
+
+	sigs, err := s.Get(testDigest)
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(sigs, 0))
+
+	sig := Signature{Payload: []byte("payload"), Signature: []byte("signature")}
+	assert.NilError(t, s.Add(testDigest, sig))
+	// adding the same signature again is a no-op
+	assert.NilError(t, s.Add(testDigest, sig))
+
+	// signatures may also be added by hand, invalid ones are ignored
+	dir := filepath.Join(root, "sha256", testDigest.Hex())
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(dir, "manual.json"), []byte(`{"payload":"cGF5bG9hZDI=","signature":"c2lnMg=="}`), 0600))
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(dir, "invalid.json"), []byte("{"), 0600))
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(dir, "README"), []byte("not a signature"), 0600))
+
+	sigs, err = s.Get(testDigest)
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(sigs, 2))
+	payloads := map[string]string{}
+	for _, sig := range sigs {
+		payloads[string(sig.Payload)] = string(sig.Signature)
+	}
+	assert.Check(t, is.DeepEqual(payloads, map[string]string{"payload": "signature", "payload2": "sig2"}))
+
+	_, err = s.Get("sha256:invalid")
+	assert.Check(t, is.ErrorContains(err, "invalid"))
+}
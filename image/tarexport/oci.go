@@ -0,0 +1,454 @@
+package tarexport // import "github.com/docker/docker/image/tarexport"
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+
+	"github.com/containerd/containerd/platforms"
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/docker/distribution/reference"
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/ioutils"
+	"github.com/docker/docker/pkg/progress"
+	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	ociIndexFileName = "index.json"
+	ociBlobsDir      = "blobs"
+)
+
+// ociBlobPath returns the path of a blob in an OCI image layout. It always
+// uses forward slashes, as these paths are used in the archive.
+func ociBlobPath(dgst digest.Digest) string {
+	return path.Join(ociBlobsDir, dgst.Algorithm().String(), dgst.Hex())
+}
+
+type ociSaveSession struct {
+	*tarexporter
+	outDir string
+	images map[image.ID]*imageDescriptor
+	layers map[layer.DiffID]ocispec.Descriptor // cache every layer blob to avoid duplicates
+}
+
+// SaveOCI writes the images to outStream as an OCI image layout archive. The
+// references of the images are kept as org.opencontainers.image.ref.name
+// annotations in the index.
+func (l *tarexporter) SaveOCI(names []string, outStream io.Writer) error {
+	images, err := l.parseNames(names)
+	if err != nil {
+		return err
+	}
+
+	// Release all the image top layer references
+	defer l.releaseLayerReferences(images)
+	return (&ociSaveSession{tarexporter: l, images: images}).save(outStream)
+}
+
+func (s *ociSaveSession) save(outStream io.Writer) error {
+	s.layers = make(map[layer.DiffID]ocispec.Descriptor)
+
+	tempDir, err := ioutil.TempDir("", "docker-export-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tempDir)
+
+	s.outDir = tempDir
+	if err := os.MkdirAll(filepath.Join(tempDir, ociBlobsDir, digest.Canonical.String()), 0755); err != nil {
+		return err
+	}
+
+	layout, err := json.Marshal(ocispec.ImageLayout{Version: ocispec.ImageLayoutVersion})
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(tempDir, ocispec.ImageLayoutFile), layout, 0644); err != nil {
+		return err
+	}
+
+	index := ocispec.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		Manifests: []ocispec.Descriptor{},
+	}
+	for id, imageDescr := range s.images {
+		desc, err := s.saveImage(id)
+		if err != nil {
+			return err
+		}
+		if len(imageDescr.refs) == 0 {
+			index.Manifests = append(index.Manifests, desc)
+		}
+		for _, ref := range imageDescr.refs {
+			d := desc
+			d.Annotations = map[string]string{ocispec.AnnotationRefName: ref.String()}
+			index.Manifests = append(index.Manifests, d)
+		}
+		s.tarexporter.loggerImgEvent.LogImageEvent(id.String(), id.String(), "save")
+	}
+
+	// keep the index stable for a given set of images
+	sort.SliceStable(index.Manifests, func(i, j int) bool {
+		a, b := index.Manifests[i], index.Manifests[j]
+		if a.Digest != b.Digest {
+			return a.Digest < b.Digest
+		}
+		return a.Annotations[ocispec.AnnotationRefName] < b.Annotations[ocispec.AnnotationRefName]
+	})
+
+	indexJSON, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(tempDir, ociIndexFileName), indexJSON, 0644); err != nil {
+		return err
+	}
+
+	fs, err := archive.Tar(tempDir, archive.Uncompressed)
+	if err != nil {
+		return err
+	}
+	defer fs.Close()
+
+	_, err = io.Copy(outStream, fs)
+	return err
+}
+
+// saveImage writes the layers, the config and the manifest of an image, and
+// returns the descriptor of the manifest.
+func (s *ociSaveSession) saveImage(id image.ID) (ocispec.Descriptor, error) {
+	img := s.images[id].image
+	os := img.OS
+	if os == "" {
+		os = runtime.GOOS
+	}
+
+	layers := []ocispec.Descriptor{}
+	rootFS := *img.RootFS
+	rootFS.DiffIDs = nil
+	for _, diffID := range img.RootFS.DiffIDs {
+		rootFS.Append(diffID)
+		desc, err := s.saveLayer(rootFS.ChainID(), os)
+		if err != nil {
+			return ocispec.Descriptor{}, err
+		}
+		layers = append(layers, desc)
+	}
+
+	config, err := s.writeBlob(ocispec.MediaTypeImageConfig, img.RawJSON())
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	manifest, err := json.Marshal(ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		Config:    config,
+		Layers:    layers,
+	})
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	desc, err := s.writeBlob(ocispec.MediaTypeImageManifest, manifest)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	desc.Platform = &ocispec.Platform{
+		Architecture: img.Architecture,
+		OS:           os,
+		OSVersion:    img.OSVersion,
+		OSFeatures:   img.OSFeatures,
+	}
+	return desc, nil
+}
+
+// saveLayer writes the gzip-compressed layer as a blob, unless it was already
+// written for another image.
+func (s *ociSaveSession) saveLayer(id layer.ChainID, operatingSystem string) (ocispec.Descriptor, error) {
+	l, err := s.lss[operatingSystem].Get(id)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	defer layer.ReleaseAndLog(s.lss[operatingSystem], l)
+
+	if desc, exists := s.layers[l.DiffID()]; exists {
+		return desc, nil
+	}
+
+	arch, err := l.TarStream()
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	defer arch.Close()
+
+	blobsDir := filepath.Join(s.outDir, ociBlobsDir, digest.Canonical.String())
+	tmpFile, err := ioutil.TempFile(blobsDir, ".layer-")
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	defer tmpFile.Close()
+
+	digester := digest.Canonical.Digester()
+	counter := ioutils.NewWriteCounter(io.MultiWriter(tmpFile, digester.Hash()))
+	compressed, err := archive.CompressStream(counter, archive.Gzip)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	if _, err := io.Copy(compressed, arch); err != nil {
+		compressed.Close()
+		return ocispec.Descriptor{}, err
+	}
+	if err := compressed.Close(); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	desc := ocispec.Descriptor{
+		MediaType: ocispec.MediaTypeImageLayerGzip,
+		Digest:    digester.Digest(),
+		Size:      counter.Count,
+	}
+	if err := os.Rename(tmpFile.Name(), filepath.Join(s.outDir, ociBlobPath(desc.Digest))); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	s.layers[l.DiffID()] = desc
+	return desc, nil
+}
+
+func (s *ociSaveSession) writeBlob(mediaType string, data []byte) (ocispec.Descriptor, error) {
+	desc := ocispec.Descriptor{
+		MediaType: mediaType,
+		Digest:    digest.FromBytes(data),
+		Size:      int64(len(data)),
+	}
+	if err := ioutil.WriteFile(filepath.Join(s.outDir, ociBlobPath(desc.Digest)), data, 0644); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	return desc, nil
+}
+
+// ociLoad loads the images of an OCI image layout. The images are tagged with
+// the org.opencontainers.image.ref.name annotations of the index holding a
+// full reference.
+func (l *tarexporter) ociLoad(tmpDir string, outStream io.Writer, progressOutput progress.Output) error {
+	var layout ocispec.ImageLayout
+	if err := readOCIJSON(tmpDir, ocispec.ImageLayoutFile, &layout); err != nil {
+		return err
+	}
+	if layout.Version != ocispec.ImageLayoutVersion {
+		return fmt.Errorf("unsupported OCI image layout version: %q", layout.Version)
+	}
+
+	var index ocispec.Index
+	if err := readOCIJSON(tmpDir, ociIndexFileName, &index); err != nil {
+		return err
+	}
+
+	loaded := make(map[digest.Digest]image.ID)
+	var imageIDsStr string
+	var imageRefCount int
+
+	for _, desc := range index.Manifests {
+		manifestDesc, err := resolveOCIManifest(tmpDir, desc)
+		if err != nil {
+			return err
+		}
+		imgID, ok := loaded[manifestDesc.Digest]
+		if !ok {
+			imgID, err = l.loadOCIImage(tmpDir, manifestDesc, progressOutput)
+			if err != nil {
+				return err
+			}
+			loaded[manifestDesc.Digest] = imgID
+			imageIDsStr += fmt.Sprintf("Loaded image ID: %s\n", imgID)
+			l.loggerImgEvent.LogImageEvent(imgID.String(), imgID.String(), "load")
+		}
+
+		name, ok := desc.Annotations[ocispec.AnnotationRefName]
+		if !ok {
+			continue
+		}
+		named, err := reference.ParseNormalizedNamed(name)
+		if err != nil {
+			// the annotation may only hold a tag, which cannot be used
+			// without the name of the repository
+			logrus.Debugf("Ignoring OCI reference %q of image %s: %v", name, imgID, err)
+			continue
+		}
+		ref, ok := reference.TagNameOnly(named).(reference.NamedTagged)
+		if !ok {
+			logrus.Debugf("Ignoring OCI reference %q of image %s: not a tag", name, imgID)
+			continue
+		}
+		l.setLoadedTag(ref, imgID.Digest(), outStream)
+		outStream.Write([]byte(fmt.Sprintf("Loaded image: %s\n", reference.FamiliarString(ref))))
+		imageRefCount++
+	}
+
+	if imageRefCount == 0 {
+		outStream.Write([]byte(imageIDsStr))
+	}
+
+	return nil
+}
+
+// resolveOCIManifest returns the descriptor of the image manifest for the
+// current platform referenced by desc, which may be an image index.
+func resolveOCIManifest(dir string, desc ocispec.Descriptor) (ocispec.Descriptor, error) {
+	switch desc.MediaType {
+	case ocispec.MediaTypeImageManifest, schema2.MediaTypeManifest:
+		return desc, nil
+	case ocispec.MediaTypeImageIndex, manifestlist.MediaTypeManifestList:
+		var index ocispec.Index
+		if err := readOCIBlobJSON(dir, desc, &index); err != nil {
+			return ocispec.Descriptor{}, err
+		}
+		p := platforms.DefaultSpec()
+		m := platforms.NewMatcher(p)
+		for _, d := range index.Manifests {
+			if d.Platform != nil && m.Match(*d.Platform) {
+				return resolveOCIManifest(dir, d)
+			}
+		}
+		return ocispec.Descriptor{}, fmt.Errorf("no manifest for platform %s in image index %s", platforms.Format(p), desc.Digest)
+	default:
+		return ocispec.Descriptor{}, fmt.Errorf("unsupported media type %q for %s", desc.MediaType, desc.Digest)
+	}
+}
+
+func (l *tarexporter) loadOCIImage(dir string, desc ocispec.Descriptor, progressOutput progress.Output) (image.ID, error) {
+	var manifest ocispec.Manifest
+	if err := readOCIBlobJSON(dir, desc, &manifest); err != nil {
+		return "", err
+	}
+	config, err := readOCIBlob(dir, manifest.Config)
+	if err != nil {
+		return "", err
+	}
+	img, err := image.NewFromJSON(config)
+	if err != nil {
+		return "", err
+	}
+	if err := checkCompatibleOS(img.OS); err != nil {
+		return "", err
+	}
+	if expected, actual := len(manifest.Layers), len(img.RootFS.DiffIDs); expected != actual {
+		return "", fmt.Errorf("invalid manifest, layers length mismatch: expected %d, got %d", expected, actual)
+	}
+
+	os := img.OS
+	if os == "" {
+		os = runtime.GOOS
+	}
+
+	rootFS := *img.RootFS
+	rootFS.DiffIDs = nil
+	for i, diffID := range img.RootFS.DiffIDs {
+		r := rootFS
+		r.Append(diffID)
+		newLayer, err := l.lss[os].Get(r.ChainID())
+		if err != nil {
+			layerPath, err := ociBlobFile(dir, manifest.Layers[i])
+			if err != nil {
+				return "", err
+			}
+			if err := verifyOCIBlob(layerPath, manifest.Layers[i]); err != nil {
+				return "", err
+			}
+			newLayer, err = l.loadLayer(layerPath, rootFS, diffID.String(), os, distribution.Descriptor{}, progressOutput)
+			if err != nil {
+				return "", err
+			}
+		}
+		defer layer.ReleaseAndLog(l.lss[os], newLayer)
+		if expected, actual := diffID, newLayer.DiffID(); expected != actual {
+			return "", fmt.Errorf("invalid diffID for layer %d: expected %q, got %q", i, expected, actual)
+		}
+		rootFS.Append(diffID)
+	}
+
+	return l.is.Create(config)
+}
+
+func readOCIJSON(dir, name string, v interface{}) error {
+	p, err := safePath(dir, name)
+	if err != nil {
+		return err
+	}
+	b, err := ioutil.ReadFile(p)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return errors.Wrapf(err, "invalid %s", name)
+	}
+	return nil
+}
+
+// ociBlobFile returns the path of the file holding the blob of desc.
+func ociBlobFile(dir string, desc ocispec.Descriptor) (string, error) {
+	if err := desc.Digest.Validate(); err != nil {
+		return "", errors.Wrapf(err, "invalid digest %q", desc.Digest)
+	}
+	return safePath(dir, ociBlobPath(desc.Digest))
+}
+
+func readOCIBlob(dir string, desc ocispec.Descriptor) ([]byte, error) {
+	p, err := ociBlobFile(dir, desc)
+	if err != nil {
+		return nil, err
+	}
+	b, err := ioutil.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(b)) != desc.Size || desc.Digest.Algorithm().FromBytes(b) != desc.Digest {
+		return nil, fmt.Errorf("blob %s does not match its descriptor", desc.Digest)
+	}
+	return b, nil
+}
+
+func readOCIBlobJSON(dir string, desc ocispec.Descriptor, v interface{}) error {
+	b, err := readOCIBlob(dir, desc)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return errors.Wrapf(err, "invalid blob %s", desc.Digest)
+	}
+	return nil
+}
+
+func verifyOCIBlob(p string, desc ocispec.Descriptor) error {
+	f, err := os.Open(p)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	verifier := desc.Digest.Verifier()
+	n, err := io.Copy(verifier, f)
+	if err != nil {
+		return err
+	}
+	if n != desc.Size || !verifier.Verified() {
+		return fmt.Errorf("blob %s does not match its descriptor", desc.Digest)
+	}
+	return nil
+}
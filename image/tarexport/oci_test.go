@@ -0,0 +1,215 @@
+package tarexport // import "github.com/docker/docker/image/tarexport"
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"testing"
+
+	"github.com/docker/distribution/reference"
+	_ "github.com/docker/docker/daemon/graphdriver/vfs"
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+	"github.com/docker/docker/pkg/idtools"
+	"github.com/docker/docker/pkg/reexec"
+	refstore "github.com/docker/docker/reference"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/skip"
+)
+
+func init() {
+	reexec.Init()
+}
+
+type nopImageEventLogger struct{}
+
+func (nopImageEventLogger) LogImageEvent(imageID, refName, action string) {}
+
+type testStores struct {
+	ls layer.Store
+	is image.Store
+	rs refstore.Store
+}
+
+func newTestStores(t *testing.T) (*testStores, func()) {
+	t.Helper()
+	root, err := ioutil.TempDir("", "tarexport-")
+	assert.NilError(t, err)
+
+	ls, err := layer.NewStoreFromOptions(layer.StoreOptions{
+		Root:                      root,
+		MetadataStorePathTemplate: filepath.Join(root, "image", "%s", "layerdb"),
+		GraphDriver:               "vfs",
+		IDMapping:                 &idtools.IdentityMapping{},
+		OS:                        runtime.GOOS,
+	})
+	assert.NilError(t, err)
+	fs, err := image.NewFSStoreBackend(filepath.Join(root, "imagedb"))
+	assert.NilError(t, err)
+	imgStore, err := image.NewImageStore(fs, map[string]image.LayerGetReleaser{runtime.GOOS: ls})
+	assert.NilError(t, err)
+	rs, err := refstore.NewReferenceStore(filepath.Join(root, "repositories.json"))
+	assert.NilError(t, err)
+
+	return &testStores{ls: ls, is: imgStore, rs: rs}, func() {
+		ls.Cleanup()
+		os.RemoveAll(root)
+	}
+}
+
+func (s *testStores) exporter() image.Exporter {
+	return NewTarExporter(s.is, map[string]layer.Store{runtime.GOOS: s.ls}, s.rs, nopImageEventLogger{})
+}
+
+func newTestLayerTar(t *testing.T, name, content string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	assert.NilError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+	_, err := tw.Write([]byte(content))
+	assert.NilError(t, err)
+	assert.NilError(t, tw.Close())
+	return buf.Bytes()
+}
+
+// createTestImage creates an image with a layer per file, and tags it.
+func (s *testStores) createTestImage(t *testing.T, ref string, files ...string) image.ID {
+	t.Helper()
+	rootFS := image.NewRootFS()
+	for _, f := range files {
+		l, err := s.ls.Register(bytes.NewReader(newTestLayerTar(t, f, f)), rootFS.ChainID())
+		assert.NilError(t, err)
+		rootFS.Append(l.DiffID())
+		// the image store takes its own reference on the top layer
+		defer layer.ReleaseAndLog(s.ls, l)
+	}
+	config, err := json.Marshal(map[string]interface{}{
+		"architecture": runtime.GOARCH,
+		"os":           runtime.GOOS,
+		"rootfs":       rootFS,
+	})
+	assert.NilError(t, err)
+	id, err := s.is.Create(config)
+	assert.NilError(t, err)
+
+	named, err := reference.ParseNormalizedNamed(ref)
+	assert.NilError(t, err)
+	assert.NilError(t, s.rs.AddTag(named.(reference.NamedTagged), id.Digest(), true))
+	return id
+}
+
+func readTarFiles(t *testing.T, r io.Reader) map[string][]byte {
+	t.Helper()
+	files := make(map[string][]byte)
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return files
+		}
+		assert.NilError(t, err)
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		b, err := ioutil.ReadAll(tr)
+		assert.NilError(t, err)
+		files[hdr.Name] = b
+	}
+}
+
+func TestSaveLoadOCI(t *testing.T) {
+	skip.If(t, os.Getuid() != 0, "skipping test that requires root")
+
+	src, cleanup := newTestStores(t)
+	defer cleanup()
+
+	id1 := src.createTestImage(t, "busybox:latest", "a", "b")
+	id2 := src.createTestImage(t, "example.com/foo:v1", "a", "c")
+
+	var archive bytes.Buffer
+	assert.NilError(t, src.exporter().SaveOCI([]string{"busybox:latest", "example.com/foo:v1"}, &archive))
+
+	files := readTarFiles(t, bytes.NewReader(archive.Bytes()))
+	assert.Check(t, is.Equal(string(files[ocispec.ImageLayoutFile]), `{"imageLayoutVersion":"1.0.0"}`))
+	// the configs, the manifests and the three distinct layers
+	var blobs int
+	for name := range files {
+		if filepath.Dir(name) == "blobs/sha256" {
+			blobs++
+		}
+	}
+	assert.Check(t, is.Equal(blobs, 7))
+	assert.Check(t, is.Contains(files, ociBlobPath(id1.Digest())))
+
+	var index ocispec.Index
+	assert.NilError(t, json.Unmarshal(files[ociIndexFileName], &index))
+	assert.Assert(t, is.Len(index.Manifests, 2))
+	var refs []string
+	for _, m := range index.Manifests {
+		assert.Check(t, is.Equal(m.MediaType, ocispec.MediaTypeImageManifest))
+		assert.Check(t, is.Contains(files, ociBlobPath(m.Digest)))
+		refs = append(refs, m.Annotations[ocispec.AnnotationRefName])
+	}
+	sort.Strings(refs)
+	assert.Check(t, is.DeepEqual(refs, []string{"docker.io/library/busybox:latest", "example.com/foo:v1"}))
+
+	dst, cleanup := newTestStores(t)
+	defer cleanup()
+
+	var out bytes.Buffer
+	assert.NilError(t, dst.exporter().Load(ioutil.NopCloser(&archive), &out, true))
+	assert.Check(t, is.Contains(out.String(), "Loaded image: busybox:latest"))
+	assert.Check(t, is.Contains(out.String(), "Loaded image: example.com/foo:v1"))
+
+	for ref, id := range map[string]image.ID{"busybox:latest": id1, "example.com/foo:v1": id2} {
+		named, err := reference.ParseNormalizedNamed(ref)
+		assert.NilError(t, err)
+		dgst, err := dst.rs.Get(named)
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(dgst, id.Digest()))
+
+		img, err := dst.is.Get(id)
+		assert.NilError(t, err)
+		l, err := dst.ls.Get(img.RootFS.ChainID())
+		assert.NilError(t, err)
+		layer.ReleaseAndLog(dst.ls, l)
+	}
+}
+
+func TestLoadOCIVerifiesBlobs(t *testing.T) {
+	skip.If(t, os.Getuid() != 0, "skipping test that requires root")
+
+	src, cleanup := newTestStores(t)
+	defer cleanup()
+	id := src.createTestImage(t, "busybox:latest", "a")
+
+	var archive bytes.Buffer
+	assert.NilError(t, src.exporter().SaveOCI([]string{"busybox:latest"}, &archive))
+
+	// replace the config of the image with another one
+	files := readTarFiles(t, &archive)
+	var corrupted bytes.Buffer
+	tw := tar.NewWriter(&corrupted)
+	for name, b := range files {
+		if name == ociBlobPath(id.Digest()) {
+			b = bytes.Replace(b, []byte(runtime.GOARCH), []byte("other"), 1)
+		}
+		assert.NilError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(b)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write(b)
+		assert.NilError(t, err)
+	}
+	assert.NilError(t, tw.Close())
+
+	dst, cleanup := newTestStores(t)
+	defer cleanup()
+	err := dst.exporter().Load(ioutil.NopCloser(&corrupted), ioutil.Discard, true)
+	assert.Check(t, is.ErrorContains(err, "does not match its descriptor"))
+}
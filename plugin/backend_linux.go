@@ -518,6 +518,17 @@ func (r *pluginReference) Delete(ref reference.Named) (bool, error) {
 	// Read only, ignore
 	return false, nil
 }
+func (r *pluginReference) AddPlatformReference(ref reference.Named, platform specs.Platform, id digest.Digest) error {
+	// Read only, ignore
+	return nil
+}
+func (r *pluginReference) DeleteImageReference(ref reference.Named, id digest.Digest) (bool, error) {
+	// Read only, ignore
+	return false, nil
+}
+func (r *pluginReference) GetPlatform(ref reference.Named, platform specs.Platform) (digest.Digest, error) {
+	return digest.Digest(""), refstore.ErrDoesNotExist
+}
 
 type pluginConfigStore struct {
 	pm     *Manager
@@ -8,9 +8,11 @@ import (
 	"sort"
 	"sync"
 
+	"github.com/containerd/containerd/platforms"
 	"github.com/docker/distribution/reference"
 	"github.com/docker/docker/pkg/ioutils"
 	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
 )
 
@@ -32,8 +34,11 @@ type Store interface {
 	ReferencesByName(ref reference.Named) []Association
 	AddTag(ref reference.Named, id digest.Digest, force bool) error
 	AddDigest(ref reference.Canonical, id digest.Digest, force bool) error
+	AddPlatformReference(ref reference.Named, platform specs.Platform, id digest.Digest) error
 	Delete(ref reference.Named) (bool, error)
+	DeleteImageReference(ref reference.Named, id digest.Digest) (bool, error)
 	Get(ref reference.Named) (digest.Digest, error)
+	GetPlatform(ref reference.Named, platform specs.Platform) (digest.Digest, error)
 }
 
 type store struct {
@@ -43,6 +48,10 @@ type store struct {
 	jsonPath string
 	// Repositories is a map of repositories, indexed by name.
 	Repositories map[string]repository
+	// Platforms holds the images of references pulled for several
+	// platforms, indexed by stringified reference and then by platform.
+	// The image a reference refers to in Repositories is the default one.
+	Platforms map[string]platformImages `json:",omitempty"`
 	// referencesByIDCache is a cache of references indexed by ID, to speed
 	// up References.
 	referencesByIDCache map[digest.Digest]map[string]reference.Named
@@ -52,6 +61,9 @@ type store struct {
 // including the repository name.
 type repository map[string]digest.Digest
 
+// platformImages maps normalized platforms to digests.
+type platformImages map[string]digest.Digest
+
 type lexicalRefs []reference.Named
 
 func (a lexicalRefs) Len() int      { return len(a) }
@@ -79,6 +91,7 @@ func NewReferenceStore(jsonPath string) (Store, error) {
 	store := &store{
 		jsonPath:            abspath,
 		Repositories:        make(map[string]repository),
+		Platforms:           make(map[string]platformImages),
 		referencesByIDCache: make(map[digest.Digest]map[string]reference.Named),
 	}
 	// Load the json file if it exists, otherwise create it.
@@ -167,20 +180,61 @@ func (store *store) addReference(ref reference.Named, id digest.Digest, force bo
 			)
 		}
 
-		if store.referencesByIDCache[oldID] != nil {
-			delete(store.referencesByIDCache[oldID], refStr)
-			if len(store.referencesByIDCache[oldID]) == 0 {
-				delete(store.referencesByIDCache, oldID)
-			}
-		}
 	}
 
 	repository[refStr] = id
-	if store.referencesByIDCache[id] == nil {
-		store.referencesByIDCache[id] = make(map[string]reference.Named)
+	store.cacheReference(id, refStr, ref)
+	if exists {
+		store.uncacheReference(oldID, refStr)
+	}
+
+	return store.save()
+}
+
+// AddPlatformReference associates ref with the image id for platform. The
+// image also becomes the default one ref refers to if ref does not refer to
+// any image yet, or if it refers to the previous image of platform.
+func (store *store) AddPlatformReference(ref reference.Named, platform specs.Platform, id digest.Digest) error {
+	ref, err := normalizeReference(ref)
+	if err != nil {
+		return err
+	}
+
+	refName := reference.FamiliarName(ref)
+	refStr := reference.FamiliarString(ref)
+	platformStr := platforms.Format(platforms.Normalize(platform))
+
+	if refName == string(digest.Canonical) {
+		return errors.WithStack(invalidTagError("refusing to create an ambiguous tag using digest algorithm as name"))
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	images := store.Platforms[refStr]
+	if images == nil {
+		images = make(platformImages)
+		store.Platforms[refStr] = images
+	}
+	oldID, exists := images[platformStr]
+	if exists && oldID == id {
+		return nil
+	}
+	images[platformStr] = id
+	store.cacheReference(id, refStr, ref)
+
+	repository, ok := store.Repositories[refName]
+	if !ok || repository == nil {
+		repository = make(map[string]digest.Digest)
+		store.Repositories[refName] = repository
+	}
+	if defaultID, ok := repository[refStr]; !ok || (exists && defaultID == oldID) {
+		repository[refStr] = id
 	}
-	store.referencesByIDCache[id][refStr] = ref
 
+	if exists {
+		store.uncacheReference(oldID, refStr)
+	}
 	return store.save()
 }
 
@@ -200,43 +254,83 @@ func (store *store) Delete(ref reference.Named) (bool, error) {
 	store.mu.Lock()
 	defer store.mu.Unlock()
 
-	repository, exists := store.Repositories[refName]
-	if !exists {
+	repository := store.Repositories[refName]
+	id, exists := repository[refStr]
+	images := store.Platforms[refStr]
+	if !exists && len(images) == 0 {
 		return false, ErrDoesNotExist
 	}
 
-	if id, exists := repository[refStr]; exists {
+	if exists {
 		delete(repository, refStr)
 		if len(repository) == 0 {
 			delete(store.Repositories, refName)
 		}
-		if store.referencesByIDCache[id] != nil {
-			delete(store.referencesByIDCache[id], refStr)
-			if len(store.referencesByIDCache[id]) == 0 {
-				delete(store.referencesByIDCache, id)
+	}
+	delete(store.Platforms, refStr)
+	if exists {
+		store.uncacheReference(id, refStr)
+	}
+	for _, id := range images {
+		store.uncacheReference(id, refStr)
+	}
+	return true, store.save()
+}
+
+// DeleteImageReference deletes the associations of ref with the image id,
+// as the default image of ref and as the image of a platform. The images of
+// other platforms are left untouched and remain reachable through
+// GetPlatform only: if the default image of ref is deleted, ref no longer
+// has a default image. It returns true if a deletion happened, or false
+// otherwise.
+func (store *store) DeleteImageReference(ref reference.Named, id digest.Digest) (bool, error) {
+	ref, err := favorDigest(ref)
+	if err != nil {
+		return false, err
+	}
+
+	ref = reference.TagNameOnly(ref)
+
+	refName := reference.FamiliarName(ref)
+	refStr := reference.FamiliarString(ref)
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	deleted := false
+	images := store.Platforms[refStr]
+	for platform, platformID := range images {
+		if platformID == id {
+			delete(images, platform)
+			deleted = true
+		}
+	}
+	if len(images) == 0 {
+		delete(store.Platforms, refStr)
+	}
+
+	if repository := store.Repositories[refName]; repository != nil {
+		if defaultID, exists := repository[refStr]; exists && defaultID == id {
+			delete(repository, refStr)
+			if len(repository) == 0 {
+				delete(store.Repositories, refName)
 			}
+			deleted = true
 		}
-		return true, store.save()
 	}
 
-	return false, ErrDoesNotExist
+	if !deleted {
+		return false, ErrDoesNotExist
+	}
+	store.uncacheReference(id, refStr)
+	return true, store.save()
 }
 
 // Get retrieves an item from the store by reference
 func (store *store) Get(ref reference.Named) (digest.Digest, error) {
-	if canonical, ok := ref.(reference.Canonical); ok {
-		// If reference contains both tag and digest, only
-		// lookup by digest as it takes precedence over
-		// tag, until tag/digest combos are stored.
-		if _, ok := ref.(reference.Tagged); ok {
-			var err error
-			ref, err = reference.WithDigest(reference.TrimNamed(canonical), canonical.Digest())
-			if err != nil {
-				return "", err
-			}
-		}
-	} else {
-		ref = reference.TagNameOnly(ref)
+	ref, err := normalizeReference(ref)
+	if err != nil {
+		return "", err
 	}
 
 	refName := reference.FamiliarName(ref)
@@ -258,6 +352,70 @@ func (store *store) Get(ref reference.Named) (digest.Digest, error) {
 	return id, nil
 }
 
+// GetPlatform retrieves the image of platform from the store by reference.
+// Only the images added with AddPlatformReference are looked up.
+func (store *store) GetPlatform(ref reference.Named, platform specs.Platform) (digest.Digest, error) {
+	ref, err := normalizeReference(ref)
+	if err != nil {
+		return "", err
+	}
+
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	id, exists := store.Platforms[reference.FamiliarString(ref)][platforms.Format(platforms.Normalize(platform))]
+	if !exists {
+		return "", ErrDoesNotExist
+	}
+	return id, nil
+}
+
+// normalizeReference returns ref as it is stored: references with both a
+// tag and a digest are looked up by digest, and references without a tag
+// use the default tag.
+func normalizeReference(ref reference.Named) (reference.Named, error) {
+	if canonical, ok := ref.(reference.Canonical); ok {
+		// If reference contains both tag and digest, only
+		// lookup by digest as it takes precedence over
+		// tag, until tag/digest combos are stored.
+		if _, ok := ref.(reference.Tagged); ok {
+			return reference.WithDigest(reference.TrimNamed(canonical), canonical.Digest())
+		}
+		return ref, nil
+	}
+	return reference.TagNameOnly(ref), nil
+}
+
+// cacheReference records that ref, stringified as refStr, refers to id.
+func (store *store) cacheReference(id digest.Digest, refStr string, ref reference.Named) {
+	if store.referencesByIDCache[id] == nil {
+		store.referencesByIDCache[id] = make(map[string]reference.Named)
+	}
+	store.referencesByIDCache[id][refStr] = ref
+}
+
+// uncacheReference removes the reference stringified as refStr from the
+// references to id, unless it still refers to id by default or for a
+// platform.
+func (store *store) uncacheReference(id digest.Digest, refStr string) {
+	if store.referencesByIDCache[id] == nil {
+		return
+	}
+	ref := store.referencesByIDCache[id][refStr]
+	if ref != nil && store.Repositories[reference.FamiliarName(ref)][refStr] == id {
+		return
+	}
+	for _, platformID := range store.Platforms[refStr] {
+		if platformID == id {
+			return
+		}
+	}
+	delete(store.referencesByIDCache[id], refStr)
+	if len(store.referencesByIDCache[id]) == 0 {
+		delete(store.referencesByIDCache, id)
+	}
+}
+
 // References returns a slice of references to the given ID. The slice
 // will be nil if there are no references to this ID.
 func (store *store) References(id digest.Digest) []reference.Named {
@@ -337,10 +495,20 @@ func (store *store) reload() error {
 				// Should never happen
 				continue
 			}
-			if store.referencesByIDCache[refID] == nil {
-				store.referencesByIDCache[refID] = make(map[string]reference.Named)
-			}
-			store.referencesByIDCache[refID][refStr] = ref
+			store.cacheReference(refID, refStr, ref)
+		}
+	}
+	if store.Platforms == nil {
+		store.Platforms = make(map[string]platformImages)
+	}
+	for refStr, images := range store.Platforms {
+		ref, err := reference.ParseNormalizedNamed(refStr)
+		if err != nil {
+			// Should never happen
+			continue
+		}
+		for _, refID := range images {
+			store.cacheReference(refID, refStr, ref)
 		}
 	}
 
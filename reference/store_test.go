@@ -10,6 +10,7 @@ import (
 
 	"github.com/docker/distribution/reference"
 	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
 	"gotest.tools/assert"
 	is "gotest.tools/assert/cmp"
 )
@@ -356,3 +357,102 @@ func TestInvalidTags(t *testing.T) {
 	err = store.AddTag(ref, id, true)
 	assert.Check(t, is.ErrorContains(err, ""))
 }
+
+func TestPlatformReferences(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "tag-store-test")
+	assert.NilError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	jsonFile := filepath.Join(tmpDir, "repositories.json")
+	store, err := NewReferenceStore(jsonFile)
+	assert.NilError(t, err)
+
+	amd64 := specs.Platform{OS: "linux", Architecture: "amd64"}
+	arm64 := specs.Platform{OS: "linux", Architecture: "arm64"}
+	amd64ID := digest.Digest("sha256:470022b8af682154f57a2163d030eb369549549cba00edc69e1b99b46bb924d6")
+	arm64ID := digest.Digest("sha256:ae300ebc4a4f00693702cfb0a5e0b7bc527b353828dc86ad09fb95c8a681b793")
+	newArm64ID := digest.Digest("sha256:6153498b9ac00968d71b66cca4eac37e990b5f9eb50c26877eb8799c8847451b")
+
+	ref, err := reference.ParseNormalizedNamed("username/repo")
+	assert.NilError(t, err)
+
+	// The first platform added becomes the default image
+	assert.NilError(t, store.AddPlatformReference(ref, arm64, arm64ID))
+	assert.NilError(t, store.AddPlatformReference(ref, amd64, amd64ID))
+	id, err := store.Get(ref)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(id, arm64ID))
+	id, err = store.GetPlatform(ref, amd64)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(id, amd64ID))
+	_, err = store.GetPlatform(ref, specs.Platform{OS: "linux", Architecture: "s390x"})
+	assert.Check(t, is.Equal(err, ErrDoesNotExist))
+	assert.Check(t, is.Len(store.References(amd64ID), 1))
+
+	// Replacing the image of the default platform replaces the default image
+	assert.NilError(t, store.AddPlatformReference(ref, arm64, newArm64ID))
+	id, err = store.Get(ref)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(id, newArm64ID))
+	assert.Check(t, is.Len(store.References(arm64ID), 0))
+
+	// Platform references are persisted
+	store, err = NewReferenceStore(jsonFile)
+	assert.NilError(t, err)
+	id, err = store.GetPlatform(ref, arm64)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(id, newArm64ID))
+	assert.Check(t, is.Len(store.References(amd64ID), 1))
+
+	// Deleting the default image deletes the default tag, the images of
+	// other platforms are only reachable by platform
+	deleted, err := store.DeleteImageReference(ref, newArm64ID)
+	assert.NilError(t, err)
+	assert.Check(t, deleted)
+	_, err = store.Get(ref)
+	assert.Check(t, is.Equal(err, ErrDoesNotExist))
+	_, err = store.GetPlatform(ref, arm64)
+	assert.Check(t, is.Equal(err, ErrDoesNotExist))
+	id, err = store.GetPlatform(ref, amd64)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(id, amd64ID))
+	assert.Check(t, is.Len(store.References(newArm64ID), 0))
+	assert.Check(t, is.Len(store.References(amd64ID), 1))
+
+	_, err = store.DeleteImageReference(ref, newArm64ID)
+	assert.Check(t, is.Equal(err, ErrDoesNotExist))
+
+	// Deleting the image of a platform keeps the default image
+	assert.NilError(t, store.AddPlatformReference(ref, arm64, arm64ID))
+	deleted, err = store.DeleteImageReference(ref, amd64ID)
+	assert.NilError(t, err)
+	assert.Check(t, deleted)
+	id, err = store.Get(ref)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(id, arm64ID))
+	assert.Check(t, is.Len(store.References(amd64ID), 0))
+
+	// Deleting the reference deletes the images of all platforms
+	assert.NilError(t, store.AddPlatformReference(ref, amd64, amd64ID))
+	deleted, err = store.Delete(ref)
+	assert.NilError(t, err)
+	assert.Check(t, deleted)
+	_, err = store.GetPlatform(ref, arm64)
+	assert.Check(t, is.Equal(err, ErrDoesNotExist))
+	assert.Check(t, is.Len(store.References(amd64ID), 0))
+	assert.Check(t, is.Len(store.References(arm64ID), 0))
+
+	// A reference without a default image can still be deleted
+	assert.NilError(t, store.AddPlatformReference(ref, amd64, amd64ID))
+	assert.NilError(t, store.AddPlatformReference(ref, arm64, arm64ID))
+	_, err = store.DeleteImageReference(ref, amd64ID)
+	assert.NilError(t, err)
+	_, err = store.Get(ref)
+	assert.Check(t, is.Equal(err, ErrDoesNotExist))
+	deleted, err = store.Delete(ref)
+	assert.NilError(t, err)
+	assert.Check(t, deleted)
+	_, err = store.GetPlatform(ref, arm64)
+	assert.Check(t, is.Equal(err, ErrDoesNotExist))
+	assert.Check(t, is.Len(store.References(arm64ID), 0))
+}
@@ -20,15 +20,37 @@ type ServiceOptions struct {
 	Mirrors                        []string `json:"registry-mirrors,omitempty"`
 	InsecureRegistries             []string `json:"insecure-registries,omitempty"`
 
+	// RegistryMirrors maps registry hosts to the mirrors to pull from
+	// before pulling from the registry itself.
+	RegistryMirrors map[string][]RegistryMirror `json:"registry-host-mirrors,omitempty"`
+
 	// V2Only controls access to legacy registries.  If it is set to true via the
 	// command line flag the daemon will not attempt to contact v1 legacy registries
 	V2Only bool `json:"disable-legacy-registry,omitempty"`
 }
 
+// RegistryMirror is a mirror of a registry, with its TLS configuration.
+type RegistryMirror struct {
+	// URL is the http(s) URL of the mirror.
+	URL string `json:"url"`
+	// Insecure disables the verification of the certificate of the mirror.
+	Insecure bool `json:"insecure,omitempty"`
+	// CAFile, CertFile and KeyFile are the CA to trust, and the client
+	// certificate to use, to connect to the mirror. The certificates of
+	// the certs.d directory of the mirror are used if they are not set.
+	CAFile   string `json:"tlscacert,omitempty"`
+	CertFile string `json:"tlscert,omitempty"`
+	KeyFile  string `json:"tlskey,omitempty"`
+}
+
 // serviceConfig holds daemon configuration for the registry service.
 type serviceConfig struct {
 	registrytypes.ServiceConfig
 	V2Only bool
+
+	// registryMirrors are the mirrors of each registry, other than the
+	// mirrors of the official index, which are in Mirrors.
+	registryMirrors map[string][]RegistryMirror
 }
 
 var (
@@ -87,6 +109,9 @@ func newServiceConfig(options ServiceOptions) (*serviceConfig, error) {
 	if err := config.LoadInsecureRegistries(options.InsecureRegistries); err != nil {
 		return nil, err
 	}
+	if err := config.LoadRegistryMirrors(options.RegistryMirrors); err != nil {
+		return nil, err
+	}
 
 	return config, nil
 }
@@ -158,6 +183,63 @@ func (config *serviceConfig) LoadMirrors(mirrors []string) error {
 	return nil
 }
 
+// LoadRegistryMirrors loads the mirrors of each registry to config, after
+// removing duplicates. Returns an error if a registry or a mirror is invalid.
+func (config *serviceConfig) LoadRegistryMirrors(registryMirrors map[string][]RegistryMirror) error {
+	loaded := make(map[string][]RegistryMirror, len(registryMirrors))
+	for r, mirrors := range registryMirrors {
+		name, err := ValidateIndexName(r)
+		if err != nil {
+			return err
+		}
+		if validateNoScheme(name) != nil {
+			return fmt.Errorf("registry %s of registry mirrors should not contain '://'", r)
+		}
+		if err := validateHostPort(name); err != nil {
+			return fmt.Errorf("registry %s of registry mirrors is not valid: %v", r, err)
+		}
+		if _, exist := loaded[name]; exist {
+			return fmt.Errorf("duplicate mirrors configuration for registry %s", name)
+		}
+
+		seen := map[string]struct{}{}
+		unique := []RegistryMirror{}
+		for _, mirror := range mirrors {
+			m, err := ValidateMirror(mirror.URL)
+			if err != nil {
+				return errors.Wrapf(err, "invalid mirror of registry %s", name)
+			}
+			if (mirror.CertFile == "") != (mirror.KeyFile == "") {
+				return fmt.Errorf("invalid mirror %s of registry %s: tlscert and tlskey must be set together", m, name)
+			}
+			if _, exist := seen[m]; exist {
+				continue
+			}
+			seen[m] = struct{}{}
+			mirror.URL = m
+			unique = append(unique, mirror)
+		}
+		loaded[name] = unique
+	}
+	config.registryMirrors = loaded
+	return nil
+}
+
+// mirrorsOf returns the URLs of the mirrors of a registry.
+func (config *serviceConfig) mirrorsOf(indexName string) []string {
+	var urls []string
+	if indexName == IndexName {
+		urls = append(urls, config.Mirrors...)
+	}
+	for _, m := range config.registryMirrors[indexName] {
+		urls = append(urls, m.URL)
+	}
+	if urls == nil {
+		urls = make([]string, 0)
+	}
+	return urls
+}
+
 // LoadInsecureRegistries loads insecure registries to config
 func (config *serviceConfig) LoadInsecureRegistries(registries []string) error {
 	// Localhost is by default considered as an insecure registry
@@ -387,13 +469,18 @@ func newIndexInfo(config *serviceConfig, indexName string) (*registrytypes.Index
 
 	// Return any configured index info, first.
 	if index, ok := config.IndexConfigs[indexName]; ok {
+		if _, ok := config.registryMirrors[indexName]; ok {
+			withMirrors := *index
+			withMirrors.Mirrors = config.mirrorsOf(indexName)
+			return &withMirrors, nil
+		}
 		return index, nil
 	}
 
 	// Construct a non-configured index info.
 	index := &registrytypes.IndexInfo{
 		Name:     indexName,
-		Mirrors:  make([]string, 0),
+		Mirrors:  config.mirrorsOf(indexName),
 		Official: false,
 	}
 	index.Secure = isSecureIndex(config, indexName)
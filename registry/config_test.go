@@ -307,6 +307,39 @@ func TestNewServiceConfig(t *testing.T) {
 			},
 			"",
 		},
+		{
+			ServiceOptions{
+				RegistryMirrors: map[string][]RegistryMirror{"quay.io": {{URL: "https://quay-mirror.example.com"}}},
+			},
+			"",
+		},
+		{
+			ServiceOptions{
+				RegistryMirrors: map[string][]RegistryMirror{"quay.io": {{URL: "ftp://quay-mirror.example.com"}}},
+			},
+			`invalid mirror of registry quay.io: invalid mirror: unsupported scheme "ftp" in "ftp://quay-mirror.example.com"`,
+		},
+		{
+			ServiceOptions{
+				RegistryMirrors: map[string][]RegistryMirror{"https://quay.io": {{URL: "https://quay-mirror.example.com"}}},
+			},
+			"registry https://quay.io of registry mirrors should not contain '://'",
+		},
+		{
+			ServiceOptions{
+				RegistryMirrors: map[string][]RegistryMirror{"quay.io": {{URL: "https://quay-mirror.example.com", CertFile: "/cert.pem"}}},
+			},
+			"invalid mirror https://quay-mirror.example.com/ of registry quay.io: tlscert and tlskey must be set together",
+		},
+		{
+			ServiceOptions{
+				RegistryMirrors: map[string][]RegistryMirror{
+					"index.docker.io": {{URL: "https://hub-mirror.example.com"}},
+					"docker.io":       {{URL: "https://hub-mirror.example.com"}},
+				},
+			},
+			"duplicate mirrors configuration for registry docker.io",
+		},
 	}
 
 	for _, testCase := range testCases {
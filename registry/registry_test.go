@@ -14,6 +14,7 @@ import (
 	"github.com/docker/docker/api/types"
 	registrytypes "github.com/docker/docker/api/types/registry"
 	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
 	"gotest.tools/skip"
 )
 
@@ -701,6 +702,57 @@ func TestMirrorEndpointLookup(t *testing.T) {
 	}
 }
 
+func TestRegistryMirrorEndpointLookup(t *testing.T) {
+	cfg, err := newServiceConfig(ServiceOptions{
+		Mirrors: []string{"https://hub-mirror.example.com"},
+		RegistryMirrors: map[string][]RegistryMirror{
+			"docker.io": {{URL: "https://hub-cache.example.com"}},
+			"quay.io": {
+				{URL: "https://quay-cache.example.com"},
+				{URL: "http://quay-mirror.example.com:5000", Insecure: true},
+				{URL: "https://quay-cache.example.com/"},
+			},
+		},
+	})
+	assert.NilError(t, err)
+	s := DefaultService{config: cfg}
+
+	endpointHosts := func(endpoints []APIEndpoint) []string {
+		var hosts []string
+		for _, e := range endpoints {
+			if e.Version == APIVersion2 {
+				hosts = append(hosts, e.URL.Host)
+			}
+		}
+		return hosts
+	}
+
+	endpoints, err := s.LookupPullEndpoints("quay.io")
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(endpointHosts(endpoints), []string{"quay-cache.example.com", "quay-mirror.example.com:5000", "quay.io"}))
+	assert.Check(t, endpoints[0].Mirror)
+	assert.Check(t, !endpoints[0].TLSConfig.InsecureSkipVerify)
+	assert.Check(t, endpoints[1].TLSConfig.InsecureSkipVerify)
+	assert.Check(t, !endpoints[2].Mirror)
+
+	endpoints, err = s.LookupPushEndpoints("quay.io")
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(endpointHosts(endpoints), []string{"quay.io"}))
+
+	endpoints, err = s.LookupPullEndpoints(IndexName)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(endpointHosts(endpoints), []string{"hub-mirror.example.com", "hub-cache.example.com", DefaultV2Registry.Host}))
+
+	endpoints, err = s.LookupPullEndpoints("ghcr.io")
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(endpointHosts(endpoints), []string{"ghcr.io"}))
+
+	index, err := newIndexInfo(cfg, "quay.io")
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(index.Mirrors, []string{"https://quay-cache.example.com/", "http://quay-mirror.example.com:5000/"}))
+	assert.Check(t, is.Len(s.ServiceConfig().IndexConfigs["quay.io"].Mirrors, 2))
+}
+
 func TestPushRegistryTag(t *testing.T) {
 	r := spawnTestRegistrySession(t)
 	repoRef, err := reference.ParseNormalizedNamed(REPO)
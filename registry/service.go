@@ -13,6 +13,7 @@ import (
 	"github.com/docker/docker/api/types"
 	registrytypes "github.com/docker/docker/api/types/registry"
 	"github.com/docker/docker/errdefs"
+	"github.com/docker/go-connections/tlsconfig"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
@@ -34,6 +35,7 @@ type Service interface {
 	LoadAllowNondistributableArtifacts([]string) error
 	LoadMirrors([]string) error
 	LoadInsecureRegistries([]string) error
+	LoadRegistryMirrors(map[string][]RegistryMirror) error
 }
 
 // DefaultService is a registry service. It tracks configuration data such as a list
@@ -73,6 +75,11 @@ func (s *DefaultService) ServiceConfig() *registrytypes.ServiceConfig {
 	for key, value := range s.config.ServiceConfig.IndexConfigs {
 		servConfig.IndexConfigs[key] = value
 	}
+	for key := range s.config.registryMirrors {
+		if index, err := newIndexInfo(s.config, key); err == nil {
+			servConfig.IndexConfigs[key] = index
+		}
+	}
 
 	servConfig.Mirrors = append(servConfig.Mirrors, s.config.ServiceConfig.Mirrors...)
 
@@ -103,6 +110,14 @@ func (s *DefaultService) LoadInsecureRegistries(registries []string) error {
 	return s.config.LoadInsecureRegistries(registries)
 }
 
+// LoadRegistryMirrors loads the mirrors of each registry for Service
+func (s *DefaultService) LoadRegistryMirrors(registryMirrors map[string][]RegistryMirror) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.config.LoadRegistryMirrors(registryMirrors)
+}
+
 // Auth contacts the public registry with the provided credentials,
 // and returns OK if authentication was successful.
 // It can be used to verify the validity of a client's credentials.
@@ -280,6 +295,24 @@ func (s *DefaultService) tlsConfigForMirror(mirrorURL *url.URL) (*tls.Config, er
 	return s.tlsConfig(mirrorURL.Host)
 }
 
+// tlsConfigForRegistryMirror constructs the client TLS configuration of a
+// registry mirror, from its own settings, or from the certs.d directory of
+// the mirror host.
+func (s *DefaultService) tlsConfigForRegistryMirror(mirror RegistryMirror, mirrorURL *url.URL) (*tls.Config, error) {
+	if mirror.CAFile == "" && mirror.CertFile == "" {
+		if mirror.Insecure {
+			return newTLSConfig(mirrorURL.Host, false)
+		}
+		return s.tlsConfigForMirror(mirrorURL)
+	}
+	return tlsconfig.Client(tlsconfig.Options{
+		CAFile:             mirror.CAFile,
+		CertFile:           mirror.CertFile,
+		KeyFile:            mirror.KeyFile,
+		InsecureSkipVerify: mirror.Insecure,
+	})
+}
+
 // LookupPullEndpoints creates a list of endpoints to try to pull from, in order of preference.
 // It gives preference to v2 endpoints over v1, mirrors over the actual
 // registry, and HTTPS over plain HTTP.
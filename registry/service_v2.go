@@ -32,6 +32,11 @@ func (s *DefaultService) lookupV2Endpoints(hostname string) (endpoints []APIEndp
 				TLSConfig:    mirrorTLSConfig,
 			})
 		}
+		mirrors, err := s.lookupV2MirrorEndpoints(IndexName)
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, mirrors...)
 		// v2 registry
 		endpoints = append(endpoints, APIEndpoint{
 			URL:          DefaultV2Registry,
@@ -51,7 +56,11 @@ func (s *DefaultService) lookupV2Endpoints(hostname string) (endpoints []APIEndp
 		return nil, err
 	}
 
-	endpoints = []APIEndpoint{
+	endpoints, err = s.lookupV2MirrorEndpoints(hostname)
+	if err != nil {
+		return nil, err
+	}
+	endpoints = append(endpoints, []APIEndpoint{
 		{
 			URL: &url.URL{
 				Scheme: "https",
@@ -62,7 +71,7 @@ func (s *DefaultService) lookupV2Endpoints(hostname string) (endpoints []APIEndp
 			TrimHostname:                   true,
 			TLSConfig:                      tlsConfig,
 		},
-	}
+	}...)
 
 	if tlsConfig.InsecureSkipVerify {
 		endpoints = append(endpoints, APIEndpoint{
@@ -80,3 +89,28 @@ func (s *DefaultService) lookupV2Endpoints(hostname string) (endpoints []APIEndp
 
 	return endpoints, nil
 }
+
+// lookupV2MirrorEndpoints returns the endpoints of the mirrors configured for
+// a registry in the registry mirrors, in order.
+func (s *DefaultService) lookupV2MirrorEndpoints(indexName string) ([]APIEndpoint, error) {
+	var endpoints []APIEndpoint
+	for _, mirror := range s.config.registryMirrors[indexName] {
+		mirrorURL, err := url.Parse(mirror.URL)
+		if err != nil {
+			return nil, err
+		}
+		mirrorTLSConfig, err := s.tlsConfigForRegistryMirror(mirror, mirrorURL)
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, APIEndpoint{
+			URL: mirrorURL,
+			// guess mirrors are v2
+			Version:      APIVersion2,
+			Mirror:       true,
+			TrimHostname: true,
+			TLSConfig:    mirrorTLSConfig,
+		})
+	}
+	return endpoints, nil
+}
@@ -13,6 +13,9 @@ const (
 	backoffMultiplier = 2
 	defaultTimeout    = 100 * time.Millisecond
 	maxRestartTimeout = 1 * time.Minute
+	// if the container ran for more than resetTimeout, regardless of status
+	// and policy, the timeout is reset back to the default.
+	resetTimeout = 10 * time.Second
 )
 
 // ErrRestartCanceled is returned when the restart manager has been
@@ -23,6 +26,18 @@ var ErrRestartCanceled = errors.New("restart canceled")
 type RestartManager interface {
 	Cancel() error
 	ShouldRestart(exitCode uint32, hasBeenManuallyStopped bool, executionDuration time.Duration) (bool, chan error, error)
+	State() State
+}
+
+// State describes the current backoff state of a RestartManager.
+type State struct {
+	// Delay is the delay applied before the last (or pending) restart.
+	Delay time.Duration
+	// RecentRestarts is the number of restarts within the policy's window.
+	RecentRestarts int
+	// GaveUp is set if the container reached the policy's maximum number of
+	// restarts within the window, and will no longer be restarted.
+	GaveUp bool
 }
 
 type restartManager struct {
@@ -31,6 +46,8 @@ type restartManager struct {
 	policy       container.RestartPolicy
 	restartCount int
 	timeout      time.Duration
+	restarts     []time.Time // restart times within the policy's window
+	gaveUp       bool
 	active       bool
 	cancel       chan struct{}
 	canceled     bool
@@ -44,6 +61,7 @@ func New(policy container.RestartPolicy, restartCount int) RestartManager {
 func (rm *restartManager) SetPolicy(policy container.RestartPolicy) {
 	rm.Lock()
 	rm.policy = policy
+	rm.gaveUp = false
 	rm.Unlock()
 }
 
@@ -66,19 +84,22 @@ func (rm *restartManager) ShouldRestart(exitCode uint32, hasBeenManuallyStopped
 	if rm.active {
 		return false, nil, fmt.Errorf("invalid call on an active restart manager")
 	}
-	// if the container ran for more than 10s, regardless of status and policy reset the
-	// the timeout back to the default.
-	if executionDuration.Seconds() >= 10 {
+	if executionDuration >= durationWithDefault(rm.policy.ResetAfter, resetTimeout) {
 		rm.timeout = 0
 	}
+	maxTimeout := durationWithDefault(rm.policy.MaxDelay, maxRestartTimeout)
 	switch {
 	case rm.timeout == 0:
-		rm.timeout = defaultTimeout
-	case rm.timeout < maxRestartTimeout:
-		rm.timeout *= backoffMultiplier
+		rm.timeout = durationWithDefault(rm.policy.Delay, defaultTimeout)
+	case rm.timeout < maxTimeout:
+		multiplier := rm.policy.Multiplier
+		if multiplier == 0 {
+			multiplier = backoffMultiplier
+		}
+		rm.timeout = time.Duration(float64(rm.timeout) * multiplier)
 	}
-	if rm.timeout > maxRestartTimeout {
-		rm.timeout = maxRestartTimeout
+	if rm.timeout > maxTimeout {
+		rm.timeout = maxTimeout
 	}
 
 	var restart bool
@@ -94,6 +115,23 @@ func (rm *restartManager) ShouldRestart(exitCode uint32, hasBeenManuallyStopped
 		}
 	}
 
+	if restart && rm.policy.MaxAttempts > 0 {
+		now := time.Now()
+		recent := rm.restarts[:0]
+		for _, t := range rm.restarts {
+			if now.Sub(t) < rm.policy.Window {
+				recent = append(recent, t)
+			}
+		}
+		rm.restarts = recent
+		if len(rm.restarts) >= rm.policy.MaxAttempts {
+			rm.gaveUp = true
+			restart = false
+		} else {
+			rm.restarts = append(rm.restarts, now)
+		}
+	}
+
 	if !restart {
 		rm.active = false
 		return false, nil, nil
@@ -122,6 +160,16 @@ func (rm *restartManager) ShouldRestart(exitCode uint32, hasBeenManuallyStopped
 	return true, ch, nil
 }
 
+func (rm *restartManager) State() State {
+	rm.Lock()
+	defer rm.Unlock()
+	return State{
+		Delay:          rm.timeout,
+		RecentRestarts: len(rm.restarts),
+		GaveUp:         rm.gaveUp,
+	}
+}
+
 func (rm *restartManager) Cancel() error {
 	rm.Do(func() {
 		rm.Lock()
@@ -131,3 +179,11 @@ func (rm *restartManager) Cancel() error {
 	})
 	return nil
 }
+
+// If configuredValue is zero, use defaultValue instead.
+func durationWithDefault(configuredValue, defaultValue time.Duration) time.Duration {
+	if configuredValue == 0 {
+		return defaultValue
+	}
+	return configuredValue
+}
@@ -34,3 +34,66 @@ func TestRestartManagerTimeoutReset(t *testing.T) {
 		t.Fatalf("restart manager should have a timeout of 100 ms but has %s", rm.timeout)
 	}
 }
+
+func TestRestartManagerCustomBackoff(t *testing.T) {
+	rm := New(container.RestartPolicy{
+		Name:       "always",
+		Delay:      time.Second,
+		Multiplier: 1.5,
+		MaxDelay:   2 * time.Second,
+		ResetAfter: time.Minute,
+	}, 0).(*restartManager)
+
+	for _, expected := range []time.Duration{time.Second, 1500 * time.Millisecond, 2 * time.Second, 2 * time.Second} {
+		if _, _, err := rm.ShouldRestart(0, false, 30*time.Second); err != nil {
+			t.Fatal(err)
+		}
+		rm.active = false
+		if rm.timeout != expected {
+			t.Fatalf("restart manager should have a timeout of %s but has %s", expected, rm.timeout)
+		}
+	}
+
+	if _, _, err := rm.ShouldRestart(0, false, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if rm.timeout != time.Second {
+		t.Fatalf("restart manager should have a timeout of 1s but has %s", rm.timeout)
+	}
+}
+
+func TestRestartManagerMaxAttemptsInWindow(t *testing.T) {
+	rm := New(container.RestartPolicy{Name: "always", MaxAttempts: 2, Window: time.Minute}, 0).(*restartManager)
+
+	for i := 0; i < 2; i++ {
+		should, _, err := rm.ShouldRestart(0, false, time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !should {
+			t.Fatal("container should be restarted")
+		}
+		rm.active = false
+	}
+
+	should, _, err := rm.ShouldRestart(0, false, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if should {
+		t.Fatal("container should not be restarted")
+	}
+	if state := rm.State(); !state.GaveUp || state.RecentRestarts != 2 {
+		t.Fatalf("unexpected restart state: %+v", state)
+	}
+
+	// restarts older than the window are not counted
+	rm.restarts[0] = rm.restarts[0].Add(-time.Hour)
+	should, _, err = rm.ShouldRestart(0, false, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !should {
+		t.Fatal("container should be restarted")
+	}
+}
@@ -0,0 +1,102 @@
+package local // import "github.com/docker/docker/volume/local"
+
+import (
+	"io"
+
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/chrootarchive"
+	"github.com/docker/docker/pkg/ioutils"
+	"github.com/docker/docker/pkg/stringid"
+	"github.com/docker/docker/volume"
+	"github.com/pkg/errors"
+)
+
+// Export returns a tar archive of the data of the volume. The volume stays
+// mounted until the archive is closed.
+func (r *Root) Export(v volume.Volume) (io.ReadCloser, error) {
+	lv, err := r.localVolume(v)
+	if err != nil {
+		return nil, err
+	}
+
+	id := stringid.GenerateNonCryptoID()
+	path, err := lv.Mount(id)
+	if err != nil {
+		return nil, err
+	}
+	rdr, err := archive.TarWithOptions(path, &archive.TarOptions{Compression: archive.Uncompressed})
+	if err != nil {
+		lv.Unmount(id)
+		return nil, errdefs.System(errors.Wrapf(err, "error while archiving volume %s", lv.name))
+	}
+	return ioutils.NewReadCloserWrapper(rdr, func() error {
+		err := rdr.Close()
+		if uerr := lv.Unmount(id); err == nil {
+			err = uerr
+		}
+		return err
+	}), nil
+}
+
+// Import extracts a tar archive, which may be compressed, into the data of
+// the volume. The archive is extracted in a chroot of the volume data, so
+// that it cannot write outside of it.
+func (r *Root) Import(v volume.Volume, content io.Reader) error {
+	lv, err := r.localVolume(v)
+	if err != nil {
+		return err
+	}
+
+	id := stringid.GenerateNonCryptoID()
+	path, err := lv.Mount(id)
+	if err != nil {
+		return err
+	}
+	defer lv.Unmount(id)
+
+	if err := chrootarchive.Untar(content, path, &archive.TarOptions{}); err != nil {
+		return errdefs.InvalidParameter(errors.Wrapf(err, "error while importing volume %s", lv.name))
+	}
+	return nil
+}
+
+// Clone copies the data of the volume src into the volume dst. File
+// contents are shared with copy-on-write clones where the backing
+// filesystem supports it.
+func (r *Root) Clone(src, dst volume.Volume) error {
+	srcVolume, err := r.localVolume(src)
+	if err != nil {
+		return err
+	}
+	dstVolume, err := r.localVolume(dst)
+	if err != nil {
+		return err
+	}
+
+	id := stringid.GenerateNonCryptoID()
+	srcPath, err := srcVolume.Mount(id)
+	if err != nil {
+		return err
+	}
+	defer srcVolume.Unmount(id)
+	dstPath, err := dstVolume.Mount(id)
+	if err != nil {
+		return err
+	}
+	defer dstVolume.Unmount(id)
+
+	if err := copyData(srcPath, dstPath); err != nil {
+		return errdefs.System(errors.Wrapf(err, "error while cloning volume %s to %s", srcVolume.name, dstVolume.name))
+	}
+	return nil
+}
+
+// localVolume returns the volume v of the driver.
+func (r *Root) localVolume(v volume.Volume) (*localVolume, error) {
+	lv, ok := v.(*localVolume)
+	if !ok {
+		return nil, errdefs.System(errors.Errorf("unknown volume type %T", v))
+	}
+	return lv, nil
+}
@@ -0,0 +1,9 @@
+package local // import "github.com/docker/docker/volume/local"
+
+import "github.com/docker/docker/daemon/graphdriver/copy"
+
+// copyData copies the directory src into dst, preserving ownership, modes
+// and xattrs. Files are cloned if the backing filesystem supports reflinks.
+func copyData(src, dst string) error {
+	return copy.DirCopy(src, dst, copy.Content, true)
+}
@@ -0,0 +1,66 @@
+package local // import "github.com/docker/docker/volume/local"
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/docker/docker/pkg/idtools"
+	"github.com/docker/docker/pkg/reexec"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/skip"
+)
+
+func init() {
+	reexec.Init()
+}
+
+func TestExportImportClone(t *testing.T) {
+	skip.If(t, os.Getuid() != 0, "requires mounts")
+	rootDir, err := ioutil.TempDir("", "local-volume-test")
+	assert.NilError(t, err)
+	defer os.RemoveAll(rootDir)
+
+	r, err := New(rootDir, idtools.Identity{UID: os.Geteuid(), GID: os.Getegid()})
+	assert.NilError(t, err)
+
+	src, err := r.Create("src", nil)
+	assert.NilError(t, err)
+	assert.NilError(t, os.MkdirAll(filepath.Join(src.Path(), "dir"), 0700))
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(src.Path(), "dir", "file"), []byte("data"), 0640))
+	assert.NilError(t, os.Lchown(filepath.Join(src.Path(), "dir", "file"), 1234, 5678))
+	assert.NilError(t, os.Symlink("/etc/passwd", filepath.Join(src.Path(), "link")))
+
+	checkData := func(path string) {
+		t.Helper()
+		data, err := ioutil.ReadFile(filepath.Join(path, "dir", "file"))
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(string(data), "data"))
+		fi, err := os.Stat(filepath.Join(path, "dir", "file"))
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(fi.Mode().Perm(), os.FileMode(0640)))
+		st := fi.Sys().(*syscall.Stat_t)
+		assert.Check(t, is.Equal(st.Uid, uint32(1234)))
+		assert.Check(t, is.Equal(st.Gid, uint32(5678)))
+		link, err := os.Readlink(filepath.Join(path, "link"))
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(link, "/etc/passwd"))
+	}
+
+	archive, err := r.Export(src)
+	assert.NilError(t, err)
+	imported, err := r.Create("imported", nil)
+	assert.NilError(t, err)
+	err = r.Import(imported, archive)
+	assert.NilError(t, err)
+	assert.NilError(t, archive.Close())
+	checkData(imported.Path())
+
+	cloned, err := r.Create("cloned", nil)
+	assert.NilError(t, err)
+	assert.NilError(t, r.Clone(src, cloned))
+	checkData(cloned.Path())
+}
@@ -0,0 +1,18 @@
+// +build !linux
+
+package local // import "github.com/docker/docker/volume/local"
+
+import (
+	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/chrootarchive"
+)
+
+// copyData copies the directory src into dst by archiving it.
+func copyData(src, dst string) error {
+	rdr, err := archive.TarWithOptions(src, &archive.TarOptions{Compression: archive.Uncompressed})
+	if err != nil {
+		return err
+	}
+	defer rdr.Close()
+	return chrootarchive.UntarUncompressed(rdr, dst, &archive.TarOptions{})
+}
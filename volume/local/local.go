@@ -19,6 +19,7 @@ import (
 	"github.com/docker/docker/pkg/mount"
 	"github.com/docker/docker/volume"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 )
 
 // VolumeDataPathName is the name of the directory where the volume data is stored.
@@ -59,6 +60,7 @@ func New(scope string, rootIdentity idtools.Identity) (*Root, error) {
 		volumes:      make(map[string]*localVolume),
 		rootIdentity: rootIdentity,
 	}
+	setupQuota(r)
 
 	dirs, err := ioutil.ReadDir(rootDirectory)
 	if err != nil {
@@ -75,6 +77,7 @@ func New(scope string, rootIdentity idtools.Identity) (*Root, error) {
 			driverName: r.Name(),
 			name:       name,
 			path:       r.DataPath(name),
+			quota:      &r.driverQuota,
 		}
 		r.volumes[name] = v
 		optsFilePath := filepath.Join(rootDirectory, name, "opts.json")
@@ -90,7 +93,9 @@ func New(scope string, rootIdentity idtools.Identity) (*Root, error) {
 			}
 
 			// unmount anything that may still be mounted (for example, from an unclean shutdown)
-			mount.Unmount(v.path)
+			if v.opts.needsMount() {
+				mount.Unmount(v.path)
+			}
 		}
 	}
 
@@ -106,6 +111,7 @@ type Root struct {
 	path         string
 	volumes      map[string]*localVolume
 	rootIdentity idtools.Identity
+	driverQuota
 }
 
 // List lists all the volumes
@@ -161,12 +167,18 @@ func (r *Root) Create(name string, opts map[string]string) (volume.Volume, error
 		driverName: r.Name(),
 		name:       name,
 		path:       path,
+		quota:      &r.driverQuota,
 	}
 
 	if len(opts) != 0 {
 		if err = setOpts(v, opts); err != nil {
 			return nil, err
 		}
+		if size := v.opts.quotaSize(); size > 0 {
+			if err = r.setQuota(filepath.Dir(path), size); err != nil {
+				return nil, err
+			}
+		}
 		var b []byte
 		b, err = json.Marshal(v.opts)
 		if err != nil {
@@ -272,6 +284,8 @@ type localVolume struct {
 	opts *optsConfig
 	// active refcounts the active mounts
 	active activeMount
+	// quota controls the quota of the volume if its size is limited
+	quota *driverQuota
 }
 
 // Name returns the name of the given Volume.
@@ -299,7 +313,7 @@ func (v *localVolume) CachedPath() string {
 func (v *localVolume) Mount(id string) (string, error) {
 	v.m.Lock()
 	defer v.m.Unlock()
-	if v.opts != nil {
+	if v.opts.needsMount() {
 		if !v.active.mounted {
 			if err := v.mount(); err != nil {
 				return "", errdefs.System(err)
@@ -321,7 +335,7 @@ func (v *localVolume) Unmount(id string) error {
 	// Essentially docker doesn't care if this fails, it will send an error, but
 	// ultimately there's nothing that can be done. If we don't decrement the count
 	// this volume can never be removed until a daemon restart occurs.
-	if v.opts != nil {
+	if v.opts.needsMount() {
 		v.active.count--
 	}
 
@@ -333,7 +347,7 @@ func (v *localVolume) Unmount(id string) error {
 }
 
 func (v *localVolume) unmount() error {
-	if v.opts != nil {
+	if v.opts.needsMount() {
 		if err := mount.Unmount(v.path); err != nil {
 			if mounted, mErr := mount.Mounted(v.path); mounted || mErr != nil {
 				return errdefs.System(err)
@@ -345,7 +359,27 @@ func (v *localVolume) unmount() error {
 }
 
 func (v *localVolume) Status() map[string]interface{} {
-	return nil
+	limit, usage, err := v.Quota()
+	if err != nil {
+		logrus.WithError(err).WithField("volume", v.name).Warn("Failed to get the disk space used by the volume")
+		return nil
+	}
+	if limit == 0 {
+		return nil
+	}
+	return map[string]interface{}{"Quota": limit, "Usage": usage}
+}
+
+// Quota returns the size limit of the volume set by the size option, and the
+// disk space it uses. The limit is zero if the size of the volume is not
+// limited.
+func (v *localVolume) Quota() (limit, usage uint64, err error) {
+	limit = v.opts.quotaSize()
+	if limit == 0 {
+		return 0, 0, nil
+	}
+	usage, err = v.quota.getUsage(filepath.Dir(v.path))
+	return limit, usage, err
 }
 
 // getAddress finds out address/hostname from options
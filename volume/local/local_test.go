@@ -9,8 +9,11 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/docker/docker/errdefs"
 	"github.com/docker/docker/pkg/idtools"
 	"github.com/docker/docker/pkg/mount"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
 	"gotest.tools/skip"
 )
 
@@ -333,3 +336,37 @@ func TestRelaodNoOpts(t *testing.T) {
 		}
 	}
 }
+
+func TestCreateWithSizeOpt(t *testing.T) {
+	skip.If(t, runtime.GOOS == "windows")
+	rootDir, err := ioutil.TempDir("", "local-volume-test-size")
+	assert.NilError(t, err)
+	defer os.RemoveAll(rootDir)
+
+	r, err := New(rootDir, idtools.Identity{UID: os.Geteuid(), GID: os.Getegid()})
+	assert.NilError(t, err)
+
+	_, err = r.Create("invalid", map[string]string{"size": "foo"})
+	assert.Check(t, errdefs.IsInvalidParameter(err), "got: %v", err)
+	_, err = r.Create("negative", map[string]string{"size": "-1"})
+	assert.Check(t, errdefs.IsInvalidParameter(err), "got: %v", err)
+	_, err = r.Create("combined", map[string]string{"size": "10m", "device": "tmpfs", "type": "tmpfs"})
+	assert.Check(t, errdefs.IsInvalidParameter(err), "got: %v", err)
+
+	vol, err := r.Create("test", map[string]string{"size": "10m"})
+	if err != nil {
+		// The backing filesystem of the test directory does not support
+		// project quotas, so the volume must not be created.
+		assert.Check(t, errdefs.IsNotImplemented(err), "got: %v", err)
+		_, err = os.Stat(filepath.Join(rootDir, "test"))
+		assert.Check(t, os.IsNotExist(err))
+		return
+	}
+	v := vol.(*localVolume)
+	assert.Check(t, !v.opts.needsMount())
+
+	limit, _, err := v.Quota()
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(uint64(10*1024*1024), limit))
+	assert.Check(t, is.Equal(uint64(10*1024*1024), v.Status()["Quota"]))
+}
@@ -16,6 +16,7 @@ import (
 
 	"github.com/docker/docker/errdefs"
 	"github.com/docker/docker/pkg/mount"
+	"github.com/docker/go-units"
 	"github.com/pkg/errors"
 )
 
@@ -26,6 +27,7 @@ var (
 		"type":   {}, // specify the filesystem type for mount, e.g. nfs
 		"o":      {}, // generic mount options
 		"device": {}, // device to mount from
+		"size":   {}, // quota size limit in bytes
 	}
 	mandatoryOpts = map[string]struct{}{
 		"device": {},
@@ -37,10 +39,25 @@ type optsConfig struct {
 	MountType   string
 	MountOpts   string
 	MountDevice string
+	Size        uint64 `json:",omitempty"`
 }
 
 func (o *optsConfig) String() string {
-	return fmt.Sprintf("type='%s' device='%s' o='%s'", o.MountType, o.MountDevice, o.MountOpts)
+	return fmt.Sprintf("type='%s' device='%s' o='%s' size='%d'", o.MountType, o.MountDevice, o.MountOpts, o.Size)
+}
+
+// needsMount returns whether the volume data is a mount of a device.
+func (o *optsConfig) needsMount() bool {
+	return o != nil && o.MountDevice != ""
+}
+
+// quotaSize returns the size limit of the volume, or zero if its size is not
+// limited.
+func (o *optsConfig) quotaSize() uint64 {
+	if o == nil {
+		return 0
+	}
+	return o.Size
 }
 
 // scopedPath verifies that the path where the volume is located
@@ -72,6 +89,16 @@ func setOpts(v *localVolume, opts map[string]string) error {
 		MountOpts:   opts["o"],
 		MountDevice: opts["device"],
 	}
+	if val, ok := opts["size"]; ok {
+		size, err := units.RAMInBytes(val)
+		if err != nil {
+			return errdefs.InvalidParameter(errors.Wrapf(err, "invalid size option %q", val))
+		}
+		if size <= 0 {
+			return errdefs.InvalidParameter(errors.Errorf("invalid size option %q: must be positive", val))
+		}
+		v.opts.Size = uint64(size)
+	}
 	return nil
 }
 
@@ -84,6 +111,12 @@ func validateOpts(opts map[string]string) error {
 			return errdefs.InvalidParameter(errors.Errorf("invalid option: %q", opt))
 		}
 	}
+	if _, ok := opts["size"]; ok {
+		if len(opts) > 1 {
+			return errdefs.InvalidParameter(errors.New("size option cannot be combined with mount options"))
+		}
+		return nil
+	}
 	for opt := range mandatoryOpts {
 		if _, ok := opts[opt]; !ok {
 			return errdefs.InvalidParameter(errors.Errorf("missing required option: %q", opt))
@@ -16,6 +16,17 @@ import (
 
 type optsConfig struct{}
 
+// needsMount returns whether the volume data is a mount of a device.
+func (o *optsConfig) needsMount() bool {
+	return false
+}
+
+// quotaSize returns the size limit of the volume, or zero if its size is not
+// limited.
+func (o *optsConfig) quotaSize() uint64 {
+	return 0
+}
+
 // scopedPath verifies that the path where the volume is located
 // is under Docker's root and the valid local paths.
 func (r *Root) scopedPath(realPath string) bool {
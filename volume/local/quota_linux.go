@@ -0,0 +1,36 @@
+package local // import "github.com/docker/docker/volume/local"
+
+import (
+	"github.com/docker/docker/daemon/graphdriver/quota"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+type driverQuota struct {
+	quotaCtl *quota.Control
+}
+
+func setupQuota(r *Root) {
+	if quotaCtl, err := quota.NewControl(r.path); err == nil {
+		r.quotaCtl = quotaCtl
+	} else if err != quota.ErrQuotaNotSupported {
+		logrus.Warnf("Unable to setup quota for local volumes: %v", err)
+	}
+}
+
+// setQuota limits the disk space used in path to size bytes.
+func (q *driverQuota) setQuota(path string, size uint64) error {
+	if q.quotaCtl == nil {
+		return errdefs.NotImplemented(errors.New("size option not supported: the backing filesystem of the volumes does not support, or has not enabled, project quotas"))
+	}
+	return q.quotaCtl.SetQuota(path, quota.Quota{Size: size})
+}
+
+// getUsage returns the disk space used in path, which has a quota.
+func (q *driverQuota) getUsage(path string) (uint64, error) {
+	if q.quotaCtl == nil {
+		return 0, quota.ErrQuotaNotSupported
+	}
+	return q.quotaCtl.GetUsage(path)
+}
@@ -0,0 +1,23 @@
+// +build !linux
+
+package local // import "github.com/docker/docker/volume/local"
+
+import (
+	"github.com/docker/docker/daemon/graphdriver/quota"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+)
+
+type driverQuota struct {
+}
+
+func setupQuota(r *Root) {
+}
+
+func (q *driverQuota) setQuota(path string, size uint64) error {
+	return errdefs.NotImplemented(errors.New("size option not supported on this platform"))
+}
+
+func (q *driverQuota) getUsage(path string) (uint64, error) {
+	return 0, quota.ErrQuotaNotSupported
+}
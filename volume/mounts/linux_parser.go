@@ -96,6 +96,22 @@ func (p *linuxParser) validateMountConfigImpl(mnt *mount.Mount, validateBindSour
 		if len(mnt.Source) == 0 && mnt.ReadOnly {
 			return &errMountConfig{mnt, fmt.Errorf("must not set ReadOnly mode when using anonymous volumes")}
 		}
+
+		if _, err := ParsePopulateOptions(mnt.VolumeOptions); err != nil {
+			return &errMountConfig{mnt, err}
+		}
+
+		if opts := mnt.VolumeOptions; opts != nil && opts.Subpath != "" {
+			if len(mnt.Source) == 0 {
+				return &errMountConfig{mnt, fmt.Errorf("must not set Subpath when using anonymous volumes")}
+			}
+			if err := validateSubpath(opts.Subpath); err != nil {
+				return &errMountConfig{mnt, err}
+			}
+			if (opts.Populate != "" && opts.Populate != mount.PopulateNever) || opts.PopulateOwner != "" || opts.PopulateMode != "" {
+				return &errMountConfig{mnt, fmt.Errorf("must not set populate options when using Subpath")}
+			}
+		}
 	case mount.TypeTmpfs:
 		if mnt.BindOptions != nil {
 			return &errMountConfig{mnt, errExtraField("BindOptions")}
@@ -305,8 +321,11 @@ func (p *linuxParser) parseMountSpec(cfg mount.Mount, validateBindSourceExists b
 			if cfg.VolumeOptions.DriverConfig != nil {
 				mp.Driver = cfg.VolumeOptions.DriverConfig.Name
 			}
-			if cfg.VolumeOptions.NoCopy {
+			switch {
+			case cfg.VolumeOptions.NoCopy || cfg.VolumeOptions.Subpath != "" || cfg.VolumeOptions.Populate == mount.PopulateNever:
 				mp.CopyData = false
+			case cfg.VolumeOptions.Populate != "":
+				mp.CopyData = true
 			}
 		}
 	case mount.TypeBind:
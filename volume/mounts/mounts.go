@@ -9,9 +9,11 @@ import (
 	mounttypes "github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/pkg/idtools"
 	"github.com/docker/docker/pkg/stringid"
+	"github.com/docker/docker/pkg/symlink"
 	"github.com/docker/docker/volume"
 	"github.com/opencontainers/selinux/go-selinux/label"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 )
 
 // MountPoint is the intersection point between a volume and a container. It
@@ -72,6 +74,11 @@ type MountPoint struct {
 	// Specifically needed for containers which are running and calls to `docker cp`
 	// because both these actions require mounting the volumes.
 	active int
+
+	// SubpathStaging is the path where the subpath of the volume is bind
+	// mounted while the mountpoint is active. It is persisted so that the
+	// staging mount can be found again after the daemon restarts.
+	SubpathStaging string `json:",omitempty"`
 }
 
 // Cleanup frees resources used by the mountpoint
@@ -85,17 +92,28 @@ func (m *MountPoint) Cleanup() error {
 	}
 
 	m.active--
-	if m.active == 0 {
+	// the mountpoint may have been mounted by a previous daemon, in which
+	// case it was not counted as active.
+	if m.active <= 0 {
+		m.active = 0
 		m.ID = ""
+		if m.SubpathStaging != "" {
+			if err := unstageSubpath(m.SubpathStaging); err != nil {
+				return errors.Wrapf(err, "error unmounting subpath of volume %s", m.Volume.Name())
+			}
+			m.SubpathStaging = ""
+		}
 	}
 	return nil
 }
 
 // Setup sets up a mount point by either mounting the volume if it is
 // configured, or creating the source directory if supplied.
+// The subpath of the volume, if any, is bind mounted in a directory created
+// in stagingDir.
 // The, optional, checkFun parameter allows doing additional checking
 // before creating the source directory on the host.
-func (m *MountPoint) Setup(mountLabel string, rootIDs idtools.Identity, checkFun func(m *MountPoint) error) (path string, err error) {
+func (m *MountPoint) Setup(mountLabel string, rootIDs idtools.Identity, stagingDir string, checkFun func(m *MountPoint) error) (path string, err error) {
 	if m.SkipMountpointCreation {
 		return m.Source, nil
 	}
@@ -131,6 +149,24 @@ func (m *MountPoint) Setup(mountLabel string, rootIDs idtools.Identity, checkFun
 		if err != nil {
 			return "", errors.Wrapf(err, "error while mounting volume '%s'", m.Source)
 		}
+		if opts := m.Spec.VolumeOptions; opts != nil && opts.Subpath != "" {
+			if m.SubpathStaging != "" && m.active == 0 {
+				// the staging mount was left behind by a previous daemon,
+				// which may have been unmounted since.
+				if err := unstageSubpath(m.SubpathStaging); err != nil {
+					logrus.WithError(err).WithField("path", m.SubpathStaging).Warn("error removing stale subpath staging directory")
+				}
+				m.SubpathStaging = ""
+			}
+			if m.SubpathStaging == "" {
+				m.SubpathStaging, err = mountSubpath(path, opts.Subpath, stagingDir)
+				if err != nil {
+					m.Volume.Unmount(id)
+					return "", errors.Wrapf(err, "error while mounting volume '%s'", m.Source)
+				}
+			}
+			path = m.SubpathStaging
+		}
 
 		m.ID = id
 		m.active++
@@ -164,6 +200,41 @@ func (m *MountPoint) Setup(mountLabel string, rootIDs idtools.Identity, checkFun
 	return m.Source, nil
 }
 
+// mountSubpath makes the subpath of the volume mounted at root available
+// for the container, and returns the path to mount in the container.
+func mountSubpath(root, subpath, stagingDir string) (string, error) {
+	resolved, err := resolveSubpath(root, subpath)
+	if err != nil {
+		return "", err
+	}
+	return stageSubpath(root, resolved, stagingDir)
+}
+
+// resolveSubpath returns the path of subpath within the volume mounted at
+// root. Symlinks are resolved as if root was the root of the filesystem, so
+// that the returned path can't be outside of the volume.
+//
+// Note that the volume may be changed by containers using it after the path
+// is resolved, so the returned path must be staged with stageSubpath before
+// it is mounted.
+func resolveSubpath(root, subpath string) (string, error) {
+	p, err := symlink.FollowSymlinkInScope(filepath.Join(root, subpath), root)
+	if err != nil {
+		return "", errors.Wrapf(err, "error resolving subpath %q", subpath)
+	}
+	fi, err := os.Stat(p)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", errors.Errorf("subpath %q does not exist in the volume", subpath)
+		}
+		return "", errors.Wrapf(err, "error resolving subpath %q", subpath)
+	}
+	if !fi.IsDir() {
+		return "", errors.Errorf("subpath %q is not a directory", subpath)
+	}
+	return p, nil
+}
+
 // Path returns the path of a volume in a mount point.
 func (m *MountPoint) Path() string {
 	if m.Volume != nil {
@@ -0,0 +1,38 @@
+// +build !windows
+
+package mounts // import "github.com/docker/docker/volume/mounts"
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestResolveSubpath(t *testing.T) {
+	root, err := ioutil.TempDir("", "test-resolve-subpath")
+	assert.NilError(t, err)
+	defer os.RemoveAll(root)
+
+	assert.NilError(t, os.MkdirAll(filepath.Join(root, "foo", "bar"), 0755))
+	assert.NilError(t, os.Symlink("/etc", filepath.Join(root, "escape")))
+	assert.NilError(t, os.Symlink("../../..", filepath.Join(root, "foo", "up")))
+
+	p, err := resolveSubpath(root, "foo/bar")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(p, filepath.Join(root, "foo", "bar")))
+
+	// symlinks are resolved within the volume
+	p, err = resolveSubpath(root, "foo/up/foo")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(p, filepath.Join(root, "foo")))
+
+	_, err = resolveSubpath(root, "escape")
+	assert.Check(t, is.ErrorContains(err, "does not exist in the volume"))
+
+	_, err = resolveSubpath(root, "missing")
+	assert.Check(t, is.ErrorContains(err, "does not exist in the volume"))
+}
@@ -0,0 +1,128 @@
+package mounts // import "github.com/docker/docker/volume/mounts"
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+// stageSubpath bind mounts the directory resolved within the volume mounted
+// at root to a private staging directory created in stagingDir, and returns
+// the staging directory.
+//
+// The resolved path is opened one component at a time without following
+// symlinks, and the staging directory is mounted from the opened file
+// descriptor. A component swapped for a symlink by a container using the
+// volume after the path was resolved therefore makes the mount fail instead
+// of escaping the volume.
+func stageSubpath(root, resolved, stagingDir string) (string, error) {
+	rel, err := filepath.Rel(root, resolved)
+	if err != nil {
+		return "", err
+	}
+	fd, err := openInRoot(root, rel)
+	if err != nil {
+		return "", err
+	}
+	defer unix.Close(fd)
+
+	fdPath := "/proc/self/fd/" + strconv.Itoa(fd)
+	target, err := os.Readlink(fdPath)
+	if err != nil {
+		return "", errors.Wrapf(err, "error checking subpath %q", rel)
+	}
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return "", err
+	}
+	if target != realRoot && !strings.HasPrefix(target, realRoot+string(filepath.Separator)) {
+		return "", errors.Errorf("subpath %q is outside of the volume", rel)
+	}
+
+	if err := os.MkdirAll(stagingDir, 0700); err != nil {
+		return "", err
+	}
+	staging, err := ioutil.TempDir(stagingDir, "subpath-")
+	if err != nil {
+		return "", err
+	}
+	if err := unix.Mount(fdPath, staging, "", unix.MS_BIND|unix.MS_REC, ""); err != nil {
+		os.Remove(staging)
+		return "", errors.Wrapf(&os.PathError{Op: "mount", Path: staging, Err: err}, "error mounting subpath %q", rel)
+	}
+	if err := unix.Mount("", staging, "", unix.MS_PRIVATE|unix.MS_REC, ""); err != nil {
+		unstageSubpath(staging)
+		return "", errors.Wrapf(&os.PathError{Op: "mount", Path: staging, Err: err}, "error mounting subpath %q", rel)
+	}
+	return staging, nil
+}
+
+// openInRoot opens the directory rel within root, which must not contain
+// symlinks, and returns its file descriptor.
+func openInRoot(root, rel string) (int, error) {
+	fd, err := unix.Open(root, unix.O_PATH|unix.O_DIRECTORY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return -1, &os.PathError{Op: "open", Path: root, Err: err}
+	}
+	for _, name := range strings.Split(rel, string(filepath.Separator)) {
+		if name == "" || name == "." {
+			continue
+		}
+		next, err := unix.Openat(fd, name, unix.O_PATH|unix.O_DIRECTORY|unix.O_NOFOLLOW|unix.O_CLOEXEC, 0)
+		unix.Close(fd)
+		if err != nil {
+			switch err {
+			case unix.ENOENT:
+				return -1, errors.Errorf("subpath %q does not exist in the volume", rel)
+			case unix.ENOTDIR, unix.ELOOP:
+				return -1, errors.Errorf("subpath %q was changed while it was being mounted", rel)
+			}
+			return -1, errors.Wrapf(&os.PathError{Op: "openat", Path: name, Err: err}, "error opening subpath %q", rel)
+		}
+		fd = next
+	}
+	return fd, nil
+}
+
+// unstageSubpath unmounts and removes a staging directory created by
+// stageSubpath. It is not an error if the directory is no longer mounted, or
+// no longer exists.
+func unstageSubpath(staging string) error {
+	if err := unix.Unmount(staging, unix.MNT_DETACH); err != nil && err != unix.EINVAL && err != unix.ENOENT {
+		return &os.PathError{Op: "unmount", Path: staging, Err: err}
+	}
+	// the directory is only removed once it is unmounted, so that the data
+	// of the volume is never removed with it.
+	if err := os.Remove(staging); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// CleanupSubpathStaging unmounts and removes the staging directories left in
+// stagingDir by a previous daemon. It must be called before the containers
+// are restored, and before anything removes stagingDir.
+func CleanupSubpathStaging(stagingDir string) error {
+	entries, err := ioutil.ReadDir(stagingDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	var errs []string
+	for _, e := range entries {
+		if err := unstageSubpath(filepath.Join(stagingDir, e.Name())); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Errorf("error cleaning up subpath staging directories: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package mounts // import "github.com/docker/docker/volume/mounts"
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/skip"
+)
+
+func TestStageSubpath(t *testing.T) {
+	skip.If(t, os.Getuid() != 0, "requires mounts")
+
+	root, err := ioutil.TempDir("", "test-stage-subpath")
+	assert.NilError(t, err)
+	defer os.RemoveAll(root)
+	assert.NilError(t, os.MkdirAll(filepath.Join(root, "foo", "bar"), 0755))
+
+	stagingDir, err := ioutil.TempDir("", "test-stage-subpath-staging")
+	assert.NilError(t, err)
+	defer os.RemoveAll(stagingDir)
+
+	staging, err := mountSubpath(root, "foo/bar", stagingDir)
+	assert.NilError(t, err)
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(root, "foo", "bar", "file"), nil, 0644))
+	_, err = os.Stat(filepath.Join(staging, "file"))
+	assert.Check(t, err == nil, err)
+
+	assert.Check(t, is.Equal(filepath.Dir(staging), stagingDir))
+
+	assert.NilError(t, unstageSubpath(staging))
+	_, err = os.Stat(staging)
+	assert.Check(t, os.IsNotExist(err))
+	assert.NilError(t, unstageSubpath(staging))
+}
+
+func TestCleanupSubpathStaging(t *testing.T) {
+	skip.If(t, os.Getuid() != 0, "requires mounts")
+
+	root, err := ioutil.TempDir("", "test-cleanup-subpath-staging")
+	assert.NilError(t, err)
+	defer os.RemoveAll(root)
+	stagingDir, err := ioutil.TempDir("", "test-cleanup-subpath-staging-dir")
+	assert.NilError(t, err)
+	defer os.RemoveAll(stagingDir)
+	assert.NilError(t, os.MkdirAll(filepath.Join(root, "foo"), 0755))
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(root, "foo", "file"), nil, 0644))
+
+	// staging mounts left behind by a previous daemon are unmounted, and the
+	// data of the volume is kept
+	_, err = mountSubpath(root, "foo", stagingDir)
+	assert.NilError(t, err)
+	assert.NilError(t, CleanupSubpathStaging(stagingDir))
+
+	entries, err := ioutil.ReadDir(stagingDir)
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(entries, 0))
+	_, err = os.Stat(filepath.Join(root, "foo", "file"))
+	assert.Check(t, err)
+
+	assert.NilError(t, CleanupSubpathStaging(filepath.Join(stagingDir, "missing")))
+}
+
+func TestStageSubpathSwappedComponent(t *testing.T) {
+	skip.If(t, os.Getuid() != 0, "requires mounts")
+
+	root, err := ioutil.TempDir("", "test-stage-subpath-swap")
+	assert.NilError(t, err)
+	defer os.RemoveAll(root)
+	outside, err := ioutil.TempDir("", "test-stage-subpath-outside")
+	assert.NilError(t, err)
+	defer os.RemoveAll(outside)
+
+	assert.NilError(t, os.MkdirAll(filepath.Join(root, "foo", "bar"), 0755))
+	assert.NilError(t, os.MkdirAll(filepath.Join(outside, "bar"), 0755))
+
+	for _, swapped := range []string{"foo", filepath.Join("foo", "bar")} {
+		resolved, err := resolveSubpath(root, "foo/bar")
+		assert.NilError(t, err)
+
+		// a container using the volume swaps a component of the resolved
+		// path for a symlink out of the volume before it is mounted
+		p := filepath.Join(root, swapped)
+		assert.NilError(t, os.Rename(p, p+".orig"))
+		assert.NilError(t, os.Symlink(filepath.Join(outside, filepath.Base(swapped)), p))
+
+		_, err = stageSubpath(root, resolved, os.TempDir())
+		assert.Check(t, is.ErrorContains(err, "was changed while it was being mounted"), swapped)
+
+		assert.NilError(t, os.Remove(p))
+		assert.NilError(t, os.Rename(p+".orig", p))
+	}
+}
@@ -0,0 +1,18 @@
+// +build !linux
+
+package mounts // import "github.com/docker/docker/volume/mounts"
+
+// stageSubpath returns the path resolved within the volume mounted at root.
+func stageSubpath(root, resolved, stagingDir string) (string, error) {
+	return resolved, nil
+}
+
+// unstageSubpath is a no-op on this platform.
+func unstageSubpath(staging string) error {
+	return nil
+}
+
+// CleanupSubpathStaging is a no-op on this platform.
+func CleanupSubpathStaging(stagingDir string) error {
+	return nil
+}
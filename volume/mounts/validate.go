@@ -2,6 +2,8 @@ package mounts // import "github.com/docker/docker/volume/mounts"
 
 import (
 	"fmt"
+	"path"
+	"strings"
 
 	"github.com/docker/docker/api/types/mount"
 	"github.com/pkg/errors"
@@ -26,3 +28,15 @@ func errExtraField(name string) error {
 func errMissingField(name string) error {
 	return errors.Errorf("field %s must not be empty", name)
 }
+
+// validateSubpath checks that the subpath of a volume mount, using forward
+// slashes as separator, is relative and does not reach outside of the volume.
+func validateSubpath(subpath string) error {
+	if path.IsAbs(subpath) {
+		return errors.Errorf("subpath must be a relative path: %s", subpath)
+	}
+	if p := path.Clean(subpath); p == ".." || strings.HasPrefix(p, "../") {
+		return errors.Errorf("subpath must not reach outside of the volume: %s", subpath)
+	}
+	return nil
+}
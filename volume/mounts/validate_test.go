@@ -32,6 +32,13 @@ func TestValidateMount(t *testing.T) {
 		{mount.Mount{Type: mount.TypeBind, Source: testDir, Target: testDestinationPath}, nil},
 		{mount.Mount{Type: "invalid", Target: testDestinationPath}, errors.New("mount type unknown")},
 		{mount.Mount{Type: mount.TypeBind, Source: testSourcePath, Target: testDestinationPath}, errBindSourceDoesNotExist(testSourcePath)},
+
+		{mount.Mount{Type: mount.TypeVolume, Target: testDestinationPath, Source: "hello", VolumeOptions: &mount.VolumeOptions{Subpath: "foo/bar"}}, nil},
+		{mount.Mount{Type: mount.TypeVolume, Target: testDestinationPath, VolumeOptions: &mount.VolumeOptions{Subpath: "foo"}}, errors.New("must not set Subpath when using anonymous volumes")},
+		{mount.Mount{Type: mount.TypeVolume, Target: testDestinationPath, Source: "hello", VolumeOptions: &mount.VolumeOptions{Subpath: "/foo"}}, errors.New("subpath must be a relative path: /foo")},
+		{mount.Mount{Type: mount.TypeVolume, Target: testDestinationPath, Source: "hello", VolumeOptions: &mount.VolumeOptions{Subpath: "foo/../../bar"}}, errors.New("subpath must not reach outside of the volume: foo/../../bar")},
+		{mount.Mount{Type: mount.TypeVolume, Target: testDestinationPath, Source: "hello", VolumeOptions: &mount.VolumeOptions{Subpath: "foo", Populate: mount.PopulateNever}}, nil},
+		{mount.Mount{Type: mount.TypeVolume, Target: testDestinationPath, Source: "hello", VolumeOptions: &mount.VolumeOptions{Populate: "sometimes"}}, errors.New("invalid populate mode: sometimes")},
 	}
 
 	lcowCases := []struct {
@@ -1,6 +1,14 @@
 package mounts // import "github.com/docker/docker/volume/mounts"
 
-import "strings"
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/pkg/idtools"
+	"github.com/pkg/errors"
+)
 
 // {<copy mode>=isEnabled}
 var copyModes = map[string]bool{
@@ -21,3 +29,85 @@ func getCopyMode(mode string, def bool) (bool, bool) {
 	}
 	return def, false
 }
+
+// PopulateConfig is the configuration of the population of a volume with the
+// content of the image at the mount target.
+type PopulateConfig struct {
+	// Populate is when the volume is populated.
+	Populate mount.Populate
+	// Owner, if set, is the owner of the volume root and the copied files.
+	// ParsePopulateOptions returns it as container ids, which must be
+	// mapped to host ids before it is applied.
+	Owner *idtools.Identity
+	// Mode, if set, is the permission bits of the volume root.
+	Mode *os.FileMode
+}
+
+// ParsePopulateOptions parses the options of the population of a volume
+// mounted with the given options.
+func ParsePopulateOptions(opts *mount.VolumeOptions) (PopulateConfig, error) {
+	cfg := PopulateConfig{Populate: mount.PopulateIfEmpty}
+	if opts == nil {
+		return cfg, nil
+	}
+
+	switch opts.Populate {
+	case "":
+		if opts.NoCopy {
+			cfg.Populate = mount.PopulateNever
+		}
+	case mount.PopulateIfEmpty, mount.PopulateAlways, mount.PopulateNever:
+		if opts.NoCopy && opts.Populate != mount.PopulateNever {
+			return cfg, errors.Errorf("populate mode %s conflicts with NoCopy", opts.Populate)
+		}
+		cfg.Populate = opts.Populate
+	default:
+		return cfg, errors.Errorf("invalid populate mode: %s", opts.Populate)
+	}
+
+	if opts.PopulateOwner != "" {
+		owner, err := parsePopulateOwner(opts.PopulateOwner)
+		if err != nil {
+			return cfg, err
+		}
+		cfg.Owner = &owner
+	}
+	if opts.PopulateMode != "" {
+		mode, err := strconv.ParseUint(opts.PopulateMode, 8, 32)
+		if err != nil || mode > 07777 {
+			return cfg, errors.Errorf("invalid populate file mode: %s", opts.PopulateMode)
+		}
+		fileMode := os.FileMode(mode) & os.ModePerm
+		if mode&04000 != 0 {
+			fileMode |= os.ModeSetuid
+		}
+		if mode&02000 != 0 {
+			fileMode |= os.ModeSetgid
+		}
+		if mode&01000 != 0 {
+			fileMode |= os.ModeSticky
+		}
+		cfg.Mode = &fileMode
+	}
+	if cfg.Populate == mount.PopulateNever && (cfg.Owner != nil || cfg.Mode != nil) {
+		return cfg, errors.New("populate owner and mode must not be set when the volume is never populated")
+	}
+	return cfg, nil
+}
+
+// parsePopulateOwner parses an owner in the numeric "uid:gid" format.
+func parsePopulateOwner(owner string) (idtools.Identity, error) {
+	parts := strings.Split(owner, ":")
+	if len(parts) != 2 {
+		return idtools.Identity{}, errors.Errorf("invalid populate owner %q: must be in the uid:gid format", owner)
+	}
+	uid, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return idtools.Identity{}, errors.Errorf("invalid populate owner %q: invalid uid", owner)
+	}
+	gid, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return idtools.Identity{}, errors.Errorf("invalid populate owner %q: invalid gid", owner)
+	}
+	return idtools.Identity{UID: int(uid), GID: int(gid)}, nil
+}
@@ -0,0 +1,44 @@
+package mounts // import "github.com/docker/docker/volume/mounts"
+
+import (
+	"os"
+	"testing"
+
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/pkg/idtools"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestParsePopulateOptions(t *testing.T) {
+	cfg, err := ParsePopulateOptions(nil)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(cfg, PopulateConfig{Populate: mount.PopulateIfEmpty}))
+
+	cfg, err = ParsePopulateOptions(&mount.VolumeOptions{NoCopy: true})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(cfg.Populate, mount.PopulateNever))
+
+	cfg, err = ParsePopulateOptions(&mount.VolumeOptions{Populate: mount.PopulateAlways, PopulateOwner: "1000:50", PopulateMode: "2750"})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(cfg.Populate, mount.PopulateAlways))
+	assert.Check(t, is.DeepEqual(cfg.Owner, &idtools.Identity{UID: 1000, GID: 50}))
+	assert.Check(t, is.Equal(*cfg.Mode, os.ModeSetgid|0750))
+
+	invalid := []struct {
+		opts     mount.VolumeOptions
+		expected string
+	}{
+		{mount.VolumeOptions{Populate: "sometimes"}, "invalid populate mode: sometimes"},
+		{mount.VolumeOptions{Populate: mount.PopulateAlways, NoCopy: true}, "populate mode always conflicts with NoCopy"},
+		{mount.VolumeOptions{PopulateOwner: "1000"}, "must be in the uid:gid format"},
+		{mount.VolumeOptions{PopulateOwner: "root:root"}, "invalid uid"},
+		{mount.VolumeOptions{PopulateMode: "0999"}, "invalid populate file mode: 0999"},
+		{mount.VolumeOptions{PopulateMode: "17777"}, "invalid populate file mode: 17777"},
+		{mount.VolumeOptions{Populate: mount.PopulateNever, PopulateMode: "0755"}, "must not be set when the volume is never populated"},
+	}
+	for _, tc := range invalid {
+		_, err := ParsePopulateOptions(&tc.opts)
+		assert.Check(t, is.ErrorContains(err, tc.expected), "%+v", tc.opts)
+	}
+}
@@ -272,6 +272,33 @@ func (p *windowsParser) validateMountConfigReg(mnt *mount.Mount, destRegex strin
 				return &errMountConfig{mnt, err}
 			}
 		}
+
+		if opts := mnt.VolumeOptions; opts != nil && opts.Subpath != "" {
+			if len(mnt.Source) == 0 {
+				return &errMountConfig{mnt, fmt.Errorf("must not set Subpath when using anonymous volumes")}
+			}
+			if strings.Contains(opts.Subpath, ":") {
+				return &errMountConfig{mnt, fmt.Errorf("subpath must be a relative path: %s", opts.Subpath)}
+			}
+			if err := validateSubpath(strings.Replace(opts.Subpath, `\`, `/`, -1)); err != nil {
+				return &errMountConfig{mnt, err}
+			}
+		}
+
+		if _, err := ParsePopulateOptions(mnt.VolumeOptions); err != nil {
+			return &errMountConfig{mnt, err}
+		}
+		if opts := mnt.VolumeOptions; opts != nil {
+			if opts.Populate != "" && opts.Populate != mount.PopulateNever {
+				return &errMountConfig{mnt, fmt.Errorf("populate mode %s is not supported", opts.Populate)}
+			}
+			if opts.PopulateOwner != "" {
+				return &errMountConfig{mnt, errExtraField("PopulateOwner")}
+			}
+			if opts.PopulateMode != "" {
+				return &errMountConfig{mnt, errExtraField("PopulateMode")}
+			}
+		}
 	case mount.TypeNamedPipe:
 		if len(mnt.Source) == 0 {
 			return &errMountConfig{mnt, errMissingField("Source")}
@@ -395,7 +422,7 @@ func (p *windowsParser) parseMountSpec(cfg mount.Mount, destRegex string, conver
 			if cfg.VolumeOptions.DriverConfig != nil {
 				mp.Driver = cfg.VolumeOptions.DriverConfig.Name
 			}
-			if cfg.VolumeOptions.NoCopy {
+			if cfg.VolumeOptions.NoCopy || cfg.VolumeOptions.Subpath != "" {
 				mp.CopyData = false
 			}
 		}
@@ -0,0 +1,144 @@
+package service // import "github.com/docker/docker/volume/service"
+
+import (
+	"context"
+	"io"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/ioutils"
+	"github.com/docker/docker/pkg/stringid"
+	"github.com/docker/docker/volume"
+	"github.com/docker/docker/volume/service/opts"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Export returns a tar archive of the data of the volume. The volume cannot
+// be removed until the archive is closed.
+func (s *VolumesService) Export(ctx context.Context, name string) (io.ReadCloser, error) {
+	ref := "export-" + stringid.GenerateNonCryptoID()
+	v, err := s.vs.Get(ctx, name, opts.WithGetReference(ref))
+	if err != nil {
+		return nil, err
+	}
+	release := func() {
+		if err := s.vs.Release(context.Background(), v.Name(), ref); err != nil {
+			logrus.WithError(err).WithField("volume", v.Name()).Warn("Error releasing reference to volume")
+		}
+	}
+
+	d, err := s.archiveDriver(v)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	rdr, err := d.Export(unwrapVolume(v))
+	if err != nil {
+		release()
+		return nil, err
+	}
+	s.eventLogger.LogVolumeEvent(v.Name(), "export", map[string]string{"driver": v.DriverName()})
+	return ioutils.NewReadCloserWrapper(rdr, func() error {
+		defer release()
+		return rdr.Close()
+	}), nil
+}
+
+// Import creates a volume with the data of a tar archive. The volume is
+// removed if the archive cannot be imported.
+func (s *VolumesService) Import(ctx context.Context, content io.Reader, name, driverName string, createOpts ...opts.CreateOption) (*types.Volume, error) {
+	if driverName == "" {
+		driverName = volume.DefaultDriverName
+	}
+	d, err := s.vs.drivers.GetDriver(driverName)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := archiveDriver(d); err != nil {
+		return nil, err
+	}
+	return s.createFrom(ctx, name, driverName, "import", func(v volume.Volume) error {
+		d, err := s.archiveDriver(v)
+		if err != nil {
+			return err
+		}
+		return d.Import(unwrapVolume(v), content)
+	}, createOpts...)
+}
+
+// Clone creates a volume with a copy of the data of the volume name, using
+// the same driver. Driver options of the clone are not copied from the
+// volume, as they may refer to the same storage.
+func (s *VolumesService) Clone(ctx context.Context, name, cloneName string, createOpts ...opts.CreateOption) (*types.Volume, error) {
+	ref := "clone-" + stringid.GenerateNonCryptoID()
+	src, err := s.vs.Get(ctx, name, opts.WithGetReference(ref))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := s.vs.Release(context.Background(), src.Name(), ref); err != nil {
+			logrus.WithError(err).WithField("volume", src.Name()).Warn("Error releasing reference to volume")
+		}
+	}()
+
+	if _, err := s.archiveDriver(src); err != nil {
+		return nil, err
+	}
+	return s.createFrom(ctx, cloneName, src.DriverName(), "clone", func(v volume.Volume) error {
+		d, err := s.archiveDriver(v)
+		if err != nil {
+			return err
+		}
+		return d.Clone(unwrapVolume(src), unwrapVolume(v))
+	}, createOpts...)
+}
+
+// createFrom creates a new volume name, and then populates it with populate.
+// The volume is removed if it cannot be populated. Creating the volume fails if
+// it already exists, so that only a volume created by this call is removed.
+func (s *VolumesService) createFrom(ctx context.Context, name, driverName, action string, populate func(volume.Volume) error, createOpts ...opts.CreateOption) (*types.Volume, error) {
+	if name == "" {
+		name = stringid.GenerateNonCryptoID()
+	}
+
+	ref := action + "-" + stringid.GenerateNonCryptoID()
+	v, err := s.vs.Create(ctx, name, driverName, append(createOpts, opts.WithCreateReference(ref), opts.WithCreateExclusive())...)
+	if err != nil {
+		return nil, err
+	}
+	err = populate(v)
+	if rErr := s.vs.Release(context.Background(), v.Name(), ref); rErr != nil {
+		logrus.WithError(rErr).WithField("volume", v.Name()).Warn("Error releasing reference to volume")
+	}
+	if err != nil {
+		if rmErr := s.vs.Remove(context.Background(), v); rmErr != nil {
+			logrus.WithError(rmErr).WithField("volume", v.Name()).Warnf("Error removing volume after failed %s", action)
+		}
+		return nil, err
+	}
+
+	s.eventLogger.LogVolumeEvent(v.Name(), "create", map[string]string{"driver": v.DriverName()})
+	s.eventLogger.LogVolumeEvent(v.Name(), action, map[string]string{"driver": v.DriverName()})
+	apiV := volumeToAPIType(v)
+	return &apiV, nil
+}
+
+// archiveDriver returns the driver of the volume v if it is able to archive
+// volumes.
+func (s *VolumesService) archiveDriver(v volume.Volume) (volume.ArchiveDriver, error) {
+	d, err := s.vs.drivers.GetDriver(v.DriverName())
+	if err != nil {
+		return nil, err
+	}
+	return archiveDriver(d)
+}
+
+// archiveDriver returns d if it is able to archive volumes.
+func archiveDriver(d volume.Driver) (volume.ArchiveDriver, error) {
+	ad, ok := d.(volume.ArchiveDriver)
+	if !ok {
+		return nil, errdefs.NotImplemented(errors.Errorf("volume driver %s does not support exporting, importing or cloning volumes", d.Name()))
+	}
+	return ad, nil
+}
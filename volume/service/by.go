@@ -1,6 +1,8 @@
 package service // import "github.com/docker/docker/volume/service"
 
 import (
+	"time"
+
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/volume"
 )
@@ -87,3 +89,35 @@ func byLabelFilter(filter filters.Args) By {
 		return true
 	})
 }
+
+// byUnusedFor returns a `By` that filters volumes which were not used for the
+// duration of the `unused-for` filter. Volumes which were never used are
+// filtered based on their creation time instead. It returns nil if the filter
+// is not set.
+func (s *VolumeStore) byUnusedFor(filter filters.Args) (By, error) {
+	if !filter.Contains("unused-for") {
+		return nil, nil
+	}
+	values := filter.Get("unused-for")
+	if len(values) > 1 {
+		return nil, invalidFilter{"unused-for", values}
+	}
+	d, err := time.ParseDuration(values[0])
+	if err != nil || d < 0 {
+		return nil, invalidFilter{"unused-for", values[0]}
+	}
+	cutoff := time.Now().Add(-d)
+
+	return CustomFilter(func(v volume.Volume) bool {
+		lastUsed := s.lastUsed(v.Name()).at
+		if lastUsed.IsZero() {
+			createdAt, err := v.CreatedAt()
+			if err != nil || createdAt.IsZero() {
+				// the volume can't be known to be unused
+				return false
+			}
+			lastUsed = createdAt
+		}
+		return lastUsed.Before(cutoff)
+	}), nil
+}
@@ -28,6 +28,11 @@ type pathCacher interface {
 	CachedPath() string
 }
 
+// quotaVolume is implemented by volumes whose size can be limited by a quota.
+type quotaVolume interface {
+	Quota() (limit, usage uint64, err error)
+}
+
 func (s *VolumesService) volumesToAPI(ctx context.Context, volumes []volume.Volume, opts ...convertOpt) []*types.Volume {
 	var (
 		out        = make([]*types.Volume, 0, len(volumes))
@@ -50,6 +55,7 @@ func (s *VolumesService) volumesToAPI(ctx context.Context, volumes []volume.Volu
 		default:
 		}
 		apiV := volumeToAPIType(v)
+		s.setUsage(&apiV)
 
 		if cachedPath {
 			if vv, ok := v.(pathCacher); ok {
@@ -64,12 +70,25 @@ func (s *VolumesService) volumesToAPI(ctx context.Context, volumes []volume.Volu
 			if apiV.Mountpoint == "" {
 				apiV.Mountpoint = p
 			}
-			sz, err := directory.Size(ctx, p)
-			if err != nil {
-				logrus.WithError(err).WithField("volume", v.Name()).Warnf("Failed to determine size of volume")
-				sz = -1
+			usage := &types.VolumeUsageData{RefCount: int64(s.vs.CountReferences(v))}
+			if qv, ok := unwrapVolume(v).(quotaVolume); ok {
+				limit, sz, err := qv.Quota()
+				if err != nil {
+					logrus.WithError(err).WithField("volume", v.Name()).Warnf("Failed to determine quota usage of volume")
+				} else if limit > 0 {
+					usage.Quota = int64(limit)
+					usage.Size = int64(sz)
+				}
 			}
-			apiV.UsageData = &types.VolumeUsageData{Size: sz, RefCount: int64(s.vs.CountReferences(v))}
+			if usage.Quota == 0 {
+				sz, err := directory.Size(ctx, p)
+				if err != nil {
+					logrus.WithError(err).WithField("volume", v.Name()).Warnf("Failed to determine size of volume")
+					sz = -1
+				}
+				usage.Size = sz
+			}
+			apiV.UsageData = usage
 		}
 
 		out = append(out, &apiV)
@@ -77,6 +96,20 @@ func (s *VolumesService) volumesToAPI(ctx context.Context, volumes []volume.Volu
 	return out
 }
 
+// setUsage sets when the volume was last used, the last container that used
+// it, and how it was last populated.
+func (s *VolumesService) setUsage(apiV *types.Volume) {
+	usage := s.vs.lastUsed(apiV.Name)
+	if !usage.at.IsZero() {
+		apiV.LastUsedAt = usage.at.Format(time.RFC3339)
+	}
+	apiV.LastUsedBy = usage.container
+	if usage.population != nil {
+		population := *usage.population
+		apiV.Population = &population
+	}
+}
+
 func volumeToAPIType(v volume.Volume) types.Volume {
 	createdAt, _ := v.CreatedAt()
 	tv := types.Volume{
@@ -2,7 +2,9 @@ package service // import "github.com/docker/docker/volume/service"
 
 import (
 	"encoding/json"
+	"time"
 
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/errdefs"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -12,10 +14,13 @@ import (
 var volumeBucketName = []byte("volumes")
 
 type volumeMetadata struct {
-	Name    string
-	Driver  string
-	Labels  map[string]string
-	Options map[string]string
+	Name       string
+	Driver     string
+	Labels     map[string]string
+	Options    map[string]string
+	LastUsedAt time.Time               `json:",omitempty"`
+	LastUsedBy string                  `json:",omitempty"`
+	Population *types.VolumePopulation `json:",omitempty"`
 }
 
 func (s *VolumeStore) setMeta(name string, meta volumeMetadata) error {
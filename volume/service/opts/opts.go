@@ -9,6 +9,7 @@ type CreateConfig struct {
 	Options   map[string]string
 	Labels    map[string]string
 	Reference string
+	Exclusive bool
 }
 
 // WithCreateLabels creates a CreateOption which sets the labels to the
@@ -36,6 +37,15 @@ func WithCreateReference(ref string) CreateOption {
 	}
 }
 
+// WithCreateExclusive creates a CreateOption which makes the creation fail if
+// a volume with the same name already exists, instead of returning the
+// existing volume.
+func WithCreateExclusive() CreateOption {
+	return func(cfg *CreateConfig) {
+		cfg.Exclusive = true
+	}
+}
+
 // GetConfig is used with `GetOption` to set options for the volumes service's
 // `Get` implementation.
 type GetConfig struct {
@@ -87,3 +97,21 @@ func WithPurgeOnError(b bool) RemoveOption {
 		o.PurgeOnError = b
 	}
 }
+
+// MountConfig is used by `MountOption` to store config options for the
+// volumes service's `Mount` and `Unmount` implementations.
+type MountConfig struct {
+	Container string
+}
+
+// MountOption is used to pass options to the volumes service `Mount` and
+// `Unmount` implementations.
+type MountOption func(*MountConfig)
+
+// WithMountContainer indicates the ID of the container the volume is mounted
+// for, which is recorded as the last container that used the volume.
+func WithMountContainer(id string) MountOption {
+	return func(o *MountConfig) {
+		o.Container = id
+	}
+}
@@ -66,6 +66,7 @@ func (s *VolumeStore) restore() {
 			s.globalLock.Lock()
 			s.options[v.Name()] = meta.Options
 			s.labels[v.Name()] = meta.Labels
+			s.usage[v.Name()] = usageFromMeta(meta)
 			s.names[v.Name()] = v
 			s.refs[v.Name()] = make(map[string]struct{})
 			s.globalLock.Unlock()
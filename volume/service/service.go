@@ -82,6 +82,7 @@ func (s *VolumesService) Get(ctx context.Context, name string, getOpts ...opts.G
 		return nil, err
 	}
 	vol := volumeToAPIType(v)
+	s.setUsage(&vol)
 
 	var cfg opts.GetConfig
 	for _, o := range getOpts {
@@ -103,7 +104,11 @@ func (s *VolumesService) Get(ctx context.Context, name string, getOpts ...opts.G
 // s.Mount(ctx, vol, mountID)
 // s.Unmount(ctx, vol, mountID)
 // ```
-func (s *VolumesService) Mount(ctx context.Context, vol *types.Volume, ref string) (string, error) {
+//
+// The time of the mount is recorded as the last time the volume was used, and
+// the container passed with `WithMountContainer` as the last container that
+// used it.
+func (s *VolumesService) Mount(ctx context.Context, vol *types.Volume, ref string, mountOpts ...opts.MountOption) (string, error) {
 	v, err := s.vs.Get(ctx, vol.Name, opts.WithGetDriver(vol.Driver))
 	if err != nil {
 		if IsNotExist(err) {
@@ -111,7 +116,12 @@ func (s *VolumesService) Mount(ctx context.Context, vol *types.Volume, ref strin
 		}
 		return "", err
 	}
-	return v.Mount(ref)
+	p, err := v.Mount(ref)
+	if err != nil {
+		return "", err
+	}
+	s.markUsed(v.Name(), mountOpts)
+	return p, nil
 }
 
 // Unmount unmounts the volume.
@@ -120,7 +130,7 @@ func (s *VolumesService) Mount(ctx context.Context, vol *types.Volume, ref strin
 // The reference specified here should be the same reference specified during `Mount` and should be
 // unique for each mount/unmount pair.
 // See `Mount` documentation for an example.
-func (s *VolumesService) Unmount(ctx context.Context, vol *types.Volume, ref string) error {
+func (s *VolumesService) Unmount(ctx context.Context, vol *types.Volume, ref string, mountOpts ...opts.MountOption) error {
 	v, err := s.vs.Get(ctx, vol.Name, opts.WithGetDriver(vol.Driver))
 	if err != nil {
 		if IsNotExist(err) {
@@ -128,7 +138,29 @@ func (s *VolumesService) Unmount(ctx context.Context, vol *types.Volume, ref str
 		}
 		return err
 	}
-	return v.Unmount(ref)
+	if err := v.Unmount(ref); err != nil {
+		return err
+	}
+	s.markUsed(v.Name(), mountOpts)
+	return nil
+}
+
+// SetPopulation records that the volume was populated with the content of an
+// image.
+func (s *VolumesService) SetPopulation(ctx context.Context, name string, population types.VolumePopulation) error {
+	return s.vs.setPopulation(name, population)
+}
+
+// markUsed records the volume as just used. Failing to do so does not fail the
+// mount or unmount of the volume, so errors are only logged.
+func (s *VolumesService) markUsed(name string, mountOpts []opts.MountOption) {
+	var cfg opts.MountConfig
+	for _, o := range mountOpts {
+		o(&cfg)
+	}
+	if err := s.vs.markUsed(name, cfg.Container); err != nil {
+		logrus.WithError(err).WithField("volume", name).Warn("Failed to record volume usage")
+	}
 }
 
 // Release releases a volume reference
@@ -168,8 +200,24 @@ func (s *VolumesService) Remove(ctx context.Context, name string, rmOpts ...opts
 }
 
 var acceptedPruneFilters = map[string]bool{
-	"label":  true,
-	"label!": true,
+	"label":      true,
+	"label!":     true,
+	"unused-for": true,
+}
+
+// hasLocalData returns whether the data of the volume is stored on the local
+// disk, that is, the volume has no options other than a size quota.
+func hasLocalData(v volume.Volume) bool {
+	dv, ok := v.(volume.DetailedVolume)
+	if !ok {
+		return false
+	}
+	for opt := range dv.Options() {
+		if opt != "size" {
+			return false
+		}
+	}
+	return true
 }
 
 var acceptedListFilters = map[string]bool{
@@ -182,11 +230,10 @@ var acceptedListFilters = map[string]bool{
 // LocalVolumesSize gets all local volumes and fetches their size on disk
 // Note that this intentionally skips volumes which have mount options. Typically
 // volumes with mount options are not really local even if they are using the
-// local driver.
+// local driver. Volumes with a size quota report their usage against the quota.
 func (s *VolumesService) LocalVolumesSize(ctx context.Context) ([]*types.Volume, error) {
 	ls, _, err := s.vs.Find(ctx, And(ByDriver(volume.DefaultDriverName), CustomFilter(func(v volume.Volume) bool {
-		dv, ok := v.(volume.DetailedVolume)
-		return ok && len(dv.Options()) == 0
+		return hasLocalData(v)
 	})))
 	if err != nil {
 		return nil, err
@@ -197,6 +244,9 @@ func (s *VolumesService) LocalVolumesSize(ctx context.Context) ([]*types.Volume,
 // Prune removes (local) volumes which match the past in filter arguments.
 // Note that this intentionally skips volumes with mount options as there would
 // be no space reclaimed in this case.
+// The `unused-for` filter only matches volumes which were not mounted or
+// unmounted for the given duration, or, if they were never used, which were
+// created before that.
 func (s *VolumesService) Prune(ctx context.Context, filter filters.Args) (*types.VolumesPruneReport, error) {
 	if !atomic.CompareAndSwapInt32(&s.pruneRunning, 0, 1) {
 		return nil, errdefs.Conflict(errors.New("a prune operation is already running"))
@@ -207,9 +257,12 @@ func (s *VolumesService) Prune(ctx context.Context, filter filters.Args) (*types
 	if err != nil {
 		return nil, err
 	}
-	ls, _, err := s.vs.Find(ctx, And(ByDriver(volume.DefaultDriverName), ByReferenced(false), by, CustomFilter(func(v volume.Volume) bool {
-		dv, ok := v.(volume.DetailedVolume)
-		return ok && len(dv.Options()) == 0
+	unusedBy, err := s.vs.byUnusedFor(filter)
+	if err != nil {
+		return nil, err
+	}
+	ls, _, err := s.vs.Find(ctx, And(ByDriver(volume.DefaultDriverName), ByReferenced(false), by, unusedBy, CustomFilter(func(v volume.Volume) bool {
+		return hasLocalData(v)
 	})))
 	if err != nil {
 		return nil, err
@@ -5,9 +5,12 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/docker/docker/errdefs"
 	"github.com/docker/docker/pkg/idtools"
+	"github.com/docker/docker/pkg/reexec"
 	"github.com/docker/docker/volume"
 	volumedrivers "github.com/docker/docker/volume/drivers"
 	"github.com/docker/docker/volume/local"
@@ -17,6 +20,10 @@ import (
 	is "gotest.tools/assert/cmp"
 )
 
+func init() {
+	reexec.Init()
+}
+
 func TestLocalVolumeSize(t *testing.T) {
 	t.Parallel()
 
@@ -64,3 +71,68 @@ func TestLocalVolumeSize(t *testing.T) {
 		}
 	}
 }
+
+func TestServiceExportImportClone(t *testing.T) {
+	t.Parallel()
+
+	ds := volumedrivers.NewStore(nil)
+	dir, err := ioutil.TempDir("", t.Name())
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	l, err := local.New(dir, idtools.Identity{UID: os.Getuid(), GID: os.Getegid()})
+	assert.NilError(t, err)
+	assert.Assert(t, ds.Register(l, volume.DefaultDriverName))
+	assert.Assert(t, ds.Register(testutils.NewFakeDriver("fake"), "fake"))
+
+	service, cleanup := newTestService(t, ds)
+	defer cleanup()
+
+	ctx := context.Background()
+	src, err := service.Create(ctx, "src", volume.DefaultDriverName)
+	assert.NilError(t, err)
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(src.Mountpoint, "data"), []byte("data"), 0644))
+
+	clone, err := service.Clone(ctx, "src", "clone", opts.WithCreateLabels(map[string]string{"cloned": "true"}))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(clone.Labels["cloned"], "true"))
+	data, err := ioutil.ReadFile(filepath.Join(clone.Mountpoint, "data"))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(data), "data"))
+
+	_, err = service.Clone(ctx, "src", "clone")
+	assert.Check(t, errdefs.IsConflict(err))
+
+	// a failed import does not remove an existing volume
+	_, err = service.Import(ctx, strings.NewReader("not a tar archive"), "clone", "")
+	assert.Check(t, errdefs.IsConflict(err))
+	_, err = service.Get(ctx, "clone")
+	assert.Check(t, err)
+
+	// the volume cannot be removed while it is exported
+	archive, err := service.Export(ctx, "src")
+	assert.NilError(t, err)
+	assert.Check(t, errdefs.IsConflict(service.Remove(ctx, "src")))
+	imported, err := service.Import(ctx, archive, "imported", "")
+	assert.NilError(t, err)
+	assert.NilError(t, archive.Close())
+	assert.NilError(t, service.Remove(ctx, "src"))
+	data, err = ioutil.ReadFile(filepath.Join(imported.Mountpoint, "data"))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(data), "data"))
+
+	// the volume is removed if the archive cannot be imported
+	_, err = service.Import(ctx, strings.NewReader("not a tar archive"), "invalid", "")
+	assert.Check(t, is.ErrorContains(err, ""))
+	_, err = service.Get(ctx, "invalid")
+	assert.Check(t, IsNotExist(err))
+
+	_, err = service.Create(ctx, "fake-volume", "fake")
+	assert.NilError(t, err)
+	_, err = service.Clone(ctx, "fake-volume", "fake-clone")
+	assert.Check(t, errdefs.IsNotImplemented(err))
+	_, err = service.Export(ctx, "fake-volume")
+	assert.Check(t, errdefs.IsNotImplemented(err))
+	_, err = service.Import(ctx, strings.NewReader(""), "fake-import", "fake")
+	assert.Check(t, errdefs.IsNotImplemented(err))
+}
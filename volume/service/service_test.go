@@ -5,7 +5,9 @@ import (
 	"io/ioutil"
 	"os"
 	"testing"
+	"time"
 
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/errdefs"
 	"github.com/docker/docker/volume"
@@ -233,6 +235,70 @@ func TestServicePrune(t *testing.T) {
 	assert.Assert(t, is.Equal(pr.VolumesDeleted[0], "test"))
 }
 
+func TestServiceVolumeUsage(t *testing.T) {
+	t.Parallel()
+
+	ds := volumedrivers.NewStore(nil)
+	assert.Assert(t, ds.Register(testutils.NewFakeDriver(volume.DefaultDriverName), volume.DefaultDriverName))
+
+	dir, err := ioutil.TempDir("", t.Name())
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	store, err := NewStore(dir, ds)
+	assert.NilError(t, err)
+	service := &VolumesService{vs: store, eventLogger: dummyEventLogger{}}
+	ctx := context.Background()
+
+	v, err := service.Create(ctx, "test", volume.DefaultDriverName)
+	assert.NilError(t, err)
+	_, err = service.Create(ctx, "unused", volume.DefaultDriverName)
+	assert.NilError(t, err)
+
+	v, err = service.Get(ctx, "test")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(v.LastUsedAt, ""))
+	assert.Check(t, is.Equal(v.LastUsedBy, ""))
+
+	_, err = service.Mount(ctx, v, "ref", opts.WithMountContainer("container"))
+	assert.NilError(t, err)
+	assert.NilError(t, service.Unmount(ctx, v, "ref"))
+
+	v, err = service.Get(ctx, "test")
+	assert.NilError(t, err)
+	assert.Check(t, v.LastUsedAt != "")
+	assert.Check(t, is.Equal(v.LastUsedBy, "container"))
+
+	population := types.VolumePopulation{At: v.LastUsedAt, Container: "container", Copied: true, Image: "image", Populate: "always"}
+	assert.NilError(t, service.SetPopulation(ctx, "test", population))
+
+	// the usage is persisted across restarts
+	assert.NilError(t, service.Shutdown())
+	store, err = NewStore(dir, ds)
+	assert.NilError(t, err)
+	service = &VolumesService{vs: store, eventLogger: dummyEventLogger{}}
+	defer service.Shutdown()
+
+	ls, _, err := service.List(ctx, filters.NewArgs(filters.Arg("name", "test")))
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(ls, 1))
+	assert.Check(t, is.Equal(ls[0].LastUsedAt, v.LastUsedAt))
+	assert.Check(t, is.Equal(ls[0].LastUsedBy, "container"))
+	assert.Check(t, is.DeepEqual(ls[0].Population, &population))
+
+	_, err = service.Prune(ctx, filters.NewArgs(filters.Arg("unused-for", "banana")))
+	assert.Check(t, errdefs.IsInvalidParameter(err), err)
+
+	pr, err := service.Prune(ctx, filters.NewArgs(filters.Arg("unused-for", "1h")))
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(pr.VolumesDeleted, 0))
+
+	store.usage["test"] = volumeUsage{at: time.Now().Add(-2 * time.Hour), container: "container"}
+	pr, err = service.Prune(ctx, filters.NewArgs(filters.Arg("unused-for", "1h")))
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(pr.VolumesDeleted, []string{"test"}))
+}
+
 func newTestService(t *testing.T, ds *volumedrivers.Store) (*VolumesService, func()) {
 	t.Helper()
 
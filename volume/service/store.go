@@ -12,6 +12,7 @@ import (
 
 	"github.com/pkg/errors"
 
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/errdefs"
 	"github.com/docker/docker/pkg/locker"
 	"github.com/docker/docker/volume"
@@ -77,6 +78,7 @@ func NewStore(rootPath string, drivers *drivers.Store) (*VolumeStore, error) {
 		refs:    make(map[string]map[string]struct{}),
 		labels:  make(map[string]map[string]string),
 		options: make(map[string]map[string]string),
+		usage:   make(map[string]volumeUsage),
 		drivers: drivers,
 	}
 
@@ -179,6 +181,7 @@ func (s *VolumeStore) purge(ctx context.Context, name string) error {
 	delete(s.refs, name)
 	delete(s.labels, name)
 	delete(s.options, name)
+	delete(s.usage, name)
 	return nil
 }
 
@@ -199,7 +202,21 @@ type VolumeStore struct {
 	labels map[string]map[string]string
 	// options stores volume options for each volume
 	options map[string]map[string]string
-	db      *bolt.DB
+	// usage stores when each volume was last used, and by which container
+	usage map[string]volumeUsage
+	db    *bolt.DB
+}
+
+// volumeUsage records when a volume was last mounted or unmounted, the last
+// container that used it, and how it was last populated.
+type volumeUsage struct {
+	at         time.Time
+	container  string
+	population *types.VolumePopulation
+}
+
+func usageFromMeta(meta volumeMetadata) volumeUsage {
+	return volumeUsage{at: meta.LastUsedAt, container: meta.LastUsedBy, population: meta.Population}
 }
 
 func filterByDriver(names []string) filterFunc {
@@ -465,6 +482,14 @@ func (s *VolumeStore) Create(ctx context.Context, name, driverName string, creat
 	default:
 	}
 
+	if cfg.Exclusive {
+		if _, err := s.getVolume(ctx, name, ""); err == nil {
+			return nil, &OpErr{Err: errVolumeExists, Name: name, Op: "create"}
+		} else if !IsNotExist(err) {
+			return nil, &OpErr{Err: err, Name: name, Op: "create"}
+		}
+	}
+
 	v, err := s.create(ctx, name, driverName, cfg.Options, cfg.Labels)
 	if err != nil {
 		if _, ok := err.(*OpErr); ok {
@@ -608,13 +633,17 @@ func (s *VolumeStore) create(ctx context.Context, name, driverName string, opts,
 	s.labels[name] = labels
 	s.options[name] = opts
 	s.refs[name] = make(map[string]struct{})
+	usage := s.usage[name]
 	s.globalLock.Unlock()
 
 	metadata := volumeMetadata{
-		Name:    name,
-		Driver:  vd.Name(),
-		Labels:  labels,
-		Options: opts,
+		Name:       name,
+		Driver:     vd.Name(),
+		Labels:     labels,
+		Options:    opts,
+		LastUsedAt: usage.at,
+		LastUsedBy: usage.container,
+		Population: usage.population,
 	}
 
 	if err := s.setMeta(name, metadata); err != nil {
@@ -831,6 +860,67 @@ func (s *VolumeStore) Release(ctx context.Context, name string, ref string) erro
 	return nil
 }
 
+// markUsed records that the volume was just mounted or unmounted for the given
+// container. The last container that used the volume is left unchanged if the
+// container is empty.
+func (s *VolumeStore) markUsed(name, container string) error {
+	err := s.updateUsage(name, func(meta *volumeMetadata) {
+		meta.LastUsedAt = time.Now().UTC()
+		if container != "" {
+			meta.LastUsedBy = container
+		}
+	})
+	return errors.Wrapf(err, "error recording usage of volume %s", name)
+}
+
+// setPopulation records how the volume was last populated with the content of
+// an image.
+func (s *VolumeStore) setPopulation(name string, population types.VolumePopulation) error {
+	err := s.updateUsage(name, func(meta *volumeMetadata) {
+		meta.Population = &population
+	})
+	return errors.Wrapf(err, "error recording population of volume %s", name)
+}
+
+// updateUsage updates the usage recorded in the metadata of the volume with fn.
+func (s *VolumeStore) updateUsage(name string, fn func(*volumeMetadata)) error {
+	name = normalizeVolumeName(name)
+	s.locks.Lock(name)
+	defer s.locks.Unlock(name)
+
+	var usage volumeUsage
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		var meta volumeMetadata
+		if err := getMeta(tx, name, &meta); err != nil {
+			return err
+		}
+		if meta.Name == "" {
+			meta.Name = name
+		}
+		fn(&meta)
+		usage = usageFromMeta(meta)
+		return setMeta(tx, name, meta)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.globalLock.Lock()
+	s.usage[name] = usage
+	s.globalLock.Unlock()
+	return nil
+}
+
+// lastUsed returns when the volume was last mounted or unmounted, and the last
+// container that used it.
+func (s *VolumeStore) lastUsed(name string) volumeUsage {
+	name = normalizeVolumeName(name)
+
+	s.globalLock.RLock()
+	defer s.globalLock.RUnlock()
+	return s.usage[name]
+}
+
 // CountReferences gives a count of all references for a given volume.
 func (s *VolumeStore) CountReferences(v volume.Volume) int {
 	name := normalizeVolumeName(v.Name())
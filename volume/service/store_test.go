@@ -10,6 +10,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/docker/docker/errdefs"
 	"github.com/docker/docker/volume"
 	volumedrivers "github.com/docker/docker/volume/drivers"
 	"github.com/docker/docker/volume/service/opts"
@@ -49,6 +50,23 @@ func TestCreate(t *testing.T) {
 	}
 }
 
+func TestCreateExclusive(t *testing.T) {
+	t.Parallel()
+
+	s, cleanup := setupTest(t)
+	defer cleanup()
+	s.drivers.Register(volumetestutils.NewFakeDriver("fake"), "fake")
+
+	ctx := context.Background()
+	_, err := s.Create(ctx, "fake1", "fake", opts.WithCreateExclusive())
+	assert.NilError(t, err)
+
+	_, err = s.Create(ctx, "fake1", "fake", opts.WithCreateExclusive())
+	assert.Check(t, errdefs.IsConflict(err), err)
+	_, err = s.Create(ctx, "fake1", "fake")
+	assert.Check(t, err)
+}
+
 func TestRemove(t *testing.T) {
 	t.Parallel()
 
@@ -1,6 +1,7 @@
 package volume // import "github.com/docker/docker/volume"
 
 import (
+	"io"
 	"time"
 )
 
@@ -32,6 +33,19 @@ type Driver interface {
 	Scope() string
 }
 
+// ArchiveDriver is implemented by drivers able to archive the data of their
+// volumes, to export, import and clone them. It is an optional capability of
+// drivers.
+type ArchiveDriver interface {
+	Driver
+	// Export returns a tar archive of the data of the volume.
+	Export(vol Volume) (io.ReadCloser, error)
+	// Import extracts a tar archive into the data of the volume.
+	Import(vol Volume, content io.Reader) error
+	// Clone copies the data of the volume src into the volume dst.
+	Clone(src, dst Volume) error
+}
+
 // Capability defines a set of capabilities that a driver is able to handle.
 type Capability struct {
 	// Scope is the scope of the driver, `global` or `local`